@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/Javier-Godon/reports-rendering-go/mockprovider"
+)
+
+func main() {
+	var opts mockprovider.Options
+	address := flag.String("address", "localhost:50051", "address to listen on")
+	errorCode := flag.String("error-code", "UNAVAILABLE", "gRPC status code of injected failures")
+	flag.Int64Var(&opts.Seed, "seed", 1, "seed of the synthetic data")
+	flag.IntVar(&opts.CPUs, "cpus", 8, "number of synthetic CPUs")
+	flag.StringVar(&opts.FixtureDir, "fixtures", "", "directory with JSON/CSV fixtures to replay instead of synthetic data")
+	flag.DurationVar(&opts.Latency, "latency", 0, "delay added to every response")
+	flag.Float64Var(&opts.ErrorRate, "error-rate", 0, "probability (0-1) that a call fails")
+	flag.StringVar(&opts.Version, "version", "mock", "provider version reported to clients")
+	flag.Parse()
+
+	if err := opts.ErrorCode.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(*errorCode)))); err != nil {
+		log.Fatalf("invalid error code %q: %v", *errorCode, err)
+	}
+	if opts.ErrorCode == codes.OK {
+		log.Fatal("error-code must not be OK")
+	}
+
+	server, err := mockprovider.New(opts)
+	if err != nil {
+		log.Fatal("cannot create mock provider: ", err)
+	}
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		log.Fatal("cannot listen: ", err)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		server.Stop()
+	}()
+
+	log.Printf("mock provider listening on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		log.Fatal("mock provider stopped: ", err)
+	}
+}
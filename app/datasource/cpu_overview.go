@@ -0,0 +1,39 @@
+package datasource
+
+// CpuUsageBreakdown is the system and user usage of one CPU. A CPU missing from one of the datasets
+// has no usage for it.
+type CpuUsageBreakdown struct {
+	CPU    string
+	System *CpuUsage
+	User   *CpuUsage
+}
+
+// JoinCpuUsage joins the system and user usage by CPU: the CPUs of system in order, then the CPUs
+// only found in user.
+func JoinCpuUsage(system []CpuUsage, user []CpuUsage) []CpuUsageBreakdown {
+	joined := make([]CpuUsageBreakdown, 0, max(len(system), len(user)))
+	index := make(map[string]int, len(system))
+	for i := range system {
+		index[system[i].CPU] = len(joined)
+		joined = append(joined, CpuUsageBreakdown{CPU: system[i].CPU, System: &system[i]})
+	}
+	for i := range user {
+		if j, ok := index[user[i].CPU]; ok {
+			joined[j].User = &user[i]
+			continue
+		}
+		index[user[i].CPU] = len(joined)
+		joined = append(joined, CpuUsageBreakdown{CPU: user[i].CPU, User: &user[i]})
+	}
+	return joined
+}
+
+// TotalAvgUsage returns the average usage of the CPU, system and user together, and false when one
+// of them is missing. Only the averages add up: the system and user extremes may have happened at
+// different times, so their sum is not an extreme of the total.
+func (usage CpuUsageBreakdown) TotalAvgUsage() (float64, bool) {
+	if usage.System == nil || usage.User == nil {
+		return 0, false
+	}
+	return usage.System.AvgUsage + usage.User.AvgUsage, true
+}
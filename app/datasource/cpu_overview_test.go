@@ -0,0 +1,25 @@
+package datasource
+
+import "testing"
+
+func TestJoinCpuUsageAddsUpTheAverages(t *testing.T) {
+	system := []CpuUsage{{CPU: "cpu0", AvgUsage: 0.25, MaxUsage: 0.9, MinUsage: 0.1}, {CPU: "cpu1", AvgUsage: 0.5}}
+	user := []CpuUsage{{CPU: "cpu0", AvgUsage: 0.5, MaxUsage: 0.95, MinUsage: 0.2}, {CPU: "cpu2", AvgUsage: 0.1}}
+
+	joined := JoinCpuUsage(system, user)
+	var cpus []string
+	for _, usage := range joined {
+		cpus = append(cpus, usage.CPU)
+	}
+	if len(cpus) != 3 || cpus[0] != "cpu0" || cpus[1] != "cpu1" || cpus[2] != "cpu2" {
+		t.Fatalf("JoinCpuUsage() CPUs = %q, want cpu0, cpu1, cpu2", cpus)
+	}
+	if total, ok := joined[0].TotalAvgUsage(); !ok || total != 0.75 {
+		t.Errorf("cpu0 TotalAvgUsage() = %v, %v, want 0.75, true", total, ok)
+	}
+	for _, usage := range joined[1:] {
+		if total, ok := usage.TotalAvgUsage(); ok {
+			t.Errorf("%s TotalAvgUsage() = %v, want none without both datasets", usage.CPU, total)
+		}
+	}
+}
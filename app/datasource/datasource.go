@@ -0,0 +1,91 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+)
+
+// UsageKind selects which CPU usage dataset is requested.
+type UsageKind string
+
+const (
+	CpuSystem UsageKind = "system"
+	CpuUser   UsageKind = "user"
+)
+
+const (
+	TypeGRPC   = "grpc"
+	TypeFile   = "file"
+	TypeMemory = "memory"
+)
+
+// CpuUsage holds the aggregated usage of a single CPU over the requested period.
+type CpuUsage struct {
+	CPU      string  `json:"cpu"`
+	AvgUsage float64 `json:"avg_usage"`
+	MaxUsage float64 `json:"max_usage"`
+	MinUsage float64 `json:"min_usage"`
+}
+
+// DataSource provides the data the reports are rendered from, independently of where it comes from.
+type DataSource interface {
+	CpuUsage(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64) ([]CpuUsage, error)
+	// CpuUsageTimeline returns the usage of every CPU bucketed by step over the period.
+	CpuUsageTimeline(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64, step time.Duration) ([]CpuUsageTimeline, error)
+	MemoryUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]MemoryUsage, error)
+	DiskUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]DiskUsage, error)
+	NetworkUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]NetworkUsage, error)
+	// Provenance identifies where the data comes from, for the report headers.
+	Provenance() Provenance
+	Close() error
+}
+
+// Provenance identifies the data provider of a data source.
+type Provenance struct {
+	Address string
+	// Version is the provider version, empty when unknown.
+	Version string
+}
+
+// UsesGRPC reports whether the configured data source reads from the gRPC data provider.
+func UsesGRPC(cfg *framework.Cfg) bool {
+	return cfg.DataSource.TYPE == "" || cfg.DataSource.TYPE == TypeGRPC
+}
+
+// NewFromConfig builds the data source selected by the data-source section of the application config.
+// pool is only used, and must only be non-nil, when UsesGRPC(cfg) is true.
+func NewFromConfig(cfg *framework.Cfg, pool *proto.GRPCClientPool) (DataSource, error) {
+	switch cfg.DataSource.TYPE {
+	case "", TypeGRPC:
+		if pool == nil {
+			return nil, fmt.Errorf("the %s data source needs a gRPC client pool", TypeGRPC)
+		}
+		provider := cfg.DataProvider
+		return NewGRPCDataSource(pool, GRPCOptions{
+			Timeout: provider.TIMEOUT,
+			Retries: provider.RETRIES,
+			Backoff: provider.BACKOFF,
+		}), nil
+	case TypeFile:
+		return NewFileDataSource(cfg.DataSource.PATH), nil
+	case TypeMemory:
+		source := NewMemoryDataSource()
+		if cfg.DataSource.PATH != "" {
+			if err := source.LoadFrom(NewFileDataSource(cfg.DataSource.PATH)); err != nil {
+				return nil, err
+			}
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("unknown data source type %q", cfg.DataSource.TYPE)
+	}
+}
+
+// Kinds lists every CPU usage kind, in the order the reports render them.
+func Kinds() []UsageKind {
+	return []UsageKind{CpuSystem, CpuUser}
+}
@@ -0,0 +1,176 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+)
+
+// FailurePolicy decides what happens to a report when some of its datasets cannot be fetched.
+type FailurePolicy string
+
+const (
+	// FailReport fails the whole report as soon as one dataset fails, cancelling the other fetches.
+	FailReport FailurePolicy = "fail"
+	// RenderPlaceholder renders the datasets that were fetched and a "data unavailable" placeholder
+	// for the others. The report only fails when every dataset failed.
+	RenderPlaceholder FailurePolicy = "placeholder"
+)
+
+// ParseFailurePolicy validates a configured policy, defaulting to FailReport when empty.
+func ParseFailurePolicy(value string) (FailurePolicy, error) {
+	switch FailurePolicy(value) {
+	case "", FailReport:
+		return FailReport, nil
+	case RenderPlaceholder:
+		return RenderPlaceholder, nil
+	default:
+		return "", fmt.Errorf("unknown partial failure policy %q", value)
+	}
+}
+
+// UnavailableReason is the reason shown in the report for a dataset that could not be fetched. The
+// error itself, which may name server paths or provider internals, only goes to the log.
+const UnavailableReason = "The data provider did not return this dataset."
+
+// Result is the outcome of fetching one dataset. Err is only set under RenderPlaceholder.
+type Result[T any] struct {
+	Data T
+	Err  error
+}
+
+// Unavailable returns the reason to show in place of the dataset when it could not be fetched, or ""
+// when it was.
+func (result Result[T]) Unavailable() string {
+	if result.Err == nil {
+		return ""
+	}
+	return UnavailableReason
+}
+
+// CpuUsageResult is the outcome of fetching one CPU usage dataset.
+type CpuUsageResult = Result[[]CpuUsage]
+
+// CpuUsageTimelineResult is the outcome of fetching one CPU usage timeline.
+type CpuUsageTimelineResult = Result[[]CpuUsageTimeline]
+
+// FetchCpuUsages fetches the CPU usage of every kind concurrently, applying policy to failures.
+func FetchCpuUsages(ctx context.Context, source DataSource, dateFrom int64, dateTo int64, policy FailurePolicy, kinds ...UsageKind) (map[UsageKind]CpuUsageResult, error) {
+	return FetchEach(ctx, policy, kinds, func(ctx context.Context, kind UsageKind) ([]CpuUsage, error) {
+		return source.CpuUsage(ctx, kind, dateFrom, dateTo)
+	})
+}
+
+// FetchCpuUsageTimelines fetches the CPU usage timeline of every kind concurrently, applying policy to failures.
+func FetchCpuUsageTimelines(ctx context.Context, source DataSource, dateFrom int64, dateTo int64, step time.Duration, policy FailurePolicy, kinds ...UsageKind) (map[UsageKind]CpuUsageTimelineResult, error) {
+	return FetchEach(ctx, policy, kinds, func(ctx context.Context, kind UsageKind) ([]CpuUsageTimeline, error) {
+		return source.CpuUsageTimeline(ctx, kind, dateFrom, dateTo, step)
+	})
+}
+
+// HostUsage is the outcome of fetching the memory, disk and network datasets of a period.
+type HostUsage struct {
+	Memory  Result[[]MemoryUsage]
+	Disk    Result[[]DiskUsage]
+	Network Result[[]NetworkUsage]
+}
+
+// FetchHostUsage fetches the memory, disk and network usage concurrently, applying policy to failures.
+func FetchHostUsage(ctx context.Context, source DataSource, dateFrom int64, dateTo int64, policy FailurePolicy) (HostUsage, error) {
+	var usage HostUsage
+	errs, err := run(ctx, policy, []func(ctx context.Context) error{
+		func(ctx context.Context) (err error) {
+			usage.Memory.Data, err = source.MemoryUsage(ctx, dateFrom, dateTo)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			usage.Disk.Data, err = source.DiskUsage(ctx, dateFrom, dateTo)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			usage.Network.Data, err = source.NetworkUsage(ctx, dateFrom, dateTo)
+			return err
+		},
+	})
+	if err != nil {
+		return HostUsage{}, err
+	}
+	usage.Memory.Err, usage.Disk.Err, usage.Network.Err = errs[0], errs[1], errs[2]
+	return usage, nil
+}
+
+// FetchEach runs get for every key concurrently, applying policy to failures, and collects the results by key.
+func FetchEach[K comparable, T any](ctx context.Context, policy FailurePolicy, keys []K, get func(ctx context.Context, key K) (T, error)) (map[K]Result[T], error) {
+	data := make([]T, len(keys))
+	tasks := make([]func(ctx context.Context) error, len(keys))
+	for i, key := range keys {
+		tasks[i] = func(ctx context.Context) (err error) {
+			data[i], err = get(ctx, key)
+			return err
+		}
+	}
+	errs, err := run(ctx, policy, tasks)
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[K]Result[T], len(keys))
+	for i, key := range keys {
+		results[key] = Result[T]{Data: data[i], Err: errs[i]}
+	}
+	return results, nil
+}
+
+// run executes the tasks concurrently, each writing its own result. Under FailReport the first failure
+// cancels the other tasks and fails the run; under RenderPlaceholder the error of every task is
+// returned, by position, and logged with the request ID; the run only fails when every task failed.
+func run(ctx context.Context, policy FailurePolicy, tasks []func(ctx context.Context) error) ([]error, error) {
+	errs := make([]error, len(tasks))
+
+	if policy == RenderPlaceholder {
+		var wg sync.WaitGroup
+		for i, task := range tasks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = task(ctx)
+			}()
+		}
+		wg.Wait()
+		failed := 0
+		for _, err := range errs {
+			if err != nil {
+				failed++
+			}
+		}
+		if failed > 0 && failed == len(errs) {
+			return nil, errs[0]
+		}
+		prefix := ""
+		if id := framework.RequestID(ctx); id != "" {
+			prefix = "[" + id + "] "
+		}
+		for _, err := range errs {
+			if err != nil {
+				log.Printf("%sdataset unavailable, rendering a placeholder: %v", prefix, err)
+			}
+		}
+		return errs, nil
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, task := range tasks {
+		group.Go(func() error {
+			return task(groupCtx)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
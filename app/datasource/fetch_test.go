@@ -0,0 +1,105 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunFailReportCancelsTheOtherTasks(t *testing.T) {
+	failure := errors.New("provider down")
+	cancelled := make(chan error, 1)
+	_, err := run(context.Background(), FailReport, []func(ctx context.Context) error{
+		func(ctx context.Context) error { return failure },
+		func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				cancelled <- ctx.Err()
+			case <-time.After(time.Second):
+				cancelled <- nil
+			}
+			return ctx.Err()
+		},
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("run() = %v, want %v", err, failure)
+	}
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("the other task ended with %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRunFailReportSucceeds(t *testing.T) {
+	errs, err := run(context.Background(), FailReport, []func(ctx context.Context) error{
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { return nil },
+	})
+	if err != nil {
+		t.Fatalf("run() = %v", err)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("task %d: error %v", i, err)
+		}
+	}
+}
+
+func TestRunRenderPlaceholderKeepsEveryError(t *testing.T) {
+	failure := errors.New("provider down")
+	finished := false
+	errs, err := run(context.Background(), RenderPlaceholder, []func(ctx context.Context) error{
+		func(ctx context.Context) error { return failure },
+		func(ctx context.Context) error {
+			// A failure elsewhere does not cancel the task.
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(20 * time.Millisecond):
+				finished = true
+				return nil
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("run() = %v", err)
+	}
+	if !errors.Is(errs[0], failure) || errs[1] != nil {
+		t.Fatalf("run() errors = %v, want [%v <nil>]", errs, failure)
+	}
+	if !finished {
+		t.Error("the successful task did not finish")
+	}
+}
+
+func TestRunRenderPlaceholderFailsWhenEveryTaskFails(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	_, err := run(context.Background(), RenderPlaceholder, []func(ctx context.Context) error{
+		func(ctx context.Context) error { return first },
+		func(ctx context.Context) error { return second },
+	})
+	if !errors.Is(err, first) {
+		t.Fatalf("run() = %v, want %v", err, first)
+	}
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	for value, want := range map[string]FailurePolicy{"": FailReport, "fail": FailReport, "placeholder": RenderPlaceholder} {
+		if policy, err := ParseFailurePolicy(value); err != nil || policy != want {
+			t.Errorf("ParseFailurePolicy(%q) = %q, %v, want %q", value, policy, err, want)
+		}
+	}
+	if _, err := ParseFailurePolicy("ignore"); err == nil {
+		t.Error("ParseFailurePolicy(\"ignore\") succeeded")
+	}
+}
+
+func TestUnavailableHidesTheError(t *testing.T) {
+	result := Result[[]DiskUsage]{Err: errors.New("failed to read fixture /srv/fixtures/disk_usage.json: open: permission denied")}
+	if reason := result.Unavailable(); reason != UnavailableReason {
+		t.Errorf("Unavailable() = %q, want %q", reason, UnavailableReason)
+	}
+	if reason := (Result[[]DiskUsage]{}).Unavailable(); reason != "" {
+		t.Errorf("Unavailable() of a fetched dataset = %q, want \"\"", reason)
+	}
+}
@@ -0,0 +1,156 @@
+package datasource
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+)
+
+// FileDataSource reads the usage data from JSON or CSV fixtures stored in a directory, named after
+// the dataset (cpu_system_usage.json, cpu_user_usage.csv, ...). Timelines (cpu_system_usage_timeline.json,
+// ...) and the memory, disk and network datasets (memory_usage.json, disk_usage.json, network_usage.json)
+// are read from JSON fixtures only; timelines are resampled to the requested step. Fixtures are
+// static, so the requested period is ignored.
+type FileDataSource struct {
+	dir string
+}
+
+func NewFileDataSource(dir string) *FileDataSource {
+	return &FileDataSource{dir: dir}
+}
+
+func (source *FileDataSource) CpuUsage(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64) ([]CpuUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("cpu_%s_usage", kind)
+
+	jsonPath := filepath.Join(source.dir, name+".json")
+	data, err := readCpuUsageJSON(jsonPath)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, framework.NewUpstreamError(fmt.Sprintf("invalid fixture %s", jsonPath), err)
+	}
+
+	csvPath := filepath.Join(source.dir, name+".csv")
+	data, err = readCpuUsageCSV(csvPath)
+	if err != nil {
+		return nil, framework.NewUpstreamError(fmt.Sprintf("failed to read fixture %s", csvPath), err)
+	}
+	return data, nil
+}
+
+func (source *FileDataSource) CpuUsageTimeline(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64, step time.Duration) ([]CpuUsageTimeline, error) {
+	data, err := readFixtureJSON[CpuUsageTimeline](ctx, source.dir, fmt.Sprintf("cpu_%s_usage_timeline.json", kind))
+	if err != nil {
+		return nil, err
+	}
+	return ResampleTimelines(data, step), nil
+}
+
+func (source *FileDataSource) MemoryUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]MemoryUsage, error) {
+	return readFixtureJSON[MemoryUsage](ctx, source.dir, "memory_usage.json")
+}
+
+func (source *FileDataSource) DiskUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]DiskUsage, error) {
+	return readFixtureJSON[DiskUsage](ctx, source.dir, "disk_usage.json")
+}
+
+func (source *FileDataSource) NetworkUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]NetworkUsage, error) {
+	return readFixtureJSON[NetworkUsage](ctx, source.dir, "network_usage.json")
+}
+
+// Provenance returns the fixture directory.
+func (source *FileDataSource) Provenance() Provenance {
+	return Provenance{Address: "file:" + source.dir}
+}
+
+func (source *FileDataSource) Close() error {
+	return nil
+}
+
+// readFixtureJSON reads a JSON array fixture of dir, reporting failures as upstream errors.
+func readFixtureJSON[T any](ctx context.Context, dir string, name string) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, framework.NewUpstreamError(fmt.Sprintf("failed to read fixture %s", path), err)
+	}
+	var data []T
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, framework.NewUpstreamError(fmt.Sprintf("invalid fixture %s", path), err)
+	}
+	return data, nil
+}
+
+func readCpuUsageJSON(path string) ([]CpuUsage, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var data []CpuUsage
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readCpuUsageCSV reads a fixture whose header row names the cpu, avg_usage, max_usage and min_usage columns.
+func readCpuUsageCSV(path string) ([]CpuUsage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header row")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, header := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(header))] = i
+	}
+	for _, required := range []string{"cpu", "avg_usage", "max_usage", "min_usage"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	data := make([]CpuUsage, 0, len(records)-1)
+	for line, record := range records[1:] {
+		usage := CpuUsage{CPU: record[columns["cpu"]]}
+		for column, target := range map[string]*float64{
+			"avg_usage": &usage.AvgUsage,
+			"max_usage": &usage.MaxUsage,
+			"min_usage": &usage.MinUsage,
+		} {
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[columns[column]]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s: %w", line+2, column, err)
+			}
+			*target = value
+		}
+		data = append(data, usage)
+	}
+	return data, nil
+}
@@ -0,0 +1,298 @@
+package datasource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+	pb_system "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_system_usage"
+	pb_user "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_user_usage"
+	pb_disk "github.com/Javier-Godon/reports-rendering-go/proto/get_disk_usage"
+	pb_memory "github.com/Javier-Godon/reports-rendering-go/proto/get_memory_usage"
+	pb_network "github.com/Javier-Godon/reports-rendering-go/proto/get_network_usage"
+)
+
+const (
+	defaultRPCTimeout = 30 * time.Second
+	defaultRPCBackoff = 200 * time.Millisecond
+)
+
+// GRPCOptions bounds every call to the data provider.
+type GRPCOptions struct {
+	// Timeout is the deadline of a single RPC attempt.
+	Timeout time.Duration
+	// Retries is the number of extra attempts made after an Unavailable or DeadlineExceeded failure.
+	Retries int
+	// Backoff is the wait before the first retry. It doubles on every retry and is jittered.
+	Backoff time.Duration
+}
+
+// GRPCDataSource reads the usage data from the gRPC data provider through the application's client pool.
+type GRPCDataSource struct {
+	pool *proto.GRPCClientPool
+	opts GRPCOptions
+}
+
+func NewGRPCDataSource(pool *proto.GRPCClientPool, opts GRPCOptions) *GRPCDataSource {
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultRPCTimeout
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultRPCBackoff
+	}
+	return &GRPCDataSource{pool: pool, opts: opts}
+}
+
+func (source *GRPCDataSource) CpuUsage(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64) ([]CpuUsage, error) {
+	var data []CpuUsage
+	switch kind {
+	case CpuSystem:
+		err := source.call(ctx, "cpu system usage", func(callCtx context.Context) error {
+			resp, err := source.pool.Client().GetCpuSystemUsage(callCtx, dateFrom, dateTo)
+			data = mapGetCpuSystemUsageResponse(resp)
+			return err
+		})
+		if err != nil {
+			return nil, framework.NewUpstreamError("failed to get cpu system usage", err)
+		}
+		return data, nil
+	case CpuUser:
+		err := source.call(ctx, "cpu user usage", func(callCtx context.Context) error {
+			resp, err := source.pool.Client().GetCpuUserUsage(callCtx, dateFrom, dateTo)
+			data = mapGetCpuUserUsageResponse(resp)
+			return err
+		})
+		if err != nil {
+			return nil, framework.NewUpstreamError("failed to get cpu user usage", err)
+		}
+		return data, nil
+	default:
+		return nil, framework.NewValidationError(fmt.Sprintf("unknown cpu usage kind %q", kind), nil)
+	}
+}
+
+func (source *GRPCDataSource) CpuUsageTimeline(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64, step time.Duration) ([]CpuUsageTimeline, error) {
+	stepSeconds := int64(step / time.Second)
+	var data []CpuUsageTimeline
+	switch kind {
+	case CpuSystem:
+		err := source.call(ctx, "cpu system usage timeline", func(callCtx context.Context) error {
+			resp, err := source.pool.Client().GetCpuSystemUsageTimeline(callCtx, dateFrom, dateTo, stepSeconds)
+			data = mapGetCpuSystemUsageTimelineResponse(resp)
+			return err
+		})
+		if err != nil {
+			return nil, framework.NewUpstreamError("failed to get cpu system usage timeline", err)
+		}
+		return data, nil
+	case CpuUser:
+		err := source.call(ctx, "cpu user usage timeline", func(callCtx context.Context) error {
+			resp, err := source.pool.Client().GetCpuUserUsageTimeline(callCtx, dateFrom, dateTo, stepSeconds)
+			data = mapGetCpuUserUsageTimelineResponse(resp)
+			return err
+		})
+		if err != nil {
+			return nil, framework.NewUpstreamError("failed to get cpu user usage timeline", err)
+		}
+		return data, nil
+	default:
+		return nil, framework.NewValidationError(fmt.Sprintf("unknown cpu usage kind %q", kind), nil)
+	}
+}
+
+func (source *GRPCDataSource) MemoryUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]MemoryUsage, error) {
+	var data []MemoryUsage
+	err := source.call(ctx, "memory usage", func(callCtx context.Context) error {
+		resp, err := source.pool.Client().GetMemoryUsage(callCtx, dateFrom, dateTo)
+		data = mapGetMemoryUsageResponse(resp)
+		return err
+	})
+	if err != nil {
+		return nil, framework.NewUpstreamError("failed to get memory usage", err)
+	}
+	return data, nil
+}
+
+func (source *GRPCDataSource) DiskUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]DiskUsage, error) {
+	var data []DiskUsage
+	err := source.call(ctx, "disk usage", func(callCtx context.Context) error {
+		resp, err := source.pool.Client().GetDiskUsage(callCtx, dateFrom, dateTo)
+		data = mapGetDiskUsageResponse(resp)
+		return err
+	})
+	if err != nil {
+		return nil, framework.NewUpstreamError("failed to get disk usage", err)
+	}
+	return data, nil
+}
+
+func (source *GRPCDataSource) NetworkUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]NetworkUsage, error) {
+	var data []NetworkUsage
+	err := source.call(ctx, "network usage", func(callCtx context.Context) error {
+		resp, err := source.pool.Client().GetNetworkUsage(callCtx, dateFrom, dateTo)
+		data = mapGetNetworkUsageResponse(resp)
+		return err
+	})
+	if err != nil {
+		return nil, framework.NewUpstreamError("failed to get network usage", err)
+	}
+	return data, nil
+}
+
+// call runs rpc with a per-attempt deadline, retrying transient failures with exponential backoff
+// for as long as ctx allows.
+func (source *GRPCDataSource) call(ctx context.Context, name string, rpc func(callCtx context.Context) error) error {
+	backoff := source.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := context.WithTimeout(ctx, source.opts.Timeout)
+		err := rpc(callCtx)
+		cancel()
+		if err == nil || attempt >= source.opts.Retries || ctx.Err() != nil || !isTransient(err) {
+			return err
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff)))
+		log.Printf("retrying %s in %s (attempt %d/%d): %v", name, wait, attempt+2, source.opts.Retries+1, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransient reports whether a failed RPC is worth retrying.
+func isTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// Provenance returns the address of the data provider and the version it reported, if any yet.
+func (source *GRPCDataSource) Provenance() Provenance {
+	return Provenance{Address: source.pool.Address(), Version: source.pool.Version()}
+}
+
+// Close does nothing: the pool is owned by the application, which closes it on shutdown.
+func (source *GRPCDataSource) Close() error {
+	return nil
+}
+
+// mapGetCpuSystemUsageResponse maps the gRPC response to a slice of CpuUsage.
+func mapGetCpuSystemUsageResponse(usage *pb_system.GetCpuSystemUsageResponse) []CpuUsage {
+	data := make([]CpuUsage, len(usage.GetUsages()))
+	for i, u := range usage.GetUsages() {
+		data[i] = CpuUsage{
+			CPU:      u.Cpu,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
+
+// mapGetCpuUserUsageResponse maps the gRPC response to a slice of CpuUsage.
+func mapGetCpuUserUsageResponse(usage *pb_user.GetCpuUserUsageResponse) []CpuUsage {
+	data := make([]CpuUsage, len(usage.GetUsages()))
+	for i, u := range usage.GetUsages() {
+		data[i] = CpuUsage{
+			CPU:      u.Cpu,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
+
+// mapGetCpuSystemUsageTimelineResponse maps the gRPC response to a slice of CpuUsageTimeline.
+func mapGetCpuSystemUsageTimelineResponse(timeline *pb_system.GetCpuSystemUsageTimelineResponse) []CpuUsageTimeline {
+	data := make([]CpuUsageTimeline, len(timeline.GetTimelines()))
+	for i, t := range timeline.GetTimelines() {
+		samples := make([]CpuUsageSample, len(t.GetSamples()))
+		for j, sample := range t.GetSamples() {
+			samples[j] = CpuUsageSample{
+				Timestamp: sample.Timestamp,
+				AvgUsage:  sample.AvgUsage,
+				MaxUsage:  sample.MaxUsage,
+				MinUsage:  sample.MinUsage,
+			}
+		}
+		data[i] = CpuUsageTimeline{CPU: t.Cpu, Samples: samples}
+	}
+	return data
+}
+
+// mapGetCpuUserUsageTimelineResponse maps the gRPC response to a slice of CpuUsageTimeline.
+func mapGetCpuUserUsageTimelineResponse(timeline *pb_user.GetCpuUserUsageTimelineResponse) []CpuUsageTimeline {
+	data := make([]CpuUsageTimeline, len(timeline.GetTimelines()))
+	for i, t := range timeline.GetTimelines() {
+		samples := make([]CpuUsageSample, len(t.GetSamples()))
+		for j, sample := range t.GetSamples() {
+			samples[j] = CpuUsageSample{
+				Timestamp: sample.Timestamp,
+				AvgUsage:  sample.AvgUsage,
+				MaxUsage:  sample.MaxUsage,
+				MinUsage:  sample.MinUsage,
+			}
+		}
+		data[i] = CpuUsageTimeline{CPU: t.Cpu, Samples: samples}
+	}
+	return data
+}
+
+// mapGetMemoryUsageResponse maps the gRPC response to a slice of MemoryUsage.
+func mapGetMemoryUsageResponse(usage *pb_memory.GetMemoryUsageResponse) []MemoryUsage {
+	data := make([]MemoryUsage, len(usage.GetUsages()))
+	for i, u := range usage.GetUsages() {
+		data[i] = MemoryUsage{
+			Metric:   u.Metric,
+			AvgBytes: u.AvgBytes,
+			MaxBytes: u.MaxBytes,
+			MinBytes: u.MinBytes,
+		}
+	}
+	return data
+}
+
+// mapGetDiskUsageResponse maps the gRPC response to a slice of DiskUsage.
+func mapGetDiskUsageResponse(usage *pb_disk.GetDiskUsageResponse) []DiskUsage {
+	data := make([]DiskUsage, len(usage.GetUsages()))
+	for i, u := range usage.GetUsages() {
+		data[i] = DiskUsage{
+			Device:              u.Device,
+			ReadBytesPerSecond:  u.ReadBytesPerSecond,
+			WriteBytesPerSecond: u.WriteBytesPerSecond,
+			ReadIOPS:            u.ReadIops,
+			WriteIOPS:           u.WriteIops,
+		}
+	}
+	return data
+}
+
+// mapGetNetworkUsageResponse maps the gRPC response to a slice of NetworkUsage.
+func mapGetNetworkUsageResponse(usage *pb_network.GetNetworkUsageResponse) []NetworkUsage {
+	data := make([]NetworkUsage, len(usage.GetUsages()))
+	for i, u := range usage.GetUsages() {
+		data[i] = NetworkUsage{
+			Interface: u.Interface,
+			RxBytes:   u.RxBytes,
+			TxBytes:   u.TxBytes,
+			RxErrors:  u.RxErrors,
+			TxErrors:  u.TxErrors,
+		}
+	}
+	return data
+}
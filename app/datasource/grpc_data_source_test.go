@@ -0,0 +1,132 @@
+package datasource_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/mockprovider"
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+)
+
+const (
+	dateFrom = 1704067200
+	dateTo   = dateFrom + 3600
+)
+
+// newSource starts a mock provider with opts and returns a data source reading from it.
+func newSource(t *testing.T, provider mockprovider.Options, opts datasource.GRPCOptions) (*datasource.GRPCDataSource, *mockprovider.Server) {
+	t.Helper()
+	server := mockprovider.StartForTest(t, provider)
+	pool, err := proto.NewGRPCClientPool(server.Addr(), 1)
+	if err != nil {
+		t.Fatalf("failed to create client pool: %v", err)
+	}
+	t.Cleanup(func() { _ = pool.Close() })
+	return datasource.NewGRPCDataSource(pool, opts), server
+}
+
+func TestGRPCDataSourceReadsTheProvider(t *testing.T) {
+	source, server := newSource(t, mockprovider.Options{CPUs: 3, Version: "2.0.1"}, datasource.GRPCOptions{})
+
+	usages, err := source.CpuUsage(context.Background(), datasource.CpuSystem, dateFrom, dateTo)
+	if err != nil {
+		t.Fatalf("CpuUsage() = %v", err)
+	}
+	if len(usages) != 3 || usages[0].CPU != "cpu0" {
+		t.Fatalf("CpuUsage() = %+v, want cpu0..cpu2", usages)
+	}
+	if calls := server.Calls(); calls != 1 {
+		t.Errorf("provider received %d calls, want 1", calls)
+	}
+	if version := source.Provenance().Version; version != "2.0.1" {
+		t.Errorf("Provenance().Version = %q, want %q", version, "2.0.1")
+	}
+}
+
+func TestGRPCDataSourceRetriesTransientFailures(t *testing.T) {
+	for _, code := range []codes.Code{codes.Unavailable, codes.DeadlineExceeded} {
+		t.Run(code.String(), func(t *testing.T) {
+			source, server := newSource(t,
+				mockprovider.Options{ErrorRate: 1, ErrorCode: code},
+				datasource.GRPCOptions{Retries: 2, Backoff: time.Millisecond})
+
+			_, err := source.MemoryUsage(context.Background(), dateFrom, dateTo)
+			if status.Code(err) != code {
+				t.Fatalf("MemoryUsage() = %v, want code %s", err, code)
+			}
+			if calls := server.Calls(); calls != 3 {
+				t.Errorf("provider received %d calls, want 3", calls)
+			}
+		})
+	}
+}
+
+func TestGRPCDataSourceDoesNotRetryOtherFailures(t *testing.T) {
+	source, server := newSource(t,
+		mockprovider.Options{ErrorRate: 1, ErrorCode: codes.InvalidArgument},
+		datasource.GRPCOptions{Retries: 3, Backoff: time.Millisecond})
+
+	_, err := source.DiskUsage(context.Background(), dateFrom, dateTo)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("DiskUsage() = %v, want code %s", err, codes.InvalidArgument)
+	}
+	if calls := server.Calls(); calls != 1 {
+		t.Errorf("provider received %d calls, want 1", calls)
+	}
+}
+
+func TestGRPCDataSourceRecoversAfterRetries(t *testing.T) {
+	// Half of the calls fail; with enough retries the fetch goes through anyway.
+	source, server := newSource(t,
+		mockprovider.Options{ErrorRate: 0.5, Seed: 7},
+		datasource.GRPCOptions{Retries: 10, Backoff: time.Millisecond})
+
+	for i := 0; i < 5; i++ {
+		if _, err := source.NetworkUsage(context.Background(), dateFrom, dateTo); err != nil {
+			t.Fatalf("NetworkUsage() = %v", err)
+		}
+	}
+	if calls := server.Calls(); calls <= 5 {
+		t.Errorf("provider received %d calls, want retries beyond the 5 fetches", calls)
+	}
+}
+
+func TestGRPCDataSourceBoundsEveryAttempt(t *testing.T) {
+	source, server := newSource(t,
+		mockprovider.Options{Latency: time.Second},
+		datasource.GRPCOptions{Timeout: 50 * time.Millisecond, Retries: 1, Backoff: time.Millisecond})
+
+	start := time.Now()
+	_, err := source.CpuUsage(context.Background(), datasource.CpuUser, dateFrom, dateTo)
+	elapsed := time.Since(start)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("CpuUsage() = %v, want code %s", err, codes.DeadlineExceeded)
+	}
+	if elapsed >= time.Second {
+		t.Errorf("CpuUsage() took %s, want each attempt cut at the 50ms timeout", elapsed)
+	}
+	if calls := server.Calls(); calls != 2 {
+		t.Errorf("provider received %d calls, want 2", calls)
+	}
+}
+
+func TestGRPCDataSourceStopsRetryingWhenTheContextEnds(t *testing.T) {
+	source, _ := newSource(t,
+		mockprovider.Options{ErrorRate: 1},
+		datasource.GRPCOptions{Retries: 100, Backoff: 20 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := source.MemoryUsage(ctx, dateFrom, dateTo); err == nil {
+		t.Fatal("MemoryUsage() succeeded")
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("MemoryUsage() took %s after its context ended", elapsed)
+	}
+}
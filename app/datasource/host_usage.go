@@ -0,0 +1,33 @@
+package datasource
+
+// MemoryUsage aggregates one memory metric (MemoryUsed, MemoryCached or MemorySwap) over the period, in bytes.
+type MemoryUsage struct {
+	Metric   string  `json:"metric"`
+	AvgBytes float64 `json:"avg_bytes"`
+	MaxBytes float64 `json:"max_bytes"`
+	MinBytes float64 `json:"min_bytes"`
+}
+
+const (
+	MemoryUsed   = "used"
+	MemoryCached = "cached"
+	MemorySwap   = "swap"
+)
+
+// DiskUsage holds the average I/O of one block device over the period.
+type DiskUsage struct {
+	Device              string  `json:"device"`
+	ReadBytesPerSecond  float64 `json:"read_bytes_per_second"`
+	WriteBytesPerSecond float64 `json:"write_bytes_per_second"`
+	ReadIOPS            float64 `json:"read_iops"`
+	WriteIOPS           float64 `json:"write_iops"`
+}
+
+// NetworkUsage holds the traffic and errors of one network interface over the period.
+type NetworkUsage struct {
+	Interface string `json:"interface"`
+	RxBytes   int64  `json:"rx_bytes"`
+	TxBytes   int64  `json:"tx_bytes"`
+	RxErrors  int64  `json:"rx_errors"`
+	TxErrors  int64  `json:"tx_errors"`
+}
@@ -0,0 +1,152 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// MemoryDataSource serves usage data kept in memory. It is meant for tests and offline rendering.
+type MemoryDataSource struct {
+	mu        sync.RWMutex
+	data      map[UsageKind][]CpuUsage
+	timelines map[UsageKind][]CpuUsageTimeline
+	memory    []MemoryUsage
+	disk      []DiskUsage
+	network   []NetworkUsage
+}
+
+func NewMemoryDataSource() *MemoryDataSource {
+	return &MemoryDataSource{
+		data:      make(map[UsageKind][]CpuUsage),
+		timelines: make(map[UsageKind][]CpuUsageTimeline),
+	}
+}
+
+// SetCpuUsage replaces the usage data returned for kind.
+func (source *MemoryDataSource) SetCpuUsage(kind UsageKind, data []CpuUsage) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.data[kind] = append([]CpuUsage(nil), data...)
+}
+
+// SetCpuUsageTimeline replaces the timelines of kind. They are resampled to the step requested.
+func (source *MemoryDataSource) SetCpuUsageTimeline(kind UsageKind, timelines []CpuUsageTimeline) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.timelines[kind] = ResampleTimelines(timelines, 0)
+}
+
+// SetMemoryUsage replaces the memory usage data.
+func (source *MemoryDataSource) SetMemoryUsage(data []MemoryUsage) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.memory = append([]MemoryUsage(nil), data...)
+}
+
+// SetDiskUsage replaces the disk usage data.
+func (source *MemoryDataSource) SetDiskUsage(data []DiskUsage) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.disk = append([]DiskUsage(nil), data...)
+}
+
+// SetNetworkUsage replaces the network usage data.
+func (source *MemoryDataSource) SetNetworkUsage(data []NetworkUsage) {
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	source.network = append([]NetworkUsage(nil), data...)
+}
+
+// LoadFrom copies every dataset from another data source. Missing timeline, memory, disk and network
+// fixtures are skipped.
+func (source *MemoryDataSource) LoadFrom(other DataSource) error {
+	for _, kind := range Kinds() {
+		data, err := other.CpuUsage(context.Background(), kind, 0, 0)
+		if err != nil {
+			return err
+		}
+		source.SetCpuUsage(kind, data)
+
+		timelines, err := other.CpuUsageTimeline(context.Background(), kind, 0, 0, 0)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		source.SetCpuUsageTimeline(kind, timelines)
+	}
+
+	memory, err := other.MemoryUsage(context.Background(), 0, 0)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	source.SetMemoryUsage(memory)
+
+	disk, err := other.DiskUsage(context.Background(), 0, 0)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	source.SetDiskUsage(disk)
+
+	network, err := other.NetworkUsage(context.Background(), 0, 0)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	source.SetNetworkUsage(network)
+	return nil
+}
+
+func (source *MemoryDataSource) CpuUsage(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64) ([]CpuUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+	return append([]CpuUsage(nil), source.data[kind]...), nil
+}
+
+func (source *MemoryDataSource) CpuUsageTimeline(ctx context.Context, kind UsageKind, dateFrom int64, dateTo int64, step time.Duration) ([]CpuUsageTimeline, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+	return ResampleTimelines(source.timelines[kind], step), nil
+}
+
+func (source *MemoryDataSource) MemoryUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]MemoryUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+	return append([]MemoryUsage(nil), source.memory...), nil
+}
+
+func (source *MemoryDataSource) DiskUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]DiskUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+	return append([]DiskUsage(nil), source.disk...), nil
+}
+
+func (source *MemoryDataSource) NetworkUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]NetworkUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source.mu.RLock()
+	defer source.mu.RUnlock()
+	return append([]NetworkUsage(nil), source.network...), nil
+}
+
+// Provenance names the in-memory data source.
+func (source *MemoryDataSource) Provenance() Provenance {
+	return Provenance{Address: "memory"}
+}
+
+func (source *MemoryDataSource) Close() error {
+	return nil
+}
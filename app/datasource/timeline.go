@@ -0,0 +1,77 @@
+package datasource
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// MaxTimelineBuckets bounds the number of samples per CPU a timeline may be requested with.
+const MaxTimelineBuckets = 50000
+
+// CpuUsageSample holds the usage of a CPU over the bucket starting at Timestamp (unix seconds).
+type CpuUsageSample struct {
+	Timestamp int64   `json:"timestamp"`
+	AvgUsage  float64 `json:"avg_usage"`
+	MaxUsage  float64 `json:"max_usage"`
+	MinUsage  float64 `json:"min_usage"`
+}
+
+// CpuUsageTimeline holds the samples of a single CPU, ordered by time.
+type CpuUsageTimeline struct {
+	CPU     string           `json:"cpu"`
+	Samples []CpuUsageSample `json:"samples"`
+}
+
+// ValidateTimelineStep checks that a step is at least a minute and does not split the period into
+// more than MaxTimelineBuckets buckets.
+func ValidateTimelineStep(dateFrom int64, dateTo int64, step time.Duration) error {
+	if step < time.Minute {
+		return fmt.Errorf("step %s must be at least 1m", step)
+	}
+	if buckets := (dateTo - dateFrom) / int64(step/time.Second); buckets > MaxTimelineBuckets {
+		return fmt.Errorf("step %s splits the period into %d buckets, more than %d", step, buckets, MaxTimelineBuckets)
+	}
+	return nil
+}
+
+// ResampleTimelines regroups samples into buckets of step aligned on the unix epoch: the average of
+// the averages, the highest maximum and the lowest minimum. A step of zero returns the samples as they are.
+func ResampleTimelines(timelines []CpuUsageTimeline, step time.Duration) []CpuUsageTimeline {
+	seconds := int64(step / time.Second)
+	resampled := make([]CpuUsageTimeline, len(timelines))
+	for i, timeline := range timelines {
+		resampled[i] = CpuUsageTimeline{CPU: timeline.CPU}
+		if seconds <= 0 {
+			resampled[i].Samples = append([]CpuUsageSample(nil), timeline.Samples...)
+			continue
+		}
+
+		type bucket struct {
+			sample CpuUsageSample
+			count  int
+		}
+		buckets := make(map[int64]*bucket)
+		for _, sample := range timeline.Samples {
+			start := sample.Timestamp - ((sample.Timestamp%seconds)+seconds)%seconds
+			b, ok := buckets[start]
+			if !ok {
+				b = &bucket{sample: CpuUsageSample{Timestamp: start, MaxUsage: math.Inf(-1), MinUsage: math.Inf(1)}}
+				buckets[start] = b
+			}
+			b.sample.AvgUsage += sample.AvgUsage
+			b.sample.MaxUsage = max(b.sample.MaxUsage, sample.MaxUsage)
+			b.sample.MinUsage = min(b.sample.MinUsage, sample.MinUsage)
+			b.count++
+		}
+		for _, b := range buckets {
+			b.sample.AvgUsage /= float64(b.count)
+			resampled[i].Samples = append(resampled[i].Samples, b.sample)
+		}
+		sort.Slice(resampled[i].Samples, func(a, b int) bool {
+			return resampled[i].Samples[a].Timestamp < resampled[i].Samples[b].Timestamp
+		})
+	}
+	return resampled
+}
@@ -3,6 +3,7 @@ package framework
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,11 +12,33 @@ const configPath = "application.yaml"
 
 type Cfg struct {
 	ServerPort struct {
-		PORT string `yaml:"port"`
+		PORT             string        `yaml:"port"`
+		SHUTDOWN_TIMEOUT time.Duration `yaml:"shutdown-timeout"`
 	} `yaml:"server"`
 	DataProvider struct {
-		ADDRESS string `yaml:"address"`
+		ADDRESS   string        `yaml:"address"`
+		POOL_SIZE int           `yaml:"pool-size"`
+		TIMEOUT   time.Duration `yaml:"timeout"`
+		RETRIES   int           `yaml:"retries"`
+		BACKOFF   time.Duration `yaml:"backoff"`
 	} `yaml:"data-provider"`
+	Report struct {
+		PARTIAL_FAILURE       string  `yaml:"partial-failure"`
+		XLSX_STREAM_THRESHOLD int     `yaml:"xlsx-stream-threshold"`
+		XLSX_SHEET_ROW_LIMIT  int     `yaml:"xlsx-sheet-row-limit"`
+		DEFINITIONS_DIR       string  `yaml:"definitions-dir"`
+		OVERVIEW_THRESHOLD    float64 `yaml:"overview-threshold"`
+		TIMEZONE              string  `yaml:"timezone"`
+	} `yaml:"report"`
+	DataSource struct {
+		TYPE string `yaml:"type"`
+		PATH string `yaml:"path"`
+	} `yaml:"data-source"`
+	Jobs struct {
+		WORKERS    int           `yaml:"workers"`
+		QUEUE_SIZE int           `yaml:"queue-size"`
+		TTL        time.Duration `yaml:"ttl"`
+	} `yaml:"jobs"`
 }
 
 var AppConfig *Cfg
@@ -0,0 +1,142 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Validator is implemented by requests that can check their own consistency before being handled.
+type Validator interface {
+	Validate() error
+}
+
+// RecoveryBehavior turns a panic raised further down the pipeline into an error.
+func RecoveryBehavior() PipelineBehavior {
+	return PipelineBehaviorFunc(func(ctx context.Context, request any, next RequestHandlerFunc) (result any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("recovered from panic handling %T: %v\n%s", request, r, debug.Stack())
+				err = fmt.Errorf("panic handling %T: %v", request, r)
+			}
+		}()
+		return next(ctx)
+	})
+}
+
+// LoggingBehavior logs the type of every request together with its outcome and duration, prefixed
+// with its request ID when it has one. The request itself is left out of the log, as it may hold
+// anything a client sent.
+func LoggingBehavior() PipelineBehavior {
+	return PipelineBehaviorFunc(func(ctx context.Context, request any, next RequestHandlerFunc) (any, error) {
+		start := time.Now()
+		prefix := ""
+		if id := RequestID(ctx); id != "" {
+			prefix = "[" + id + "] "
+		}
+		log.Printf("%shandling %T", prefix, request)
+		result, err := next(ctx)
+		if err != nil {
+			log.Printf("%sfailed handling %T after %s: %v", prefix, request, time.Since(start), err)
+		} else {
+			log.Printf("%shandled %T in %s", prefix, request, time.Since(start))
+		}
+		return result, err
+	})
+}
+
+// ValidationBehavior short-circuits requests implementing Validator whose validation fails,
+// reporting the failure as a KindValidation error.
+func ValidationBehavior() PipelineBehavior {
+	return PipelineBehaviorFunc(func(ctx context.Context, request any, next RequestHandlerFunc) (any, error) {
+		if validator, ok := request.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				if KindOf(err) == KindInternal {
+					err = NewValidationError("invalid request", err)
+				}
+				return nil, err
+			}
+		}
+		return next(ctx)
+	})
+}
+
+// RetryBehavior retries the rest of the pipeline up to attempts times, waiting backoff (doubled after
+// every attempt) in between, as long as retryable reports the error as transient.
+func RetryBehavior(attempts int, backoff time.Duration, retryable func(error) bool) PipelineBehavior {
+	return PipelineBehaviorFunc(func(ctx context.Context, request any, next RequestHandlerFunc) (any, error) {
+		var result any
+		var err error
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			result, err = next(ctx)
+			if err == nil || attempt >= attempts || !retryable(err) {
+				return result, err
+			}
+			log.Printf("retrying %T (attempt %d/%d) after %s: %v", request, attempt+1, attempts, wait, err)
+			select {
+			case <-ctx.Done():
+				return result, err
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+	})
+}
+
+// RequestStats aggregates the outcome of the requests of one type.
+type RequestStats struct {
+	Count         int64         `json:"count"`
+	Failures      int64         `json:"failures"`
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+}
+
+// RequestMetrics collects per-request-type timings recorded by MetricsBehavior.
+type RequestMetrics struct {
+	mu    sync.Mutex
+	stats map[string]RequestStats
+}
+
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{stats: make(map[string]RequestStats)}
+}
+
+// Snapshot returns a copy of the statistics collected so far, keyed by request type.
+func (m *RequestMetrics) Snapshot() map[string]RequestStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]RequestStats, len(m.stats))
+	for requestType, stats := range m.stats {
+		snapshot[requestType] = stats
+	}
+	return snapshot
+}
+
+func (m *RequestMetrics) record(requestType string, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.stats[requestType]
+	stats.Count++
+	if err != nil {
+		stats.Failures++
+	}
+	stats.TotalDuration += elapsed
+	if elapsed > stats.MaxDuration {
+		stats.MaxDuration = elapsed
+	}
+	m.stats[requestType] = stats
+}
+
+// MetricsBehavior times every request and records it into metrics.
+func MetricsBehavior(metrics *RequestMetrics) PipelineBehavior {
+	return PipelineBehaviorFunc(func(ctx context.Context, request any, next RequestHandlerFunc) (any, error) {
+		start := time.Now()
+		result, err := next(ctx)
+		metrics.record(fmt.Sprintf("%T", request), time.Since(start), err)
+		return result, err
+	})
+}
@@ -0,0 +1,115 @@
+package framework
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ReportEndpoint is a route that renders a report. Async endpoints queue a job instead of
+// returning the file.
+type ReportEndpoint struct {
+	Format string `json:"format"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Async  bool   `json:"async,omitempty"`
+}
+
+// ReportParameter is a field of the request body of a report.
+type ReportParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// ReportType describes a report that clients can request, as listed by GET /reports.
+type ReportType struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Formats     []string          `json:"formats"`
+	Endpoints   []ReportEndpoint  `json:"endpoints"`
+	Parameters  []ReportParameter `json:"parameters"`
+	Schema      string            `json:"schema"`
+}
+
+// ReportCatalog collects the report types served by the application. Routes register the reports
+// they render together with their request contract, so the catalog always matches the routes.
+type ReportCatalog struct {
+	mu      sync.RWMutex
+	reports map[string]*catalogEntry
+}
+
+type catalogEntry struct {
+	report  ReportType
+	request reflect.Type
+	schema  map[string]any
+}
+
+func NewReportCatalog() *ReportCatalog {
+	return &ReportCatalog{reports: make(map[string]*catalogEntry)}
+}
+
+// Register adds a report rendered from requests shaped like request, a struct whose json and binding
+// tags define the body and whose description tags document it. Registering an id again adds the
+// endpoints and formats of the new registration, for example the job route of a report. Like
+// conflicting gin routes, registering the same id with another request type is a programming error
+// and panics.
+func (catalog *ReportCatalog) Register(report ReportType, request any) {
+	requestType := reflect.TypeOf(request)
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	if entry, exists := catalog.reports[report.ID]; exists {
+		if entry.request != requestType {
+			panic(fmt.Sprintf("report %q is already registered with request %v", report.ID, entry.request))
+		}
+		for _, format := range report.Formats {
+			if !slices.Contains(entry.report.Formats, format) {
+				entry.report.Formats = append(entry.report.Formats, format)
+			}
+		}
+		entry.report.Endpoints = append(entry.report.Endpoints, report.Endpoints...)
+		return
+	}
+
+	schema, parameters := requestSchema(requestType)
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = report.Title
+	if report.Description != "" {
+		schema["description"] = report.Description
+	}
+	report.Parameters = parameters
+	report.Schema = "/reports/" + report.ID + "/schema"
+	catalog.reports[report.ID] = &catalogEntry{report: report, request: requestType, schema: schema}
+}
+
+// List returns the registered reports, ordered by id.
+func (catalog *ReportCatalog) List() []ReportType {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	reports := make([]ReportType, 0, len(catalog.reports))
+	for _, entry := range catalog.reports {
+		reports = append(reports, entry.report)
+	}
+	slices.SortFunc(reports, func(a, b ReportType) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return reports
+}
+
+// Schema returns the JSON Schema of the request body of the report id.
+func (catalog *ReportCatalog) Schema(id string) (map[string]any, bool) {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+	entry, ok := catalog.reports[id]
+	if !ok {
+		return nil, false
+	}
+	return entry.schema, true
+}
@@ -0,0 +1,50 @@
+package framework
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MIMEXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	MIMEPdf  = "application/pdf"
+)
+
+// AttachmentFileName builds a download file name such as cpu_usage_report_20240101-20240131.xlsx
+// from the covered date range (unix seconds, rendered in UTC).
+func AttachmentFileName(prefix string, dateFrom int64, dateTo int64, extension string) string {
+	const layout = "20060102"
+	from := time.Unix(dateFrom, 0).UTC().Format(layout)
+	to := time.Unix(dateTo, 0).UTC().Format(layout)
+	return fmt.Sprintf("%s_%s-%s.%s", prefix, from, to, extension)
+}
+
+// WantsJSON reports whether the client asked for the legacy JSON envelope instead of the raw file.
+// The raw file is preferred whenever the Accept header does not explicitly favour JSON.
+func WantsJSON(ctx *gin.Context, contentType string) bool {
+	return ctx.NegotiateFormat(contentType, gin.MIMEJSON) == gin.MIMEJSON
+}
+
+// RespondWithAttachment writes the payload as a downloadable file with the given MIME type.
+func RespondWithAttachment(ctx *gin.Context, contentType string, fileName string, payload []byte) {
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Data(http.StatusOK, contentType, payload)
+}
+
+// StreamAttachment lets write send a downloadable file straight to the response, without buffering it.
+// Errors after the first byte cannot be reported to the client any more; the response is aborted instead.
+func StreamAttachment(ctx *gin.Context, contentType string, fileName string, write func(w io.Writer) error) {
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	ctx.Header("Content-Type", contentType)
+	ctx.Status(http.StatusOK)
+	if err := write(ctx.Writer); err != nil {
+		log.Printf("failed to stream %s: %v", fileName, err)
+		ctx.Abort()
+	}
+}
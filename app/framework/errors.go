@@ -0,0 +1,150 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorKind classifies the failures surfaced by handlers so that they can be mapped to HTTP status codes.
+type ErrorKind string
+
+const (
+	KindValidation          ErrorKind = "validation"
+	KindNotFound            ErrorKind = "not-found"
+	KindUpstreamUnavailable ErrorKind = "upstream-unavailable"
+	KindRenderFailure       ErrorKind = "render-failure"
+	KindTimeout             ErrorKind = "timeout"
+	KindOverloaded          ErrorKind = "overloaded"
+	KindInternal            ErrorKind = "internal"
+)
+
+const mimeProblemJSON = "application/problem+json"
+
+// Error is a classified error. Message describes the failed operation and Err keeps the cause.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewValidationError(message string, err error) *Error {
+	return &Error{Kind: KindValidation, Message: message, Err: err}
+}
+
+func NewNotFoundError(message string, err error) *Error {
+	return &Error{Kind: KindNotFound, Message: message, Err: err}
+}
+
+func NewRenderError(message string, err error) *Error {
+	return &Error{Kind: KindRenderFailure, Message: message, Err: err}
+}
+
+func NewTimeoutError(message string, err error) *Error {
+	return &Error{Kind: KindTimeout, Message: message, Err: err}
+}
+
+// NewUpstreamError classifies a failed call to the data provider, telling deadlines apart from
+// an unavailable or failing provider.
+func NewUpstreamError(message string, err error) *Error {
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return NewTimeoutError(message, err)
+	}
+	return &Error{Kind: KindUpstreamUnavailable, Message: message, Err: err}
+}
+
+// KindOf returns the kind of the first classified error in the chain, KindInternal otherwise.
+func KindOf(err error) ErrorKind {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
+	}
+	return KindInternal
+}
+
+// IsTransient reports whether retrying the failed operation may succeed.
+func IsTransient(err error) bool {
+	kind := KindOf(err)
+	return kind == KindUpstreamUnavailable || kind == KindTimeout
+}
+
+// StatusCode maps an error kind to the HTTP status code returned to clients.
+func StatusCode(kind ErrorKind) int {
+	switch kind {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindUpstreamUnavailable:
+		return http.StatusBadGateway
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindOverloaded:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ProblemDetails is the RFC 7807 body returned for failed requests.
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+// AbortWithProblem stops the request and writes a problem-details body with the given status.
+func AbortWithProblem(ctx *gin.Context, statusCode int, kind ErrorKind, detail string) {
+	ctx.Header("Content-Type", mimeProblemJSON)
+	ctx.AbortWithStatusJSON(statusCode, ProblemDetails{
+		Type:     "/problems/" + string(kind),
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Detail:   detail,
+		Instance: ctx.Request.URL.Path,
+	})
+}
+
+// ErrorMiddleware translates the last error attached with ctx.Error into a problem-details response.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+		err := ctx.Errors.Last().Err
+		kind := KindOf(err)
+		AbortWithProblem(ctx, StatusCode(kind), kind, ErrorDetail(err))
+	}
+}
+
+// ErrorDetail returns the description of err shown to clients: the error itself when it is
+// classified, a generic message otherwise.
+func ErrorDetail(err error) string {
+	if KindOf(err) == KindInternal {
+		// Do not leak internals such as stack-dependent panic messages to clients.
+		return "the report could not be produced"
+	}
+	return err.Error()
+}
@@ -0,0 +1,235 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+const (
+	defaultJobWorkers   = 2
+	defaultJobQueueSize = 64
+	defaultJobTTL       = time.Hour
+)
+
+var ErrJobQueueFull = &Error{Kind: KindOverloaded, Message: "the job queue is full, try again later"}
+
+// JobArtifact is the downloadable file produced by a succeeded job.
+type JobArtifact struct {
+	ContentType string
+	FileName    string
+	Payload     []byte
+}
+
+// JobFunc produces the artifact of a job. It runs on one of the JobManager workers with a context
+// that is cancelled when the manager is closed.
+type JobFunc func(ctx context.Context) (JobArtifact, error)
+
+// Job is a snapshot of a background report job as exposed to clients. A failed job gives the kind
+// of its error, which tells a bad request from a server failure, and the error as ErrorMiddleware
+// would describe it.
+type Job struct {
+	ID         string     `json:"id"`
+	Format     string     `json:"format"`
+	Status     JobStatus  `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	ErrorKind  ErrorKind  `json:"error_kind,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+type jobEntry struct {
+	job      Job
+	run      JobFunc
+	artifact *JobArtifact
+}
+
+// JobManager runs report jobs on a fixed pool of workers and keeps their results until they expire.
+type JobManager struct {
+	mu     sync.RWMutex
+	jobs   map[string]*jobEntry
+	queue  chan *jobEntry
+	ttl    time.Duration
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewJobManager starts the workers and the janitor that evicts completed jobs once their TTL is over.
+func NewJobManager(workers int, queueSize int, ttl time.Duration) *JobManager {
+	if workers <= 0 {
+		workers = defaultJobWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultJobQueueSize
+	}
+	if ttl <= 0 {
+		ttl = defaultJobTTL
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &JobManager{
+		jobs:   make(map[string]*jobEntry),
+		queue:  make(chan *jobEntry, queueSize),
+		ttl:    ttl,
+		ctx:    ctx,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.work()
+	}
+	m.wg.Add(1)
+	go m.evictExpired()
+	return m
+}
+
+// Submit enqueues a job and returns its initial snapshot without waiting for it to run.
+func (m *JobManager) Submit(format string, run JobFunc) (Job, error) {
+	id, err := newUUID()
+	if err != nil {
+		return Job{}, err
+	}
+	entry := &jobEntry{
+		job: Job{
+			ID:        id,
+			Format:    format,
+			Status:    JobQueued,
+			CreatedAt: time.Now().UTC(),
+		},
+		run: run,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case m.queue <- entry:
+		m.jobs[id] = entry
+		return entry.job, nil
+	default:
+		return Job{}, ErrJobQueueFull
+	}
+}
+
+// Get returns the current snapshot of the job.
+func (m *JobManager) Get(id string) (Job, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return entry.job, true
+}
+
+// Result returns the job snapshot together with its artifact, which is only present once the job succeeded.
+func (m *JobManager) Result(id string) (Job, *JobArtifact, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	entry, ok := m.jobs[id]
+	if !ok {
+		return Job{}, nil, false
+	}
+	return entry.job, entry.artifact, true
+}
+
+// Close stops the workers, cancels the running jobs and waits for them to return.
+func (m *JobManager) Close() {
+	close(m.stop)
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *JobManager) work() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case entry := <-m.queue:
+			m.execute(entry)
+		}
+	}
+}
+
+func (m *JobManager) execute(entry *jobEntry) {
+	started := time.Now().UTC()
+	m.mu.Lock()
+	entry.job.Status = JobRunning
+	entry.job.StartedAt = &started
+	m.mu.Unlock()
+
+	artifact, err := runJob(m.ctx, entry.run)
+
+	finished := time.Now().UTC()
+	expires := finished.Add(m.ttl)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry.job.FinishedAt = &finished
+	entry.job.ExpiresAt = &expires
+	entry.run = nil
+	if err != nil {
+		log.Printf("job %s failed: %v", entry.job.ID, err)
+		entry.job.Status = JobFailed
+		entry.job.Error = ErrorDetail(err)
+		entry.job.ErrorKind = KindOf(err)
+		return
+	}
+	entry.job.Status = JobSucceeded
+	entry.artifact = &artifact
+}
+
+// runJob shields the worker from panics raised by the job so that one bad report cannot stop the pool.
+func runJob(ctx context.Context, run JobFunc) (artifact JobArtifact, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("job panicked while rendering the report")
+			log.Printf("recovered from job panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+func (m *JobManager) evictExpired() {
+	defer m.wg.Done()
+	interval := m.ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case now := <-ticker.C:
+			m.evict(now)
+		}
+	}
+}
+
+// evict forgets the completed jobs that expired before now.
+func (m *JobManager) evict(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, entry := range m.jobs {
+		if entry.job.ExpiresAt != nil && now.After(*entry.job.ExpiresAt) {
+			delete(m.jobs, id)
+		}
+	}
+}
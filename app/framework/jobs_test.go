@@ -0,0 +1,165 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// waitForStatus polls the job until it reaches status, failing the test after a second.
+func waitForStatus(t *testing.T, jobs *JobManager, id string, status JobStatus) Job {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := jobs.Get(id)
+		if !ok {
+			t.Fatalf("job %s is unknown", id)
+		}
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobManagerRejectsJobsWhenTheQueueIsFull(t *testing.T) {
+	jobs := NewJobManager(1, 1, time.Hour)
+	release := make(chan struct{})
+	defer jobs.Close()
+	defer close(release)
+	blocked := func(ctx context.Context) (JobArtifact, error) {
+		<-release
+		return JobArtifact{}, nil
+	}
+
+	running, err := jobs.Submit("pdf", blocked)
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	waitForStatus(t, jobs, running.ID, JobRunning)
+	if _, err := jobs.Submit("pdf", blocked); err != nil {
+		t.Fatalf("Submit() of the queued job = %v", err)
+	}
+	if _, err := jobs.Submit("pdf", blocked); !errors.Is(err, ErrJobQueueFull) {
+		t.Fatalf("Submit() with a full queue = %v, want %v", err, ErrJobQueueFull)
+	}
+	if KindOf(ErrJobQueueFull) != KindOverloaded {
+		t.Errorf("KindOf(ErrJobQueueFull) = %v, want %v", KindOf(ErrJobQueueFull), KindOverloaded)
+	}
+}
+
+func TestJobManagerKeepsTheArtifactOfSucceededJobs(t *testing.T) {
+	jobs := NewJobManager(1, 1, time.Hour)
+	defer jobs.Close()
+
+	job, err := jobs.Submit("csv", func(ctx context.Context) (JobArtifact, error) {
+		return JobArtifact{ContentType: "text/csv", FileName: "report.csv", Payload: []byte("a,b\n")}, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	waitForStatus(t, jobs, job.ID, JobSucceeded)
+	done, artifact, ok := jobs.Result(job.ID)
+	if !ok || artifact == nil || string(artifact.Payload) != "a,b\n" {
+		t.Fatalf("Result() = %+v, %+v, %v", done, artifact, ok)
+	}
+	if done.StartedAt == nil || done.FinishedAt == nil || done.ExpiresAt == nil {
+		t.Errorf("Result() = %+v, want start, finish and expiry times", done)
+	}
+}
+
+func TestJobManagerEvictsExpiredJobs(t *testing.T) {
+	jobs := NewJobManager(1, 2, time.Minute)
+	defer jobs.Close()
+	succeed := func(ctx context.Context) (JobArtifact, error) { return JobArtifact{}, nil }
+
+	finished, err := jobs.Submit("xlsx", succeed)
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	job := waitForStatus(t, jobs, finished.ID, JobSucceeded)
+
+	jobs.evict(job.ExpiresAt.Add(-time.Second))
+	if _, ok := jobs.Get(finished.ID); !ok {
+		t.Fatal("the job was evicted before its expiry")
+	}
+	jobs.evict(job.ExpiresAt.Add(time.Second))
+	if _, ok := jobs.Get(finished.ID); ok {
+		t.Fatal("the job was kept after its expiry")
+	}
+}
+
+func TestJobManagerKeepsUnfinishedJobs(t *testing.T) {
+	jobs := NewJobManager(1, 1, time.Minute)
+	release := make(chan struct{})
+	defer jobs.Close()
+	defer close(release)
+
+	running, err := jobs.Submit("xlsx", func(ctx context.Context) (JobArtifact, error) {
+		<-release
+		return JobArtifact{}, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	waitForStatus(t, jobs, running.ID, JobRunning)
+	jobs.evict(time.Now().Add(24 * time.Hour))
+	if _, ok := jobs.Get(running.ID); !ok {
+		t.Fatal("a running job was evicted")
+	}
+}
+
+func TestJobManagerRecoversFromPanickingJobs(t *testing.T) {
+	jobs := NewJobManager(1, 2, time.Hour)
+	defer jobs.Close()
+
+	panicking, err := jobs.Submit("pdf", func(ctx context.Context) (JobArtifact, error) {
+		panic("boom")
+	})
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	failed := waitForStatus(t, jobs, panicking.ID, JobFailed)
+	if failed.Error != "the report could not be produced" || failed.ErrorKind != KindInternal {
+		t.Errorf("the failed job has error %q of kind %q, want the generic internal error", failed.Error, failed.ErrorKind)
+	}
+	if _, artifact, _ := jobs.Result(panicking.ID); artifact != nil {
+		t.Errorf("the failed job has an artifact: %+v", artifact)
+	}
+
+	// The worker survives the panic and runs the next job.
+	next, err := jobs.Submit("pdf", func(ctx context.Context) (JobArtifact, error) { return JobArtifact{}, nil })
+	if err != nil {
+		t.Fatalf("Submit() = %v", err)
+	}
+	waitForStatus(t, jobs, next.ID, JobSucceeded)
+}
+
+func TestJobManagerDescribesErrorsLikeResponses(t *testing.T) {
+	jobs := NewJobManager(1, 2, time.Hour)
+	defer jobs.Close()
+
+	for _, test := range []struct {
+		err    error
+		detail string
+		kind   ErrorKind
+	}{
+		{NewValidationError("invalid period", errors.New("date_to is before date_from")), "invalid period: date_to is before date_from", KindValidation},
+		{NewUpstreamError("failed to read usage", errors.New("connection refused")), "failed to read usage: connection refused", KindUpstreamUnavailable},
+		{fmt.Errorf("open /var/lib/reports/tmp: %w", errors.New("permission denied")), "the report could not be produced", KindInternal},
+	} {
+		job, err := jobs.Submit("csv", func(ctx context.Context) (JobArtifact, error) { return JobArtifact{}, test.err })
+		if err != nil {
+			t.Fatalf("Submit() = %v", err)
+		}
+		failed := waitForStatus(t, jobs, job.ID, JobFailed)
+		if failed.Error != test.detail || failed.ErrorKind != test.kind {
+			t.Errorf("job failing with %v has error %q of kind %q, want %q of kind %q", test.err, failed.Error, failed.ErrorKind, test.detail, test.kind)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// requestSchema builds the JSON Schema of a request struct, and the matching parameter list, from
+// its json, binding and description tags. Fields without a json name are skipped, and the fields of
+// embedded structs are flattened like encoding/json does.
+func requestSchema(requestType reflect.Type) (map[string]any, []ReportParameter) {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           map[string]any{},
+		"required":             []string{},
+		"additionalProperties": false,
+	}
+	parameters := addProperties(schema, requestType)
+	return schema, parameters
+}
+
+func addProperties(schema map[string]any, requestType reflect.Type) []ReportParameter {
+	for requestType.Kind() == reflect.Pointer {
+		requestType = requestType.Elem()
+	}
+	properties := schema["properties"].(map[string]any)
+	var parameters []ReportParameter
+	for i := 0; i < requestType.NumField(); i++ {
+		field := requestType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			parameters = append(parameters, addProperties(schema, field.Type)...)
+			continue
+		}
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		property := typeSchema(field.Type)
+		description := field.Tag.Get("description")
+		if description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+
+		isRequired := strings.Contains(field.Tag.Get("binding"), "required")
+		if isRequired {
+			schema["required"] = append(schema["required"].([]string), name)
+		}
+		typeName, _ := property["type"].(string)
+		parameters = append(parameters, ReportParameter{
+			Name:        name,
+			Type:        typeName,
+			Description: description,
+			Required:    isRequired,
+		})
+	}
+	return parameters
+}
+
+// typeSchema maps a Go type to its JSON Schema.
+func typeSchema(t reflect.Type) map[string]any {
+	if t == durationType {
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		schema, _ := requestSchema(t)
+		return schema
+	default:
+		return map[string]any{}
+	}
+}
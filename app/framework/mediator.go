@@ -1,13 +1,17 @@
 package framework
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"sync"
 )
 
 var (
 	registeredHandlers sync.Map
+	behaviorsMu        sync.RWMutex
+	behaviors          []PipelineBehavior
 )
 
 func init() {
@@ -17,8 +21,14 @@ func init() {
 type key[TRequest any, TResult any] struct {
 }
 
-// Register registers the provided request handler to be used for the corresponding requests
+// Register registers the provided request handler to be used for the corresponding requests.
+// Handlers that do not take a context are adapted with WithoutContext.
 func Register[TRequest any, TResult any](handler RequestHandler[TRequest, TResult]) error {
+	return RegisterWithContext[TRequest, TResult](WithoutContext(handler))
+}
+
+// RegisterWithContext registers the provided context-aware request handler to be used for the corresponding requests
+func RegisterWithContext[TRequest any, TResult any](handler ContextRequestHandler[TRequest, TResult]) error {
 	k := key[TRequest, TResult]{}
 	_, existed := registeredHandlers.LoadOrStore(reflect.TypeOf(k), handler)
 	if existed {
@@ -27,22 +37,104 @@ func Register[TRequest any, TResult any](handler RequestHandler[TRequest, TResul
 	return nil
 }
 
+// RegisterBehaviors appends pipeline behaviors that wrap every handler invoked through Send.
+// Behaviors run in registration order: the first one registered is the outermost.
+func RegisterBehaviors(pipelineBehaviors ...PipelineBehavior) {
+	behaviorsMu.Lock()
+	defer behaviorsMu.Unlock()
+	behaviors = append(behaviors, pipelineBehaviors...)
+}
+
 // Send processes the provided request and returns the produced result
 func Send[TRequest any, TResult any](r TRequest) (TResult, error) {
+	return SendWithContext[TRequest, TResult](context.Background(), r)
+}
+
+// SendWithContext processes the provided request and returns the produced result. The context is passed
+// through every pipeline behavior down to the handler, so cancelling it aborts the work in progress.
+func SendWithContext[TRequest any, TResult any](ctx context.Context, r TRequest) (TResult, error) {
 	var zeroRes TResult
 	var k key[TRequest, TResult]
 	handler, ok := registeredHandlers.Load(reflect.TypeOf(k))
 	if !ok {
-		return zeroRes, errors.New("could not find zeroRes handler for this function")
+		return zeroRes, NewNotFoundError(fmt.Sprintf("no handler registered for %T", r), nil)
 	}
 	switch handler := handler.(type) {
-	case RequestHandler[TRequest, TResult]:
-		return handler.Handle(r)
+	case ContextRequestHandler[TRequest, TResult]:
+		result, err := pipeline(r, func(ctx context.Context) (any, error) {
+			return handler.Handle(ctx, r)
+		})(ctx)
+		if err != nil {
+			return zeroRes, err
+		}
+		// A behavior that short-circuits without a result leaves the zero value.
+		if result == nil {
+			return zeroRes, nil
+		}
+		typed, ok := result.(TResult)
+		if !ok {
+			return zeroRes, fmt.Errorf("pipeline returned %T instead of %T", result, zeroRes)
+		}
+		return typed, nil
 	}
 	return zeroRes, errors.New("Invalid handler")
 }
 
+// pipeline wraps the handler call with the registered behaviors, outermost first.
+func pipeline(request any, handle RequestHandlerFunc) RequestHandlerFunc {
+	behaviorsMu.RLock()
+	defer behaviorsMu.RUnlock()
+	next := handle
+	for i := len(behaviors) - 1; i >= 0; i-- {
+		behavior, inner := behaviors[i], next
+		next = func(ctx context.Context) (any, error) {
+			return behavior.Handle(ctx, request, inner)
+		}
+	}
+	return next
+}
+
 // RequestHandler handles TRequest and returns TResult
 type RequestHandler[TRequest any, TResult any] interface {
 	Handle(request TRequest) (TResult, error)
 }
+
+// ContextRequestHandler handles TRequest within the given context and returns TResult
+type ContextRequestHandler[TRequest any, TResult any] interface {
+	Handle(ctx context.Context, request TRequest) (TResult, error)
+}
+
+// WithoutContext adapts a RequestHandler to a ContextRequestHandler. The adapted handler cannot be
+// interrupted once started, but it is not invoked at all when the context is already done.
+func WithoutContext[TRequest any, TResult any](handler RequestHandler[TRequest, TResult]) ContextRequestHandler[TRequest, TResult] {
+	return requestHandlerAdapter[TRequest, TResult]{handler: handler}
+}
+
+type requestHandlerAdapter[TRequest any, TResult any] struct {
+	handler RequestHandler[TRequest, TResult]
+}
+
+func (adapter requestHandlerAdapter[TRequest, TResult]) Handle(ctx context.Context, request TRequest) (TResult, error) {
+	if err := ctx.Err(); err != nil {
+		var zeroRes TResult
+		return zeroRes, err
+	}
+	return adapter.handler.Handle(request)
+}
+
+// RequestHandlerFunc invokes the next step of the pipeline: another behavior or the handler itself.
+type RequestHandlerFunc func(ctx context.Context) (any, error)
+
+// PipelineBehavior wraps the handling of a request. It may act before and after calling next,
+// or short-circuit by returning without calling it; a nil result without an error then yields the
+// zero result. The context it passes to next reaches the handler.
+type PipelineBehavior interface {
+	Handle(ctx context.Context, request any, next RequestHandlerFunc) (any, error)
+}
+
+// PipelineBehaviorFunc adapts an ordinary function to a PipelineBehavior.
+type PipelineBehaviorFunc func(ctx context.Context, request any, next RequestHandlerFunc) (any, error)
+
+func (f PipelineBehaviorFunc) Handle(ctx context.Context, request any, next RequestHandlerFunc) (any, error) {
+	return f(ctx, request, next)
+}
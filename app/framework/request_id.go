@@ -0,0 +1,60 @@
+package framework
+
+import (
+	"context"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, both from the client and back in the response.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware gives every request an ID: the one sent by the client in RequestIDHeader when
+// it is usable, or a new UUID. The ID is echoed in the response and stored in the request context,
+// see RequestID.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			var err error
+			if id, err = newUUID(); err != nil {
+				log.Printf("failed to generate request ID: %v", err)
+				id = ""
+			}
+		}
+		if id != "" {
+			ctx.Header(RequestIDHeader, id)
+			ctx.Request = ctx.Request.WithContext(WithRequestID(ctx.Request.Context(), id))
+		}
+		ctx.Next()
+	}
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID id, e.g. for the background job of a request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether a client-provided ID is short printable ASCII, safe to echo and log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package framework
 
 import (
+	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 )
@@ -32,3 +33,13 @@ func parseUUID(src string) (dst [16]byte, err error) {
 	return dst, err
 }
 
+// newUUID returns a random (version 4) UUID in its canonical textual form.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("cannot generate UUID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
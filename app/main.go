@@ -1,24 +1,152 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
 	"github.com/Javier-Godon/reports-rendering-go/framework"
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+	"github.com/Javier-Godon/reports-rendering-go/reportdef"
+	health "github.com/Javier-Godon/reports-rendering-go/usecases/health/rest"
+	renderChartMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_chart/mediator"
+	renderChart "github.com/Javier-Godon/reports-rendering-go/usecases/render_chart/rest"
+	renderFullCsvMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_csv/mediator"
+	renderFullCsv "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_csv/rest"
+	renderFullHtmlMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html/mediator"
+	renderFullHtml "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html/rest"
+	renderFullPdfMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf/mediator"
 	rendeRFullPdf "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf/rest"
+	renderFullXlsxMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx/mediator"
 	renderFullXlsx "github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx/rest"
+	renderReportMediator "github.com/Javier-Godon/reports-rendering-go/usecases/render_report/mediator"
+	renderReport "github.com/Javier-Godon/reports-rendering-go/usecases/render_report/rest"
+	reportCatalog "github.com/Javier-Godon/reports-rendering-go/usecases/report_catalog/rest"
+	reportJobs "github.com/Javier-Godon/reports-rendering-go/usecases/report_jobs/rest"
+	requestMetrics "github.com/Javier-Godon/reports-rendering-go/usecases/request_metrics/rest"
 
 	"github.com/gin-gonic/gin"
 )
 
+const defaultShutdownTimeout = 30 * time.Second
+
 func main() {
 	framework.ReadConfig()
 	serverPort := framework.AppConfig.ServerPort.PORT
 
-	router := gin.Default()
-	rendeRFullPdf.RouteRenderFullPdf(router)
-	renderFullXlsx.RouteRenderFullXlsx(router)
+	// The application owns a single pool of long-lived connections to the data provider.
+	var pool *proto.GRPCClientPool
+	if datasource.UsesGRPC(framework.AppConfig) {
+		var err error
+		pool, err = proto.NewGRPCClientPool(framework.AppConfig.DataProvider.ADDRESS, framework.AppConfig.DataProvider.POOL_SIZE)
+		if err != nil {
+			log.Fatal("cannot create gRPC client pool: ", err)
+		}
+		defer pool.Close()
+	}
+
+	source, err := datasource.NewFromConfig(framework.AppConfig, pool)
+	if err != nil {
+		log.Fatal("cannot create data source: ", err)
+	}
+	defer source.Close()
 
-	err := router.Run("0.0.0.0:" + serverPort)
+	policy, err := datasource.ParseFailurePolicy(framework.AppConfig.Report.PARTIAL_FAILURE)
 	if err != nil {
-		log.Fatal("cannot start server: ", err)
+		log.Fatal("invalid report configuration: ", err)
+	}
+	// An empty time zone loads as UTC.
+	location, err := time.LoadLocation(framework.AppConfig.Report.TIMEZONE)
+	if err != nil {
+		log.Fatal("invalid report time zone: ", err)
+	}
+	if err := renderFullPdfMediator.Register(source, policy, framework.AppConfig.Report.OVERVIEW_THRESHOLD, location); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+	streaming := render_xlsx.StreamOptions{
+		Threshold: framework.AppConfig.Report.XLSX_STREAM_THRESHOLD,
+		RowLimit:  framework.AppConfig.Report.XLSX_SHEET_ROW_LIMIT,
+	}
+	if err := renderFullXlsxMediator.Register(source, policy, streaming, framework.AppConfig.Report.OVERVIEW_THRESHOLD, location); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+	if err := renderFullCsvMediator.Register(source, policy, location); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+	if err := renderFullHtmlMediator.Register(source, policy, location); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+	if err := renderChartMediator.Register(); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+	definitions, err := reportdef.LoadDir(framework.AppConfig.Report.DEFINITIONS_DIR)
+	if err != nil {
+		log.Fatal("cannot load report definitions: ", err)
+	}
+	log.Printf("loaded %d report definitions", len(definitions.List()))
+	if err := renderReportMediator.Register(definitions, source, policy, streaming, location); err != nil {
+		log.Fatal("cannot register handler: ", err)
+	}
+
+	metrics := framework.NewRequestMetrics()
+	framework.RegisterBehaviors(
+		framework.RecoveryBehavior(),
+		framework.LoggingBehavior(),
+		framework.MetricsBehavior(metrics),
+		framework.ValidationBehavior(),
+	)
+
+	jobsConfig := framework.AppConfig.Jobs
+	jobs := framework.NewJobManager(jobsConfig.WORKERS, jobsConfig.QUEUE_SIZE, jobsConfig.TTL)
+	defer jobs.Close()
+
+	catalog := framework.NewReportCatalog()
+	router := gin.Default()
+	router.Use(framework.RequestIDMiddleware())
+	router.Use(framework.ErrorMiddleware())
+	rendeRFullPdf.RouteRenderFullPdf(router, catalog)
+	rendeRFullPdf.RouteRenderFullPdfJobs(router, jobs, catalog)
+	renderFullXlsx.RouteRenderFullXlsx(router, catalog)
+	renderFullXlsx.RouteRenderFullXlsxJobs(router, jobs, catalog)
+	renderFullCsv.RouteRenderFullCsv(router, catalog)
+	renderFullCsv.RouteRenderFullCsvJobs(router, jobs, catalog)
+	renderFullHtml.RouteRenderFullHtml(router, catalog)
+	renderFullHtml.RouteRenderFullHtmlJobs(router, jobs, catalog)
+	renderChart.RouteRenderChart(router)
+	renderReport.RouteRenderReport(router, definitions, catalog)
+	reportCatalog.RouteReportCatalog(router, catalog)
+	reportJobs.RouteReportJobs(router, jobs)
+	requestMetrics.RouteRequestMetrics(router, metrics)
+	health.RouteHealth(router, pool)
+
+	server := &http.Server{Addr: "0.0.0.0:" + serverPort, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("cannot start server: ", err)
+		}
+	}()
+
+	// Wait for a termination signal, then let in-flight requests finish before the deferred
+	// cleanups stop the jobs and close the data provider connections.
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	<-stop.Done()
+	log.Println("shutting down")
+
+	shutdownTimeout := framework.AppConfig.ServerPort.SHUTDOWN_TIMEOUT
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server shutdown: %v", err)
 	}
 }
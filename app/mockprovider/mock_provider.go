@@ -0,0 +1,440 @@
+package mockprovider
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+	pb_system "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_system_usage"
+	pb_user "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_user_usage"
+	pb_disk "github.com/Javier-Godon/reports-rendering-go/proto/get_disk_usage"
+	pb_memory "github.com/Javier-Godon/reports-rendering-go/proto/get_memory_usage"
+	pb_network "github.com/Javier-Godon/reports-rendering-go/proto/get_network_usage"
+)
+
+const (
+	defaultCPUs    = 8
+	defaultVersion = "mock"
+)
+
+// Options configures the data served by the mock provider and the faults it injects.
+type Options struct {
+	// Seed makes the synthetic data deterministic: the same seed and period always produce the same usages.
+	Seed int64
+	// CPUs is the number of synthetic CPUs (cpu0..cpuN-1). Defaults to 8.
+	CPUs int
+	// FixtureDir, when set, replays the fixtures of that directory (same layout as the file data source)
+	// instead of generating synthetic data.
+	FixtureDir string
+	// Latency delays every response, so that client deadlines can be exercised.
+	Latency time.Duration
+	// ErrorRate is the probability, between 0 and 1, that a call fails with ErrorCode.
+	ErrorRate float64
+	// ErrorCode is the status code of injected failures. Defaults to codes.Unavailable.
+	ErrorCode codes.Code
+	// Version is reported in the provider version header of every response. Defaults to "mock".
+	Version string
+}
+
+// Server serves the CPU (aggregates and timelines), memory, disk and network usage services from
+// synthetic or fixture data.
+type Server struct {
+	opts       Options
+	fixtures   *datasource.MemoryDataSource
+	grpcServer *grpc.Server
+	listener   net.Listener
+
+	mu     sync.Mutex
+	faults *rand.Rand
+	calls  atomic.Int64
+}
+
+// New creates a mock provider. It does not listen until Serve or Start is called.
+func New(opts Options) (*Server, error) {
+	if opts.CPUs <= 0 {
+		opts.CPUs = defaultCPUs
+	}
+	if opts.ErrorCode == codes.OK {
+		opts.ErrorCode = codes.Unavailable
+	}
+	if opts.Version == "" {
+		opts.Version = defaultVersion
+	}
+
+	server := &Server{
+		opts:   opts,
+		faults: rand.New(rand.NewSource(opts.Seed)),
+	}
+	if opts.FixtureDir != "" {
+		server.fixtures = datasource.NewMemoryDataSource()
+		if err := server.fixtures.LoadFrom(datasource.NewFileDataSource(opts.FixtureDir)); err != nil {
+			return nil, fmt.Errorf("failed to load fixtures: %w", err)
+		}
+	}
+
+	server.grpcServer = grpc.NewServer(grpc.UnaryInterceptor(server.reportVersion))
+	pb_system.RegisterGetCpuSystemUsageServiceServer(server.grpcServer, &systemUsageService{server: server})
+	pb_user.RegisterGetCpuUserUsageServiceServer(server.grpcServer, &userUsageService{server: server})
+	pb_memory.RegisterGetMemoryUsageServiceServer(server.grpcServer, &memoryUsageService{server: server})
+	pb_disk.RegisterGetDiskUsageServiceServer(server.grpcServer, &diskUsageService{server: server})
+	pb_network.RegisterGetNetworkUsageServiceServer(server.grpcServer, &networkUsageService{server: server})
+	return server, nil
+}
+
+// reportVersion sends the provider version in the response headers of every call.
+func (s *Server) reportVersion(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(proto.ProviderVersionHeader, s.opts.Version)); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// Start listens on address (use "127.0.0.1:0" for a random port) and serves in the background.
+func Start(address string, opts Options) (*Server, error) {
+	server, err := New(opts)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	server.listener = listener
+	go func() {
+		if err := server.grpcServer.Serve(listener); err != nil {
+			log.Printf("mock provider stopped: %v", err)
+		}
+	}()
+	return server, nil
+}
+
+// Serve blocks serving requests on listener until Stop is called.
+func (s *Server) Serve(listener net.Listener) error {
+	s.listener = listener
+	return s.grpcServer.Serve(listener)
+}
+
+// Addr returns the address the server listens on, to be used as data-provider address.
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Stop closes the listener and aborts the in-flight calls.
+func (s *Server) Stop() {
+	s.grpcServer.Stop()
+}
+
+// Calls returns the number of calls received so far, including the failed ones, so that tests can
+// count the attempts of a client.
+func (s *Server) Calls() int64 {
+	return s.calls.Load()
+}
+
+// inject applies the configured latency and faults to a call for dataset.
+func (s *Server) inject(ctx context.Context, dataset string) error {
+	s.calls.Add(1)
+	if s.opts.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(s.opts.Latency):
+		}
+	}
+	if s.shouldFail() {
+		return status.Errorf(s.opts.ErrorCode, "injected failure for %s", dataset)
+	}
+	return nil
+}
+
+// cpuUsage applies the configured latency and faults, then returns the usages of kind for the period.
+func (s *Server) cpuUsage(ctx context.Context, kind datasource.UsageKind, dateFrom int64, dateTo int64) ([]datasource.CpuUsage, error) {
+	if err := s.inject(ctx, fmt.Sprintf("cpu %s usage", kind)); err != nil {
+		return nil, err
+	}
+	if s.fixtures != nil {
+		return s.fixtures.CpuUsage(ctx, kind, dateFrom, dateTo)
+	}
+	return s.generate(kind, dateFrom, dateTo), nil
+}
+
+// cpuUsageTimeline is the timeline counterpart of cpuUsage. step is in seconds.
+func (s *Server) cpuUsageTimeline(ctx context.Context, kind datasource.UsageKind, dateFrom int64, dateTo int64, step int64) ([]datasource.CpuUsageTimeline, error) {
+	if err := datasource.ValidateTimelineStep(dateFrom, dateTo, time.Duration(step)*time.Second); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.inject(ctx, fmt.Sprintf("cpu %s usage timeline", kind)); err != nil {
+		return nil, err
+	}
+	if s.fixtures != nil {
+		return s.fixtures.CpuUsageTimeline(ctx, kind, dateFrom, dateTo, time.Duration(step)*time.Second)
+	}
+	return s.generateTimeline(kind, dateFrom, dateTo, step), nil
+}
+
+// memoryUsage applies the configured latency and faults, then returns the memory usage for the period.
+func (s *Server) memoryUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]datasource.MemoryUsage, error) {
+	if err := s.inject(ctx, "memory usage"); err != nil {
+		return nil, err
+	}
+	if s.fixtures != nil {
+		return s.fixtures.MemoryUsage(ctx, dateFrom, dateTo)
+	}
+	rng := s.periodRand("memory", dateFrom, dateTo)
+	const total = 64 << 30
+	usages := make([]datasource.MemoryUsage, 0, 3)
+	for _, metric := range []struct {
+		name  string
+		share float64
+	}{{datasource.MemoryUsed, 0.5}, {datasource.MemoryCached, 0.2}, {datasource.MemorySwap, 0.02}} {
+		avg := total * metric.share * (0.5 + rng.Float64())
+		usages = append(usages, datasource.MemoryUsage{
+			Metric:   metric.name,
+			AvgBytes: math.Round(avg),
+			MaxBytes: math.Round(avg * (1 + rng.Float64()*0.5)),
+			MinBytes: math.Round(avg * rng.Float64() * 0.5),
+		})
+	}
+	return usages, nil
+}
+
+// diskUsage applies the configured latency and faults, then returns the disk usage for the period.
+func (s *Server) diskUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]datasource.DiskUsage, error) {
+	if err := s.inject(ctx, "disk usage"); err != nil {
+		return nil, err
+	}
+	if s.fixtures != nil {
+		return s.fixtures.DiskUsage(ctx, dateFrom, dateTo)
+	}
+	rng := s.periodRand("disk", dateFrom, dateTo)
+	devices := []string{"nvme0n1", "nvme1n1", "sda"}
+	usages := make([]datasource.DiskUsage, len(devices))
+	for i, device := range devices {
+		usages[i] = datasource.DiskUsage{
+			Device:              device,
+			ReadBytesPerSecond:  math.Round(rng.Float64() * (200 << 20)),
+			WriteBytesPerSecond: math.Round(rng.Float64() * (100 << 20)),
+			ReadIOPS:            math.Round(rng.Float64()*50000) / 10,
+			WriteIOPS:           math.Round(rng.Float64()*30000) / 10,
+		}
+	}
+	return usages, nil
+}
+
+// networkUsage applies the configured latency and faults, then returns the network usage for the period.
+func (s *Server) networkUsage(ctx context.Context, dateFrom int64, dateTo int64) ([]datasource.NetworkUsage, error) {
+	if err := s.inject(ctx, "network usage"); err != nil {
+		return nil, err
+	}
+	if s.fixtures != nil {
+		return s.fixtures.NetworkUsage(ctx, dateFrom, dateTo)
+	}
+	rng := s.periodRand("network", dateFrom, dateTo)
+	interfaces := []string{"eth0", "eth1", "lo"}
+	usages := make([]datasource.NetworkUsage, len(interfaces))
+	for i, name := range interfaces {
+		usages[i] = datasource.NetworkUsage{
+			Interface: name,
+			RxBytes:   rng.Int63n(200 << 30),
+			TxBytes:   rng.Int63n(100 << 30),
+			RxErrors:  rng.Int63n(20),
+			TxErrors:  rng.Int63n(5),
+		}
+	}
+	return usages, nil
+}
+
+// periodRand returns a generator seeded from the seed, the dataset and the period only, so repeated calls agree.
+func (s *Server) periodRand(dataset string, dateFrom int64, dateTo int64) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d/%d", s.opts.Seed, dataset, dateFrom, dateTo)
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func (s *Server) shouldFail() bool {
+	if s.opts.ErrorRate <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.faults.Float64() < s.opts.ErrorRate
+}
+
+// generate derives the usages from the seed, the kind and the period only, so repeated calls agree.
+func (s *Server) generate(kind datasource.UsageKind, dateFrom int64, dateTo int64) []datasource.CpuUsage {
+	rng := s.periodRand(string(kind), dateFrom, dateTo)
+
+	usages := make([]datasource.CpuUsage, s.opts.CPUs)
+	for i := range usages {
+		avg := 0.05 + rng.Float64()*0.55
+		usages[i] = datasource.CpuUsage{
+			CPU:      fmt.Sprintf("cpu%d", i),
+			AvgUsage: avg,
+			MaxUsage: avg + rng.Float64()*(1-avg),
+			MinUsage: avg * rng.Float64() * 0.5,
+		}
+	}
+	return usages
+}
+
+// generateTimeline produces a daily cycle per CPU with occasional spikes. Every bucket is derived from
+// the seed, the kind, the CPU and its own timestamp, so the same bucket looks the same in every request.
+func (s *Server) generateTimeline(kind datasource.UsageKind, dateFrom int64, dateTo int64, step int64) []datasource.CpuUsageTimeline {
+	timelines := make([]datasource.CpuUsageTimeline, s.opts.CPUs)
+	for i := range timelines {
+		cpu := fmt.Sprintf("cpu%d", i)
+		timeline := datasource.CpuUsageTimeline{CPU: cpu}
+		for bucket := dateFrom - dateFrom%step; bucket < dateTo; bucket += step {
+			h := fnv.New64a()
+			fmt.Fprintf(h, "%d/%s/%s/%d", s.opts.Seed, kind, cpu, bucket)
+			rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+			avg := 0.25 + 0.15*math.Sin(2*math.Pi*float64(bucket%86400)/86400+float64(i)) + rng.Float64()*0.1
+			if rng.Float64() < 0.03 {
+				avg += 0.5 * rng.Float64()
+			}
+			avg = math.Min(math.Max(avg, 0.01), 1)
+			timeline.Samples = append(timeline.Samples, datasource.CpuUsageSample{
+				Timestamp: bucket,
+				AvgUsage:  avg,
+				MaxUsage:  avg + rng.Float64()*(1-avg),
+				MinUsage:  avg * rng.Float64(),
+			})
+		}
+		timelines[i] = timeline
+	}
+	return timelines
+}
+
+type systemUsageService struct {
+	pb_system.UnimplementedGetCpuSystemUsageServiceServer
+	server *Server
+}
+
+func (service *systemUsageService) GetCpuSystemUsage(ctx context.Context, req *pb_system.GetCpuSystemUsageRequest) (*pb_system.GetCpuSystemUsageResponse, error) {
+	usages, err := service.server.cpuUsage(ctx, datasource.CpuSystem, req.GetDateFrom(), req.GetDateTo())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_system.GetCpuSystemUsageResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb_system.CpuUsage{Cpu: u.CPU, AvgUsage: u.AvgUsage, MaxUsage: u.MaxUsage, MinUsage: u.MinUsage})
+	}
+	return resp, nil
+}
+
+func (service *systemUsageService) GetCpuSystemUsageTimeline(ctx context.Context, req *pb_system.GetCpuSystemUsageTimelineRequest) (*pb_system.GetCpuSystemUsageTimelineResponse, error) {
+	timelines, err := service.server.cpuUsageTimeline(ctx, datasource.CpuSystem, req.GetDateFrom(), req.GetDateTo(), req.GetStep())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_system.GetCpuSystemUsageTimelineResponse{}
+	for _, t := range timelines {
+		timeline := &pb_system.CpuUsageTimeline{Cpu: t.CPU}
+		for _, u := range t.Samples {
+			timeline.Samples = append(timeline.Samples, &pb_system.CpuUsageSample{Timestamp: u.Timestamp, AvgUsage: u.AvgUsage, MaxUsage: u.MaxUsage, MinUsage: u.MinUsage})
+		}
+		resp.Timelines = append(resp.Timelines, timeline)
+	}
+	return resp, nil
+}
+
+type userUsageService struct {
+	pb_user.UnimplementedGetCpuUserUsageServiceServer
+	server *Server
+}
+
+func (service *userUsageService) GetCpuUserUsage(ctx context.Context, req *pb_user.GetCpuUserUsageRequest) (*pb_user.GetCpuUserUsageResponse, error) {
+	usages, err := service.server.cpuUsage(ctx, datasource.CpuUser, req.GetDateFrom(), req.GetDateTo())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_user.GetCpuUserUsageResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb_user.CpuUsage{Cpu: u.CPU, AvgUsage: u.AvgUsage, MaxUsage: u.MaxUsage, MinUsage: u.MinUsage})
+	}
+	return resp, nil
+}
+
+func (service *userUsageService) GetCpuUserUsageTimeline(ctx context.Context, req *pb_user.GetCpuUserUsageTimelineRequest) (*pb_user.GetCpuUserUsageTimelineResponse, error) {
+	timelines, err := service.server.cpuUsageTimeline(ctx, datasource.CpuUser, req.GetDateFrom(), req.GetDateTo(), req.GetStep())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_user.GetCpuUserUsageTimelineResponse{}
+	for _, t := range timelines {
+		timeline := &pb_user.CpuUsageTimeline{Cpu: t.CPU}
+		for _, u := range t.Samples {
+			timeline.Samples = append(timeline.Samples, &pb_user.CpuUsageSample{Timestamp: u.Timestamp, AvgUsage: u.AvgUsage, MaxUsage: u.MaxUsage, MinUsage: u.MinUsage})
+		}
+		resp.Timelines = append(resp.Timelines, timeline)
+	}
+	return resp, nil
+}
+
+type memoryUsageService struct {
+	pb_memory.UnimplementedGetMemoryUsageServiceServer
+	server *Server
+}
+
+func (service *memoryUsageService) GetMemoryUsage(ctx context.Context, req *pb_memory.GetMemoryUsageRequest) (*pb_memory.GetMemoryUsageResponse, error) {
+	usages, err := service.server.memoryUsage(ctx, req.GetDateFrom(), req.GetDateTo())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_memory.GetMemoryUsageResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb_memory.MemoryUsage{Metric: u.Metric, AvgBytes: u.AvgBytes, MaxBytes: u.MaxBytes, MinBytes: u.MinBytes})
+	}
+	return resp, nil
+}
+
+type diskUsageService struct {
+	pb_disk.UnimplementedGetDiskUsageServiceServer
+	server *Server
+}
+
+func (service *diskUsageService) GetDiskUsage(ctx context.Context, req *pb_disk.GetDiskUsageRequest) (*pb_disk.GetDiskUsageResponse, error) {
+	usages, err := service.server.diskUsage(ctx, req.GetDateFrom(), req.GetDateTo())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_disk.GetDiskUsageResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb_disk.DiskUsage{Device: u.Device, ReadBytesPerSecond: u.ReadBytesPerSecond, WriteBytesPerSecond: u.WriteBytesPerSecond, ReadIops: u.ReadIOPS, WriteIops: u.WriteIOPS})
+	}
+	return resp, nil
+}
+
+type networkUsageService struct {
+	pb_network.UnimplementedGetNetworkUsageServiceServer
+	server *Server
+}
+
+func (service *networkUsageService) GetNetworkUsage(ctx context.Context, req *pb_network.GetNetworkUsageRequest) (*pb_network.GetNetworkUsageResponse, error) {
+	usages, err := service.server.networkUsage(ctx, req.GetDateFrom(), req.GetDateTo())
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb_network.GetNetworkUsageResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, &pb_network.NetworkUsage{Interface: u.Interface, RxBytes: u.RxBytes, TxBytes: u.TxBytes, RxErrors: u.RxErrors, TxErrors: u.TxErrors})
+	}
+	return resp, nil
+}
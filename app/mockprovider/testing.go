@@ -0,0 +1,17 @@
+package mockprovider
+
+import (
+	"testing"
+)
+
+// StartForTest starts a mock provider on a random local port and stops it when the test ends.
+// Point the data-provider address (or proto.NewGRPCClient) at the returned server's Addr.
+func StartForTest(t testing.TB, opts Options) *Server {
+	t.Helper()
+	server, err := Start("127.0.0.1:0", opts)
+	if err != nil {
+		t.Fatalf("failed to start mock provider: %v", err)
+	}
+	t.Cleanup(server.Stop)
+	return server
+}
@@ -2,43 +2,80 @@ package grpc_client
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sync"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 
 	pb_system "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_system_usage"
 	pb_user "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_user_usage"
+	pb_disk "github.com/Javier-Godon/reports-rendering-go/proto/get_disk_usage"
+	pb_memory "github.com/Javier-Godon/reports-rendering-go/proto/get_memory_usage"
+	pb_network "github.com/Javier-Godon/reports-rendering-go/proto/get_network_usage"
 )
 
+// ProviderVersionHeader is the response header in which the data provider reports its version.
+const ProviderVersionHeader = "x-provider-version"
+
 // GRPCClient holds the gRPC connection and client stubs.  It's safe for concurrent use.
 type GRPCClient struct {
-	conn         *grpc.ClientConn
-	systemClient pb_system.GetCpuSystemUsageServiceClient
-	userClient   pb_user.GetCpuUserUsageServiceClient
+	conn          *grpc.ClientConn
+	version       atomic.Pointer[string]
+	systemClient  pb_system.GetCpuSystemUsageServiceClient
+	userClient    pb_user.GetCpuUserUsageServiceClient
+	memoryClient  pb_memory.GetMemoryUsageServiceClient
+	diskClient    pb_disk.GetDiskUsageServiceClient
+	networkClient pb_network.GetNetworkUsageServiceClient
 }
 
 // NewGRPCClient creates a new GRPCClient.
 func NewGRPCClient(address string) (*GRPCClient, error) {
+	client := &GRPCClient{}
 	// Use insecure.NewCredentials() for a non-secure connection.  For production, use appropriate credentials.
-	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(client.recordVersion),
+	)
 	if err != nil {
 		log.Printf("failed to connect to gRPC server: %v", err)
 		return nil, err // Important: Return the error!
 	}
 
-	client := &GRPCClient{
-		conn:         conn,
-		systemClient: pb_system.NewGetCpuSystemUsageServiceClient(conn),
-		userClient:   pb_user.NewGetCpuUserUsageServiceClient(conn),
-	}
+	client.conn = conn
+	client.systemClient = pb_system.NewGetCpuSystemUsageServiceClient(conn)
+	client.userClient = pb_user.NewGetCpuUserUsageServiceClient(conn)
+	client.memoryClient = pb_memory.NewGetMemoryUsageServiceClient(conn)
+	client.diskClient = pb_disk.NewGetDiskUsageServiceClient(conn)
+	client.networkClient = pb_network.NewGetNetworkUsageServiceClient(conn)
 	return client, nil
 }
 
+// recordVersion keeps the provider version reported in the response headers of every call.
+func (c *GRPCClient) recordVersion(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	if values := header.Get(ProviderVersionHeader); len(values) > 0 {
+		c.version.Store(&values[0])
+	}
+	return err
+}
+
+// Version returns the provider version reported by the last call that reported one, or "".
+func (c *GRPCClient) Version() string {
+	if version := c.version.Load(); version != nil {
+		return *version
+	}
+	return ""
+}
+
 // GetCpuSystemUsage retrieves CPU system usage.
 func (c *GRPCClient) GetCpuSystemUsage(ctx context.Context, dateFrom int64, dateTo int64) (*pb_system.GetCpuSystemUsageResponse, error) {
-	
+
 	req := &pb_system.GetCpuSystemUsageRequest{
 		DateFrom: dateFrom,
 		DateTo:   dateTo,
@@ -54,7 +91,7 @@ func (c *GRPCClient) GetCpuSystemUsage(ctx context.Context, dateFrom int64, date
 
 // GetCpuUserUsage retrieves CPU user usage.
 func (c *GRPCClient) GetCpuUserUsage(ctx context.Context, dateFrom int64, dateTo int64) (*pb_user.GetCpuUserUsageResponse, error) {
-	
+
 	req := &pb_user.GetCpuUserUsageRequest{
 		DateFrom: dateFrom,
 		DateTo:   dateTo,
@@ -68,24 +105,192 @@ func (c *GRPCClient) GetCpuUserUsage(ctx context.Context, dateFrom int64, dateTo
 	return resp, nil
 }
 
+// GetCpuSystemUsageTimeline retrieves CPU system usage samples bucketed by step seconds.
+func (c *GRPCClient) GetCpuSystemUsageTimeline(ctx context.Context, dateFrom int64, dateTo int64, step int64) (*pb_system.GetCpuSystemUsageTimelineResponse, error) {
+	req := &pb_system.GetCpuSystemUsageTimelineRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Step:     step,
+	}
+
+	resp, err := c.systemClient.GetCpuSystemUsageTimeline(ctx, req)
+	if err != nil {
+		log.Printf("failed to get cpu system usage timeline: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetCpuUserUsageTimeline retrieves CPU user usage samples bucketed by step seconds.
+func (c *GRPCClient) GetCpuUserUsageTimeline(ctx context.Context, dateFrom int64, dateTo int64, step int64) (*pb_user.GetCpuUserUsageTimelineResponse, error) {
+	req := &pb_user.GetCpuUserUsageTimelineRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+		Step:     step,
+	}
+
+	resp, err := c.userClient.GetCpuUserUsageTimeline(ctx, req)
+	if err != nil {
+		log.Printf("failed to get cpu user usage timeline: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMemoryUsage retrieves memory usage.
+func (c *GRPCClient) GetMemoryUsage(ctx context.Context, dateFrom int64, dateTo int64) (*pb_memory.GetMemoryUsageResponse, error) {
+	req := &pb_memory.GetMemoryUsageRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+
+	resp, err := c.memoryClient.GetMemoryUsage(ctx, req)
+	if err != nil {
+		log.Printf("failed to get memory usage: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetDiskUsage retrieves disk I/O per device.
+func (c *GRPCClient) GetDiskUsage(ctx context.Context, dateFrom int64, dateTo int64) (*pb_disk.GetDiskUsageResponse, error) {
+	req := &pb_disk.GetDiskUsageRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+
+	resp, err := c.diskClient.GetDiskUsage(ctx, req)
+	if err != nil {
+		log.Printf("failed to get disk usage: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNetworkUsage retrieves network traffic per interface.
+func (c *GRPCClient) GetNetworkUsage(ctx context.Context, dateFrom int64, dateTo int64) (*pb_network.GetNetworkUsageResponse, error) {
+	req := &pb_network.GetNetworkUsageRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+
+	resp, err := c.networkClient.GetNetworkUsage(ctx, req)
+	if err != nil {
+		log.Printf("failed to get network usage: %v", err)
+		return nil, err
+	}
+	return resp, nil
+}
+
 // Close closes the underlying gRPC connection.  It's good practice to close connections when you're done with them.
 func (c *GRPCClient) Close() error {
 	return c.conn.Close()
 }
 
-// GRPCClientSingleton is a singleton to ensure only one gRPC client.
-type GRPCClientSingleton struct {
-	client *GRPCClient
-	once   sync.Once // Ensures the client is initialized only once.
-	err    error
+// State returns the current connectivity state of the underlying connection.
+func (c *GRPCClient) State() connectivity.State {
+	return c.conn.GetState()
 }
 
-// Instance returns the singleton instance of the GRPCClient.
-func (s *GRPCClientSingleton) Instance(address string) (*GRPCClient, error) {
-	s.once.Do(func() {
-		s.client, s.err = NewGRPCClient(address) // Initialize the client
-	})
-	return s.client, s.err
+// monitor logs every connectivity change and asks idle connections to reconnect right away, so that the
+// next report does not pay for the dial. It returns when ctx is done.
+func (c *GRPCClient) monitor(ctx context.Context, address string) {
+	state := c.conn.GetState()
+	for {
+		if state == connectivity.Idle {
+			c.conn.Connect()
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return
+		}
+		newState := c.conn.GetState()
+		log.Printf("gRPC connection to %s: %s -> %s", address, state, newState)
+		state = newState
+	}
 }
 
-var grpcClientSingleton GRPCClientSingleton // Global instance of the singleton
+// GRPCClientPool owns a fixed set of long-lived connections to the data provider and hands them out
+// round-robin. It's safe for concurrent use and meant to live as long as the application.
+type GRPCClientPool struct {
+	address string
+	clients []*GRPCClient
+	next    atomic.Uint64
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewGRPCClientPool opens size connections to address and starts monitoring them.
+func NewGRPCClientPool(address string, size int) (*GRPCClientPool, error) {
+	if size <= 0 {
+		size = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := &GRPCClientPool{address: address, cancel: cancel}
+	for i := 0; i < size; i++ {
+		client, err := NewGRPCClient(address)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.clients = append(pool.clients, client)
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			client.monitor(ctx, address)
+		}()
+	}
+	return pool, nil
+}
+
+// Client returns the next client of the pool.
+func (p *GRPCClientPool) Client() *GRPCClient {
+	return p.clients[(p.next.Add(1)-1)%uint64(len(p.clients))]
+}
+
+// Address returns the data provider address the pool is connected to.
+func (p *GRPCClientPool) Address() string {
+	return p.address
+}
+
+// Version returns the data provider version reported by any connection of the pool, or "" before
+// the provider reported one.
+func (p *GRPCClientPool) Version() string {
+	for _, client := range p.clients {
+		if version := client.Version(); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// States returns the connectivity state of every connection of the pool.
+func (p *GRPCClientPool) States() []connectivity.State {
+	states := make([]connectivity.State, len(p.clients))
+	for i, client := range p.clients {
+		states[i] = client.State()
+	}
+	return states
+}
+
+// Ready reports whether at least one connection of the pool is ready to serve calls.
+func (p *GRPCClientPool) Ready() bool {
+	for _, state := range p.States() {
+		if state == connectivity.Ready {
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops the monitoring and closes every connection of the pool.
+func (p *GRPCClientPool) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	var errs []error
+	for _, client := range p.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
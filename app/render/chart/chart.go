@@ -0,0 +1,204 @@
+// Package chart draws report charts server-side in pure Go, as SVG or PNG, so that they render without
+// JavaScript or a browser and can be reused by every output format.
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// Type is the kind of chart drawn.
+type Type string
+
+const (
+	// Bar draws vertical bars, the series of a category side by side.
+	Bar Type = "bar"
+	// StackedBar draws one vertical bar per category, the series stacked on top of each other.
+	StackedBar Type = "stacked_bar"
+	// Line draws a line per series.
+	Line Type = "line"
+	// Area draws a line per series with the area under it filled.
+	Area Type = "area"
+	// Heatmap draws a row of cells per series, colored by value from green (low) to red (high).
+	Heatmap Type = "heatmap"
+)
+
+// Types lists every chart type.
+var Types = []Type{Bar, StackedBar, Line, Area, Heatmap}
+
+// LegendPosition places the legend around the plot, like the position of an excelize chart legend.
+type LegendPosition string
+
+const (
+	LegendBottom LegendPosition = "bottom"
+	LegendTop    LegendPosition = "top"
+	LegendLeft   LegendPosition = "left"
+	LegendRight  LegendPosition = "right"
+	LegendNone   LegendPosition = "none"
+)
+
+const (
+	defaultWidth  = 720
+	defaultHeight = 400
+	maxSize       = 4096
+)
+
+// palette holds the colors of the series, reused in order.
+var palette = []string{"#4472C4", "#ED7D31", "#70AD47", "#FFC000", "#5B9BD5", "#A5A5A5", "#264478", "#9E480E"}
+
+// Series is a named list of values, one per category. A NaN value is a gap: no bar or cell, and a
+// break in lines.
+type Series struct {
+	Name   string    `json:"name"`
+	Values []float64 `json:"values"`
+}
+
+// Chart is the data and layout of a chart, independent of the output format.
+type Chart struct {
+	Type       Type     `json:"type"`
+	Title      string   `json:"title"`
+	XAxis      string   `json:"x_axis"`
+	YAxis      string   `json:"y_axis"`
+	Categories []string `json:"categories"`
+	Series     []Series `json:"series"`
+	// Legend defaults to LegendBottom.
+	Legend LegendPosition `json:"legend"`
+	// Width and Height are in pixels, 720x400 by default.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// ValueFormat formats the values of the axis ticks, tooltips and heatmap cells; FormatNumber by default.
+	ValueFormat func(value float64) string `json:"-"`
+	// Threshold, when positive, draws a labelled red line at that value across the plot. Heatmaps
+	// have no value axis and ignore it.
+	Threshold float64 `json:"threshold"`
+}
+
+// Validate checks the type, the legend position and the size, and that every series has one value
+// per category.
+func (c *Chart) Validate() error {
+	if !slices.Contains(Types, c.Type) {
+		return fmt.Errorf("unknown chart type %q", c.Type)
+	}
+	switch c.Legend {
+	case "", LegendBottom, LegendTop, LegendLeft, LegendRight, LegendNone:
+	default:
+		return fmt.Errorf("unknown legend position %q", c.Legend)
+	}
+	if len(c.Series) == 0 {
+		return errors.New("at least one series is required")
+	}
+	for _, series := range c.Series {
+		if len(series.Values) != len(c.Categories) {
+			return fmt.Errorf("series %q has %d values for %d categories", series.Name, len(series.Values), len(c.Categories))
+		}
+	}
+	if c.Width < 0 || c.Height < 0 || c.Width > maxSize || c.Height > maxSize {
+		return fmt.Errorf("invalid size %dx%d, at most %dx%d", c.Width, c.Height, maxSize, maxSize)
+	}
+	return nil
+}
+
+func (c *Chart) size() (float64, float64) {
+	width, height := c.Width, c.Height
+	if width == 0 {
+		width = defaultWidth
+	}
+	if height == 0 {
+		height = defaultHeight
+	}
+	return float64(width), float64(height)
+}
+
+func (c *Chart) legend() LegendPosition {
+	if c.Legend == "" {
+		return LegendBottom
+	}
+	return c.Legend
+}
+
+func (c *Chart) format(value float64) string {
+	if c.ValueFormat != nil {
+		return c.ValueFormat(value)
+	}
+	return FormatNumber(value)
+}
+
+// FormatNumber prints a value with at most two decimals.
+func FormatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// FormatPercent prints a ratio as a percentage, the format of the usage columns.
+func FormatPercent(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/10, 'f', -1, 64) + "%"
+}
+
+// valueRange returns the bounds of the value axis: from zero, or the smallest negative value, to
+// the largest value or the threshold, rounded to nice tick steps. Stacked bars range over the sums of
+// each category.
+func (c *Chart) valueRange() (low float64, high float64, step float64) {
+	if c.Type == StackedBar {
+		for i := range c.Categories {
+			var below, above float64
+			for _, series := range c.Series {
+				if v := series.Values[i]; v < 0 {
+					below += v
+				} else if v > 0 {
+					above += v
+				}
+			}
+			low, high = math.Min(low, below), math.Max(high, above)
+		}
+	} else {
+		for _, series := range c.Series {
+			for _, v := range series.Values {
+				if math.IsNaN(v) {
+					continue
+				}
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	if c.Threshold > 0 {
+		high = math.Max(high, c.Threshold)
+	}
+	if high == low {
+		high = low + 1
+	}
+	step = niceStep((high - low) / 5)
+	return math.Floor(low/step) * step, math.Ceil(high/step) * step, step
+}
+
+// heatRange returns the smallest and largest values of a heatmap, the ends of its color scale.
+func (c *Chart) heatRange() (low float64, high float64) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, series := range c.Series {
+		for _, v := range series.Values {
+			if !math.IsNaN(v) {
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+	}
+	if math.IsInf(low, 1) {
+		return 0, 0
+	}
+	return low, high
+}
+
+// niceStep rounds a tick step to 1, 2 or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch fraction := raw / magnitude; {
+	case fraction <= 1:
+		return magnitude
+	case fraction <= 2:
+		return 2 * magnitude
+	case fraction <= 5:
+		return 5 * magnitude
+	default:
+		return 10 * magnitude
+	}
+}
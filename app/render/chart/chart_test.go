@@ -0,0 +1,185 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+)
+
+func usageChart(chartType Type) *Chart {
+	return &Chart{
+		Type:       chartType,
+		Title:      "CPU <usage> & load",
+		XAxis:      "CPU",
+		YAxis:      "Usage",
+		Categories: []string{"cpu0", "cpu1", "cpu2"},
+		Series: []Series{
+			{Name: "System", Values: []float64{0.1, math.NaN(), 0.3}},
+			{Name: "User", Values: []float64{0.4, 0.2, -0.1}},
+		},
+		ValueFormat: FormatPercent,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		change func(c *Chart)
+		want   string
+	}{
+		{"valid", func(c *Chart) {}, ""},
+		{"largest size", func(c *Chart) { c.Width, c.Height = maxSize, maxSize }, ""},
+		{"type", func(c *Chart) { c.Type = "pie" }, `unknown chart type "pie"`},
+		{"legend", func(c *Chart) { c.Legend = "middle" }, `unknown legend position "middle"`},
+		{"no series", func(c *Chart) { c.Series = nil }, "at least one series"},
+		{"too few values", func(c *Chart) { c.Series[1].Values = c.Series[1].Values[:2] }, `series "User" has 2 values for 3 categories`},
+		{"too many values", func(c *Chart) { c.Categories = c.Categories[:1] }, `series "System" has 3 values for 1 categories`},
+		{"negative size", func(c *Chart) { c.Width = -1 }, "invalid size"},
+		{"too wide", func(c *Chart) { c.Width = maxSize + 1 }, "invalid size"},
+		{"too high", func(c *Chart) { c.Height = maxSize + 1 }, "invalid size"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c := usageChart(Bar)
+			test.change(c)
+			err := c.Validate()
+			if test.want == "" && err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+			if test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestValueRange(t *testing.T) {
+	for _, test := range []struct {
+		name            string
+		chartType       Type
+		series          [][]float64
+		threshold       float64
+		low, high, step float64
+	}{
+		{"all zero", Bar, [][]float64{{0, 0}, {0, 0}}, 0, 0, 1, 0.2},
+		{"positive", Line, [][]float64{{12, 47}}, 0, 0, 50, 10},
+		{"gaps", Line, [][]float64{{math.NaN(), 50}}, 0, 0, 50, 10},
+		{"negative", Bar, [][]float64{{-3, 7}}, 0, -4, 8, 2},
+		{"all negative", Area, [][]float64{{-5, -1}}, 0, -5, 0, 1},
+		{"grouped", Bar, [][]float64{{1, -2}, {3, -1}}, 0, -2, 3, 1},
+		{"stacked", StackedBar, [][]float64{{1, -2}, {3, -1}}, 0, -4, 4, 2},
+		{"stacked gaps", StackedBar, [][]float64{{math.NaN(), 2}, {3, 1}}, 0, 0, 3, 1},
+		{"threshold", StackedBar, [][]float64{{0.1, 0.2}, {0.2, 0.1}}, 0.8, 0, 0.8, 0.2},
+	} {
+		c := &Chart{Type: test.chartType, Threshold: test.threshold}
+		for i, values := range test.series {
+			c.Series = append(c.Series, Series{Name: string(rune('A' + i)), Values: values})
+			c.Categories = make([]string, len(values))
+		}
+		low, high, step := c.valueRange()
+		if math.Abs(low-test.low) > 1e-9 || math.Abs(high-test.high) > 1e-9 || math.Abs(step-test.step) > 1e-9 {
+			t.Errorf("%s: valueRange() = (%v, %v, %v), want (%v, %v, %v)", test.name, low, high, step, test.low, test.high, test.step)
+		}
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	for _, test := range []struct {
+		raw, want float64
+	}{
+		{0.03, 0.05},
+		{0.2, 0.2},
+		{0.7, 1},
+		{1, 1},
+		{1.5, 2},
+		{3, 5},
+		{7, 10},
+		{20, 20},
+		{45, 50},
+		{1 << 30, 2e9},
+	} {
+		if got := niceStep(test.raw); math.Abs(got-test.want) > 1e-9*test.want {
+			t.Errorf("niceStep(%v) = %v, want %v", test.raw, got, test.want)
+		}
+	}
+}
+
+func TestRenderEveryType(t *testing.T) {
+	for _, chartType := range Types {
+		for _, legend := range []LegendPosition{"", LegendTop, LegendLeft, LegendRight, LegendNone} {
+			c := usageChart(chartType)
+			c.Legend, c.Width, c.Height = legend, 480, 300
+
+			var svg bytes.Buffer
+			if err := c.SVG(&svg); err != nil {
+				t.Fatalf("%s %q: SVG() = %v", chartType, legend, err)
+			}
+			if !strings.HasPrefix(svg.String(), "<svg ") || !strings.HasSuffix(svg.String(), "</svg>") {
+				t.Errorf("%s %q: SVG() wrote no SVG document", chartType, legend)
+			}
+			if strings.Contains(svg.String(), "<usage>") {
+				t.Errorf("%s %q: SVG() did not escape the title", chartType, legend)
+			}
+
+			var image bytes.Buffer
+			if err := c.PNG(&image); err != nil {
+				t.Fatalf("%s %q: PNG() = %v", chartType, legend, err)
+			}
+			decoded, err := png.Decode(&image)
+			if err != nil {
+				t.Fatalf("%s %q: PNG() wrote an invalid image: %v", chartType, legend, err)
+			}
+			if size := decoded.Bounds().Size(); size.X != 480 || size.Y != 300 {
+				t.Errorf("%s %q: PNG() is %dx%d, want 480x300", chartType, legend, size.X, size.Y)
+			}
+		}
+	}
+}
+
+func TestRenderWithoutCategories(t *testing.T) {
+	for _, chartType := range Types {
+		c := &Chart{Type: chartType, Series: []Series{{Name: "System"}}}
+		if err := c.SVG(&bytes.Buffer{}); err != nil {
+			t.Errorf("%s: SVG() = %v", chartType, err)
+		}
+		if err := c.PNG(&bytes.Buffer{}); err != nil {
+			t.Errorf("%s: PNG() = %v", chartType, err)
+		}
+	}
+}
+
+func TestThresholdLine(t *testing.T) {
+	c := &Chart{
+		Type:        StackedBar,
+		Categories:  []string{"cpu0", "cpu1"},
+		Series:      []Series{{Name: "System", Values: []float64{0.1, 0.2}}, {Name: "User", Values: []float64{0.2, 0.1}}},
+		ValueFormat: FormatPercent,
+		Threshold:   0.8,
+	}
+	var svg bytes.Buffer
+	if err := c.SVG(&svg); err != nil {
+		t.Fatalf("SVG() = %v", err)
+	}
+	if !bytes.Contains(svg.Bytes(), []byte("Threshold 80%")) {
+		t.Errorf("SVG() has no threshold label")
+	}
+}
+
+func TestFormats(t *testing.T) {
+	for _, test := range []struct {
+		format func(float64) string
+		value  float64
+		want   string
+	}{
+		{FormatNumber, 1.005, "1"},
+		{FormatNumber, 2.5, "2.5"},
+		{FormatNumber, -1234.567, "-1234.57"},
+		{FormatPercent, 0.1234, "12.3%"},
+		{FormatPercent, 1, "100%"},
+	} {
+		if got := test.format(test.value); got != test.want {
+			t.Errorf("format(%v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
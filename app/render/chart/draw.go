@@ -0,0 +1,396 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+const (
+	titleSize    = 15
+	labelSize    = 11
+	swatchSize   = 10
+	legendHeight = 24
+	legendRow    = 18
+	// axisTitleSpace is the room taken by an axis title, beside the tick or category labels.
+	axisTitleSpace = 18
+	textColor      = "#333333"
+	mutedColor     = "#555555"
+	gridColor      = "#E0E0E0"
+	gapColor       = "#F2F2F2"
+	thresholdColor = "#C00000"
+)
+
+// heatColors are the stops of the heatmap color scale, from the lowest to the highest value.
+var heatColors = [][3]float64{{0x63, 0xBE, 0x7B}, {0xFF, 0xEB, 0x84}, {0xF8, 0x69, 0x6B}}
+
+type point struct {
+	x, y float64
+}
+
+// box is a rectangle of the chart, in pixels from the top left corner.
+type box struct {
+	left, top, right, bottom float64
+}
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+type textStyle struct {
+	size   float64
+	anchor anchor
+	color  string
+	bold   bool
+	// vertical text reads from bottom to top, rotated around its anchor point.
+	vertical bool
+}
+
+// canvas is what an output format implements: the primitives a chart is drawn with. Tips are the
+// tooltips of the shapes, for the formats that support them.
+type canvas interface {
+	rect(area box, fill string, tip string)
+	polyline(points []point, stroke string, width float64)
+	polygon(points []point, fill string, opacity float64)
+	circle(center point, radius float64, fill string, tip string)
+	text(at point, value string, style textStyle)
+}
+
+// textWidth estimates the width of a text, the advance of the PNG font and close to common sans-serif fonts.
+func textWidth(value string, size float64) float64 {
+	return float64(utf8.RuneCountInString(value)) * size * 0.6
+}
+
+// draw lays the chart out on cv: title, legend, then the plot in the remaining area.
+func (c *Chart) draw(cv canvas) {
+	width, height := c.size()
+	cv.rect(box{0, 0, width, height}, "#FFFFFF", "")
+	area := box{left: 12, top: 12, right: width - 16, bottom: height - 8}
+	if c.Title != "" {
+		cv.text(point{width / 2, 26}, c.Title, textStyle{size: titleSize, anchor: anchorMiddle, color: "#222222", bold: true})
+		area.top = 40
+	}
+	area = c.drawLegend(cv, area)
+	if c.Type == Heatmap {
+		c.drawHeatmap(cv, area)
+	} else {
+		c.drawPlot(cv, area)
+	}
+}
+
+// drawLegend draws the legend on its side of area and returns the area left for the plot.
+func (c *Chart) drawLegend(cv canvas, area box) box {
+	position := c.legend()
+	if position == LegendNone {
+		return area
+	}
+	if c.Type == Heatmap {
+		return c.drawScale(cv, area, position)
+	}
+	entryWidth := func(series Series) float64 {
+		return swatchSize + 4 + textWidth(series.Name, labelSize) + 16
+	}
+	switch position {
+	case LegendTop, LegendBottom:
+		total := 0.0
+		for _, series := range c.Series {
+			total += entryWidth(series)
+		}
+		x, y := math.Max(area.left, (area.left+area.right-total)/2), area.bottom-swatchSize-2
+		if position == LegendTop {
+			y = area.top
+			area.top += legendHeight
+		} else {
+			area.bottom -= legendHeight
+		}
+		for s, series := range c.Series {
+			cv.rect(box{x, y, x + swatchSize, y + swatchSize}, seriesColor(s), "")
+			cv.text(point{x + swatchSize + 4, y + 9}, series.Name, textStyle{size: labelSize, color: textColor})
+			x += entryWidth(series)
+		}
+	default:
+		widest := 0.0
+		for _, series := range c.Series {
+			widest = math.Max(widest, entryWidth(series))
+		}
+		x := area.left
+		if position == LegendRight {
+			x = area.right - widest + 16
+			area.right -= widest
+		} else {
+			area.left += widest
+		}
+		y := (area.top+area.bottom)/2 - float64(len(c.Series))*legendRow/2
+		for s, series := range c.Series {
+			cv.rect(box{x, y, x + swatchSize, y + swatchSize}, seriesColor(s), "")
+			cv.text(point{x + swatchSize + 4, y + 9}, series.Name, textStyle{size: labelSize, color: textColor})
+			y += legendRow
+		}
+	}
+	return area
+}
+
+// drawScale draws the color scale of a heatmap as its legend.
+func (c *Chart) drawScale(cv canvas, area box, position LegendPosition) box {
+	const steps, stepSize = 10, 16.0
+	low, high := c.heatRange()
+	lowLabel, highLabel := c.format(low), c.format(high)
+	switch position {
+	case LegendTop, LegendBottom:
+		x, y := (area.left+area.right-steps*stepSize)/2, area.bottom-swatchSize-2
+		if position == LegendTop {
+			y = area.top
+			area.top += legendHeight
+		} else {
+			area.bottom -= legendHeight
+		}
+		cv.text(point{x - 6, y + 9}, lowLabel, textStyle{size: labelSize, anchor: anchorEnd, color: textColor})
+		for i := range steps {
+			left := x + float64(i)*stepSize
+			cv.rect(box{left, y, left + stepSize, y + swatchSize}, heatColor(float64(i)/(steps-1)), "")
+		}
+		cv.text(point{x + steps*stepSize + 6, y + 9}, highLabel, textStyle{size: labelSize, color: textColor})
+	default:
+		width := swatchSize + 6 + math.Max(textWidth(lowLabel, labelSize), textWidth(highLabel, labelSize)) + 12
+		x := area.left
+		if position == LegendRight {
+			x = area.right - width + 12
+			area.right -= width
+		} else {
+			area.left += width
+		}
+		y := (area.top+area.bottom)/2 - steps*stepSize/2
+		for i := range steps {
+			top := y + float64(i)*stepSize
+			cv.rect(box{x, top, x + swatchSize, top + stepSize}, heatColor(1-float64(i)/(steps-1)), "")
+		}
+		cv.text(point{x + swatchSize + 6, y + 9}, highLabel, textStyle{size: labelSize, color: textColor})
+		cv.text(point{x + swatchSize + 6, y + steps*stepSize}, lowLabel, textStyle{size: labelSize, color: textColor})
+	}
+	return area
+}
+
+// drawPlot draws the value axis, the series and the category labels of the bar, line and area charts.
+func (c *Chart) drawPlot(cv canvas, area box) {
+	low, high, step := c.valueRange()
+	var ticks []float64
+	labelWidth := 0.0
+	for value := low; value <= high+step/2; value += step {
+		ticks = append(ticks, value)
+		labelWidth = math.Max(labelWidth, textWidth(c.format(value), labelSize))
+	}
+	plot := area
+	plot.left += labelWidth + 8
+	if c.YAxis != "" {
+		plot.left += axisTitleSpace
+	}
+	plot.bottom -= axisTitleSpace
+	if c.XAxis != "" {
+		plot.bottom -= axisTitleSpace
+	}
+	y := func(value float64) float64 {
+		return plot.top + (plot.bottom-plot.top)*(high-value)/(high-low)
+	}
+
+	for _, value := range ticks {
+		cv.polyline([]point{{plot.left, y(value)}, {plot.right, y(value)}}, gridColor, 1)
+		cv.text(point{plot.left - 6, y(value) + 4}, c.format(value), textStyle{size: labelSize, anchor: anchorEnd, color: mutedColor})
+	}
+
+	count := len(c.Categories)
+	plotWidth := plot.right - plot.left
+	var x func(i int) float64
+	base := y(math.Max(low, 0))
+	switch c.Type {
+	case Bar, StackedBar:
+		slot := plotWidth / float64(max(count, 1))
+		x = func(i int) float64 { return plot.left + slot*(float64(i)+0.5) }
+		if c.Type == StackedBar {
+			barWidth := slot * 0.6
+			for i := range count {
+				above, below := 0.0, 0.0
+				for s, series := range c.Series {
+					value := series.Values[i]
+					if math.IsNaN(value) || value == 0 {
+						continue
+					}
+					from := &above
+					if value < 0 {
+						from = &below
+					}
+					top, bottom := y(*from+value), y(*from)
+					if value < 0 {
+						top, bottom = bottom, top
+					}
+					*from += value
+					cv.rect(box{x(i) - barWidth/2, top, x(i) + barWidth/2, bottom}, seriesColor(s), tooltip(series.Name, c.Categories[i], c.format(value)))
+				}
+			}
+			break
+		}
+		barWidth := slot * 0.8 / float64(len(c.Series))
+		for s, series := range c.Series {
+			for i, value := range series.Values {
+				if math.IsNaN(value) {
+					continue
+				}
+				left := plot.left + slot*float64(i) + slot*0.1 + barWidth*float64(s)
+				cv.rect(box{left, y(math.Max(value, 0)), left + barWidth, y(math.Min(value, 0))}, seriesColor(s), tooltip(series.Name, c.Categories[i], c.format(value)))
+			}
+		}
+	case Line, Area:
+		gap := plotWidth
+		if count > 1 {
+			gap = plotWidth / float64(count-1)
+		}
+		x = func(i int) float64 { return plot.left + gap*float64(i) }
+		// Areas are filled first, so that no fill hides the line of another series.
+		if c.Type == Area {
+			for s, series := range c.Series {
+				for _, run := range segments(series.Values) {
+					outline := []point{{x(run[0]), base}}
+					for i := run[0]; i < run[1]; i++ {
+						outline = append(outline, point{x(i), y(series.Values[i])})
+					}
+					cv.polygon(append(outline, point{x(run[1] - 1), base}), seriesColor(s), 0.25)
+				}
+			}
+		}
+		for s, series := range c.Series {
+			for _, run := range segments(series.Values) {
+				points := make([]point, 0, run[1]-run[0])
+				for i := run[0]; i < run[1]; i++ {
+					points = append(points, point{x(i), y(series.Values[i])})
+				}
+				cv.polyline(points, seriesColor(s), 2)
+			}
+			if count <= 60 {
+				for i, value := range series.Values {
+					if !math.IsNaN(value) {
+						cv.circle(point{x(i), y(value)}, 2.5, seriesColor(s), tooltip(series.Name, c.Categories[i], c.format(value)))
+					}
+				}
+			}
+		}
+	}
+
+	if c.Threshold > 0 {
+		level := y(c.Threshold)
+		cv.polyline([]point{{plot.left, level}, {plot.right, level}}, thresholdColor, 1.5)
+		cv.text(point{plot.right - 4, level - 5}, "Threshold "+c.format(c.Threshold), textStyle{size: labelSize, anchor: anchorEnd, color: thresholdColor})
+	}
+
+	cv.polyline([]point{{plot.left, plot.top}, {plot.left, plot.bottom}}, textColor, 1)
+	cv.polyline([]point{{plot.left, base}, {plot.right, base}}, textColor, 1)
+	c.drawCategories(cv, plot, x)
+	c.drawAxisTitles(cv, plot, area)
+}
+
+// drawHeatmap draws a row of cells per series and a column per category.
+func (c *Chart) drawHeatmap(cv canvas, area box) {
+	labelWidth := 0.0
+	for _, series := range c.Series {
+		labelWidth = math.Max(labelWidth, textWidth(series.Name, labelSize))
+	}
+	plot := area
+	plot.left += labelWidth + 8
+	if c.YAxis != "" {
+		plot.left += axisTitleSpace
+	}
+	plot.bottom -= axisTitleSpace
+	if c.XAxis != "" {
+		plot.bottom -= axisTitleSpace
+	}
+	low, high := c.heatRange()
+	cellWidth := (plot.right - plot.left) / float64(max(len(c.Categories), 1))
+	cellHeight := (plot.bottom - plot.top) / float64(len(c.Series))
+	for s, series := range c.Series {
+		top := plot.top + cellHeight*float64(s)
+		cv.text(point{plot.left - 6, top + cellHeight/2 + 4}, series.Name, textStyle{size: labelSize, anchor: anchorEnd, color: mutedColor})
+		for i, value := range series.Values {
+			cell := box{plot.left + cellWidth*float64(i), top, plot.left + cellWidth*float64(i+1), top + cellHeight}
+			if math.IsNaN(value) {
+				cv.rect(cell, gapColor, "")
+				continue
+			}
+			ratio := 0.5
+			if high > low {
+				ratio = (value - low) / (high - low)
+			}
+			label := c.format(value)
+			cv.rect(cell, heatColor(ratio), tooltip(series.Name, c.Categories[i], label))
+			if textWidth(label, labelSize)+6 <= cellWidth && cellHeight >= labelSize+4 {
+				cv.text(point{(cell.left + cell.right) / 2, (cell.top+cell.bottom)/2 + 4}, label, textStyle{size: labelSize, anchor: anchorMiddle, color: "#222222"})
+			}
+		}
+	}
+	c.drawCategories(cv, plot, func(i int) float64 { return plot.left + cellWidth*(float64(i)+0.5) })
+	c.drawAxisTitles(cv, plot, area)
+}
+
+// drawCategories prints the category labels below the plot, only every few ones when they would overlap.
+func (c *Chart) drawCategories(cv canvas, plot box, x func(i int) float64) {
+	count := len(c.Categories)
+	if count == 0 {
+		return
+	}
+	widest := 0.0
+	for _, category := range c.Categories {
+		widest = math.Max(widest, textWidth(category, labelSize))
+	}
+	every := max(1, int(math.Ceil(float64(count)*(widest+8)/(plot.right-plot.left))))
+	for i := 0; i < count; i += every {
+		cv.text(point{x(i), plot.bottom + 15}, c.Categories[i], textStyle{size: labelSize, anchor: anchorMiddle, color: mutedColor})
+	}
+}
+
+func (c *Chart) drawAxisTitles(cv canvas, plot box, area box) {
+	if c.XAxis != "" {
+		cv.text(point{(plot.left + plot.right) / 2, plot.bottom + axisTitleSpace + 15}, c.XAxis, textStyle{size: labelSize, anchor: anchorMiddle, color: textColor})
+	}
+	if c.YAxis != "" {
+		cv.text(point{area.left + 10, (plot.top + plot.bottom) / 2}, c.YAxis, textStyle{size: labelSize, anchor: anchorMiddle, color: textColor, vertical: true})
+	}
+}
+
+// segments returns the [start, end) index ranges of the runs of values that are not gaps.
+func segments(values []float64) [][2]int {
+	var runs [][2]int
+	start := -1
+	for i, value := range values {
+		switch {
+		case math.IsNaN(value) && start >= 0:
+			runs = append(runs, [2]int{start, i})
+			start = -1
+		case !math.IsNaN(value) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		runs = append(runs, [2]int{start, len(values)})
+	}
+	return runs
+}
+
+func seriesColor(index int) string {
+	return palette[index%len(palette)]
+}
+
+// heatColor interpolates the heatmap color scale at ratio, from 0 (lowest) to 1 (highest).
+func heatColor(ratio float64) string {
+	ratio = math.Min(math.Max(ratio, 0), 1) * float64(len(heatColors)-1)
+	stop := min(int(ratio), len(heatColors)-2)
+	from, to, t := heatColors[stop], heatColors[stop+1], ratio-float64(stop)
+	channel := func(i int) int {
+		return int(math.Round(from[i] + (to[i]-from[i])*t))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", channel(0), channel(1), channel(2))
+}
+
+func tooltip(series string, category string, value string) string {
+	return fmt.Sprintf("%s, %s: %s", series, category, value)
+}
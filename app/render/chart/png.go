@@ -0,0 +1,201 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ContentTypePNG is the MIME type of PNG charts.
+const ContentTypePNG = "image/png"
+
+// supersample is the number of pixels per side the PNG is drawn with for every output pixel, which
+// smooths the edges once averaged.
+const supersample = 3
+
+// PNG writes the chart as a PNG image, drawn with the same layout as the SVG and a built-in bitmap font.
+func (c *Chart) PNG(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	width, height := c.size()
+	cv := newRaster(int(width), int(height))
+	c.draw(cv)
+	return png.Encode(w, cv.downsample())
+}
+
+// raster draws the shapes into an RGBA image, supersampled.
+type raster struct {
+	img *image.RGBA
+}
+
+func newRaster(width int, height int) *raster {
+	return &raster{img: image.NewRGBA(image.Rect(0, 0, width*supersample, height*supersample))}
+}
+
+func (cv *raster) rect(area box, fill string, tip string) {
+	cv.fill(area, parseColor(fill), 1)
+}
+
+func (cv *raster) polyline(points []point, stroke string, width float64) {
+	paint, half := parseColor(stroke), width/2
+	for i := 1; i < len(points); i++ {
+		from, to := points[i-1], points[i]
+		length := math.Hypot(to.x-from.x, to.y-from.y)
+		if length == 0 {
+			continue
+		}
+		nx, ny := -(to.y-from.y)/length*half, (to.x-from.x)/length*half
+		cv.fillPolygon([]point{{from.x + nx, from.y + ny}, {to.x + nx, to.y + ny}, {to.x - nx, to.y - ny}, {from.x - nx, from.y - ny}}, paint, 1)
+		if i > 1 && width > 1 {
+			cv.fillCircle(from, half, paint)
+		}
+	}
+}
+
+func (cv *raster) polygon(points []point, fill string, opacity float64) {
+	cv.fillPolygon(points, parseColor(fill), opacity)
+}
+
+func (cv *raster) circle(center point, radius float64, fill string, tip string) {
+	cv.fillCircle(center, radius, parseColor(fill))
+}
+
+// text draws the glyphs of the bitmap font, scaled so that a capital is 0.7 times the font size tall.
+func (cv *raster) text(at point, value string, style textStyle) {
+	unit := style.size / 10
+	paint := parseColor(style.color)
+	offset := 0.0
+	switch style.anchor {
+	case anchorMiddle:
+		offset = -textWidth(value, style.size) / 2
+	case anchorEnd:
+		offset = -textWidth(value, style.size)
+	}
+	dot := func(dx float64, dy float64) {
+		if style.vertical {
+			// Rotated a quarter turn counterclockwise around the anchor point.
+			cv.fill(box{at.x + dy, at.y - dx - unit, at.x + dy + unit, at.y - dx}, paint, 1)
+			return
+		}
+		cv.fill(box{at.x + dx, at.y + dy, at.x + dx + unit, at.y + dy + unit}, paint, 1)
+	}
+	for i, char := range []rune(value) {
+		left := offset + float64(i*glyphAdvance)*unit
+		for _, pixel := range glyph(char) {
+			dx, dy := left+float64(pixel[0])*unit, float64(pixel[1]-glyphHeight)*unit
+			dot(dx, dy)
+			if style.bold {
+				dot(dx+unit/2, dy)
+			}
+		}
+	}
+}
+
+// fill paints the pixels whose centers lie in area.
+func (cv *raster) fill(area box, paint color.RGBA, opacity float64) {
+	x0, x1 := span(area.left, area.right)
+	y0, y1 := span(area.top, area.bottom)
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			cv.blend(x, y, paint, opacity)
+		}
+	}
+}
+
+// fillPolygon paints the inside of a polygon, by the even-odd rule, one scanline at a time.
+func (cv *raster) fillPolygon(points []point, paint color.RGBA, opacity float64) {
+	if len(points) < 3 {
+		return
+	}
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		top, bottom = math.Min(top, p.y), math.Max(bottom, p.y)
+	}
+	y0, y1 := span(top, bottom)
+	var crossings []float64
+	for y := y0; y < y1; y++ {
+		center := (float64(y) + 0.5) / supersample
+		crossings = crossings[:0]
+		for i, from := range points {
+			to := points[(i+1)%len(points)]
+			if (from.y <= center) != (to.y <= center) {
+				crossings = append(crossings, from.x+(center-from.y)/(to.y-from.y)*(to.x-from.x))
+			}
+		}
+		slices.Sort(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			x0, x1 := span(crossings[i], crossings[i+1])
+			for x := x0; x < x1; x++ {
+				cv.blend(x, y, paint, opacity)
+			}
+		}
+	}
+}
+
+func (cv *raster) fillCircle(center point, radius float64, paint color.RGBA) {
+	x0, x1 := span(center.x-radius, center.x+radius)
+	y0, y1 := span(center.y-radius, center.y+radius)
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			dx, dy := (float64(x)+0.5)/supersample-center.x, (float64(y)+0.5)/supersample-center.y
+			if dx*dx+dy*dy <= radius*radius {
+				cv.blend(x, y, paint, 1)
+			}
+		}
+	}
+}
+
+// blend paints a supersampled pixel over the current one.
+func (cv *raster) blend(x int, y int, paint color.RGBA, opacity float64) {
+	if !(image.Point{X: x, Y: y}).In(cv.img.Rect) {
+		return
+	}
+	i := cv.img.PixOffset(x, y)
+	pixel := cv.img.Pix[i : i+4 : i+4]
+	for channel, value := range []uint8{paint.R, paint.G, paint.B, 255} {
+		pixel[channel] = uint8(math.Round(float64(value)*opacity + float64(pixel[channel])*(1-opacity)))
+	}
+}
+
+// downsample averages every supersample x supersample block into one pixel of the output image.
+func (cv *raster) downsample() *image.RGBA {
+	bounds := cv.img.Rect
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx()/supersample, bounds.Dy()/supersample))
+	for y := range out.Rect.Dy() {
+		for x := range out.Rect.Dx() {
+			var sum [4]int
+			for sy := range supersample {
+				for sx := range supersample {
+					i := cv.img.PixOffset(x*supersample+sx, y*supersample+sy)
+					for channel := range sum {
+						sum[channel] += int(cv.img.Pix[i+channel])
+					}
+				}
+			}
+			i := out.PixOffset(x, y)
+			for channel := range sum {
+				out.Pix[i+channel] = uint8(sum[channel] / (supersample * supersample))
+			}
+		}
+	}
+	return out
+}
+
+// span returns the supersampled pixels whose centers lie between from and to, in output pixels.
+func span(from float64, to float64) (int, int) {
+	return int(math.Ceil(from*supersample - 0.5)), int(math.Ceil(to*supersample - 0.5))
+}
+
+// parseColor reads a #RRGGBB color, black when malformed.
+func parseColor(value string) color.RGBA {
+	rgb, err := strconv.ParseUint(value[min(1, len(value)):], 16, 32)
+	if err != nil || len(value) != 7 {
+		return color.RGBA{A: 255}
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}
+}
@@ -0,0 +1,93 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const fontFamily = "Helvetica, Arial, sans-serif"
+
+// ContentTypeSVG is the MIME type of SVG charts.
+const ContentTypeSVG = "image/svg+xml"
+
+// SVG writes the chart as a standalone SVG document, with inline styles only. Bars, points and cells
+// carry their value as a tooltip.
+func (c *Chart) SVG(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	out := bufio.NewWriter(w)
+	width, height := c.size()
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="%s" font-size="%d" role="img" style="max-width:100%%;height:auto">`, width, height, width, height, fontFamily, labelSize)
+	fmt.Fprintf(out, `<title>%s</title>`, escape(c.Title))
+	c.draw(svgCanvas{out})
+	fmt.Fprint(out, `</svg>`)
+	return out.Flush()
+}
+
+// svgCanvas writes the shapes as SVG elements.
+type svgCanvas struct {
+	out *bufio.Writer
+}
+
+func (cv svgCanvas) rect(area box, fill string, tip string) {
+	fmt.Fprintf(cv.out, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"`, area.left, area.top, area.right-area.left, area.bottom-area.top, fill)
+	cv.close("rect", tip)
+}
+
+func (cv svgCanvas) polyline(points []point, stroke string, width float64) {
+	fmt.Fprintf(cv.out, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round"/>`, svgPoints(points), stroke, width)
+}
+
+func (cv svgCanvas) polygon(points []point, fill string, opacity float64) {
+	fmt.Fprintf(cv.out, `<polygon points="%s" fill="%s" fill-opacity="%g"/>`, svgPoints(points), fill, opacity)
+}
+
+func (cv svgCanvas) circle(center point, radius float64, fill string, tip string) {
+	fmt.Fprintf(cv.out, `<circle cx="%.2f" cy="%.2f" r="%g" fill="%s"`, center.x, center.y, radius, fill)
+	cv.close("circle", tip)
+}
+
+func (cv svgCanvas) text(at point, value string, style textStyle) {
+	fmt.Fprintf(cv.out, `<text x="%.2f" y="%.2f" fill="%s"`, at.x, at.y, style.color)
+	switch style.anchor {
+	case anchorMiddle:
+		fmt.Fprint(cv.out, ` text-anchor="middle"`)
+	case anchorEnd:
+		fmt.Fprint(cv.out, ` text-anchor="end"`)
+	}
+	if style.size != labelSize {
+		fmt.Fprintf(cv.out, ` font-size="%g"`, style.size)
+	}
+	if style.bold {
+		fmt.Fprint(cv.out, ` font-weight="bold"`)
+	}
+	if style.vertical {
+		fmt.Fprintf(cv.out, ` transform="rotate(-90 %.2f %.2f)"`, at.x, at.y)
+	}
+	fmt.Fprintf(cv.out, `>%s</text>`, escape(value))
+}
+
+// close ends an element, with its tooltip as a title child when there is one.
+func (cv svgCanvas) close(element string, tip string) {
+	if tip == "" {
+		fmt.Fprint(cv.out, `/>`)
+		return
+	}
+	fmt.Fprintf(cv.out, `><title>%s</title></%s>`, escape(tip), element)
+}
+
+func svgPoints(points []point) string {
+	formatted := make([]string, len(points))
+	for i, p := range points {
+		formatted[i] = fmt.Sprintf("%.2f,%.2f", p.x, p.y)
+	}
+	return strings.Join(formatted, " ")
+}
+
+func escape(text string) string {
+	return html.EscapeString(text)
+}
@@ -0,0 +1,342 @@
+// Package csv writes report sections as delimited text, either one file per section in a zip
+// archive or a single file with a section column.
+package csv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// Layouts of a rendered report.
+const (
+	// LayoutZip writes a zip archive with one CSV file per section.
+	LayoutZip = "zip"
+	// LayoutSingle writes a single CSV file whose first column names the section of each row.
+	LayoutSingle = "single"
+)
+
+const sectionHeader = "Section"
+
+// Options configures the written text.
+type Options struct {
+	// Delimiter separates the fields, ',' by default; '\t' produces TSV.
+	Delimiter rune
+	// DecimalSeparator is written in numbers instead of '.', for locales such as "1,5".
+	DecimalSeparator rune
+	// Header writes the column names as the first row.
+	Header bool
+	// Layout is LayoutZip or LayoutSingle.
+	Layout string
+}
+
+// DefaultOptions are comma separated, with a '.' decimal separator, a header row and a zip layout.
+func DefaultOptions() Options {
+	return Options{Delimiter: ',', DecimalSeparator: '.', Header: true, Layout: LayoutZip}
+}
+
+// Validate checks that the options produce text that can be parsed back.
+func (opts Options) Validate() error {
+	if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' || opts.Delimiter == unicode.ReplacementChar {
+		return fmt.Errorf("invalid delimiter %q", opts.Delimiter)
+	}
+	if opts.DecimalSeparator == opts.Delimiter {
+		return errors.New("the decimal separator must differ from the delimiter")
+	}
+	if opts.Layout != LayoutZip && opts.Layout != LayoutSingle {
+		return fmt.Errorf("unknown layout %q", opts.Layout)
+	}
+	return nil
+}
+
+// ContentType returns the MIME type of the written file.
+func (opts Options) ContentType() string {
+	if opts.Layout == LayoutZip {
+		return "application/zip"
+	}
+	if opts.Delimiter == '\t' {
+		return "text/tab-separated-values"
+	}
+	return "text/csv"
+}
+
+// Extension returns the file extension of the written file.
+func (opts Options) Extension() string {
+	if opts.Layout == LayoutZip {
+		return "zip"
+	}
+	return opts.fileExtension()
+}
+
+// fileExtension returns the extension of the delimited files themselves.
+func (opts Options) fileExtension() string {
+	if opts.Delimiter == '\t' {
+		return "tsv"
+	}
+	return "csv"
+}
+
+// Section is a table written as CSV. The XLSX reports satisfy it with their streaming methods, so
+// that both formats always have the same columns.
+type Section interface {
+	SheetName() string
+	Columns() []render_xlsx.StreamColumn
+	Rows() iter.Seq[[]any]
+}
+
+// Write writes the sections to w with the layout of opts.
+func Write[S Section](ctx context.Context, w io.Writer, opts Options, sections ...S) error {
+	if opts.Layout == LayoutSingle {
+		return writeSingle(ctx, w, opts, sections)
+	}
+	return writeZip(ctx, w, opts, sections)
+}
+
+func writeZip[S Section](ctx context.Context, w io.Writer, opts Options, sections []S) error {
+	archive := zip.NewWriter(w)
+	used := make(map[string]bool, len(sections))
+	for _, section := range sections {
+		name := fileName(section.SheetName(), used) + "." + opts.fileExtension()
+		file, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		if err := writeSection(ctx, file, opts, section); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+func writeSection(ctx context.Context, w io.Writer, opts Options, section Section) error {
+	writer := newWriter(w, opts)
+	if opts.Header {
+		if err := writer.Write(headers(section.Columns())); err != nil {
+			return err
+		}
+	}
+	formats := columnFormats(section.Columns())
+	for values := range section.Rows() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := writer.Write(formatRow(values, formats, opts)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeSingle writes every section into one file. Its columns are the section column followed by
+// the columns of every section, in order of first appearance; sections leave the columns they do
+// not have empty.
+func writeSingle[S Section](ctx context.Context, w io.Writer, opts Options, sections []S) error {
+	all := []string{sectionHeader}
+	positions := make([][]int, len(sections))
+	formats := make([][]valueFormat, len(sections))
+	for i, section := range sections {
+		formats[i] = columnFormats(section.Columns())
+		for _, header := range headers(section.Columns()) {
+			position := slices.Index(all, header)
+			if position < 1 {
+				all = append(all, header)
+				position = len(all) - 1
+			}
+			positions[i] = append(positions[i], position)
+		}
+	}
+
+	writer := newWriter(w, opts)
+	if opts.Header {
+		if err := writer.Write(all); err != nil {
+			return err
+		}
+	}
+	for i, section := range sections {
+		for values := range section.Rows() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			record := make([]string, len(all))
+			record[0] = section.SheetName()
+			for j, value := range formatRow(values, formats[i], opts) {
+				if j < len(positions[i]) {
+					record[positions[i][j]] = value
+				}
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func newWriter(w io.Writer, opts Options) *csv.Writer {
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Delimiter
+	return writer
+}
+
+func headers(columns []render_xlsx.StreamColumn) []string {
+	result := make([]string, len(columns))
+	for i, column := range columns {
+		result[i] = column.Header
+	}
+	return result
+}
+
+// valueFormat is how the values of a column are written: multiplied by scale, with precision decimals.
+type valueFormat struct {
+	scale     float64
+	precision int
+}
+
+// columnFormats applies the XLSX number format of every column to its text: percentages are written
+// in percent, as their headers say, and integers without decimals. Other numbers get 4 decimals,
+// enough for the data without the noise of their binary representation.
+func columnFormats(columns []render_xlsx.StreamColumn) []valueFormat {
+	formats := make([]valueFormat, len(columns))
+	for i, column := range columns {
+		formats[i] = valueFormat{scale: 1, precision: 4}
+		if column.Style == nil {
+			continue
+		}
+		switch column.Style.NumFmt {
+		case 9, 10:
+			formats[i].scale = 100
+		case 1, 3:
+			formats[i].precision = 0
+		}
+	}
+	return formats
+}
+
+func formatRow(values []any, formats []valueFormat, opts Options) []string {
+	record := make([]string, len(values))
+	for i, value := range values {
+		format := valueFormat{scale: 1, precision: 4}
+		if i < len(formats) {
+			format = formats[i]
+		}
+		record[i] = formatValue(value, format, opts)
+	}
+	return record
+}
+
+// formatValue writes numbers in the format of their column with the configured decimal separator,
+// and times in RFC 3339.
+func formatValue(value any, format valueFormat, opts Options) string {
+	var text string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		text = strconv.FormatFloat(v*format.scale, 'f', format.precision, 64)
+	case float32:
+		text = strconv.FormatFloat(float64(v)*format.scale, 'f', format.precision, 64)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+	if opts.DecimalSeparator != '.' && opts.DecimalSeparator != 0 {
+		text = strings.Replace(text, ".", string(opts.DecimalSeparator), 1)
+	}
+	return text
+}
+
+// fileName turns a section name into a unique lowercase file name such as "cpu_system_usage".
+func fileName(name string, used map[string]bool) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	base := strings.TrimSuffix(b.String(), "_")
+	if base == "" {
+		base = "section"
+	}
+	unique := base
+	for n := 2; used[unique]; n++ {
+		unique = fmt.Sprintf("%s_%d", base, n)
+	}
+	used[unique] = true
+	return unique
+}
+
+// RequestOptions are the request body fields that shape the delimited text, for embedding in the
+// request contracts of the routes that render CSV.
+type RequestOptions struct {
+	Delimiter        string `json:"delimiter" description:"CSV only: field delimiter, a single character or \"tab\" for TSV (default \",\")"`
+	DecimalSeparator string `json:"decimal_separator" description:"CSV only: decimal separator of numbers (default \".\")"`
+	Header           *bool  `json:"header" description:"CSV only: writes the column names as the first row (default true)"`
+	Layout           string `json:"layout" description:"CSV only: zip for one file per section (default), single for one file with a section column"`
+}
+
+// Parse validates the request fields and returns the options they select.
+func (request RequestOptions) Parse() (Options, error) {
+	return ParseOptions(request.Delimiter, request.DecimalSeparator, request.Header, request.Layout)
+}
+
+// ParseOptions builds options from request values, starting from DefaultOptions. delimiter and
+// decimalSeparator are single characters, delimiter also accepts "tab"; a nil header keeps the header row.
+func ParseOptions(delimiter string, decimalSeparator string, header *bool, layout string) (Options, error) {
+	opts := DefaultOptions()
+	if delimiter != "" {
+		if strings.EqualFold(delimiter, "tab") {
+			delimiter = "\t"
+		}
+		r, err := singleRune(delimiter)
+		if err != nil {
+			return opts, fmt.Errorf("invalid delimiter: %w", err)
+		}
+		opts.Delimiter = r
+	}
+	if decimalSeparator != "" {
+		r, err := singleRune(decimalSeparator)
+		if err != nil {
+			return opts, fmt.Errorf("invalid decimal separator: %w", err)
+		}
+		opts.DecimalSeparator = r
+	}
+	if header != nil {
+		opts.Header = *header
+	}
+	if layout != "" {
+		opts.Layout = layout
+	}
+	return opts, opts.Validate()
+}
+
+func singleRune(value string) (rune, error) {
+	runes := []rune(value)
+	if len(runes) != 1 {
+		return 0, fmt.Errorf("%q must be a single character", value)
+	}
+	return runes[0], nil
+}
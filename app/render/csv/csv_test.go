@@ -0,0 +1,60 @@
+package csv
+
+import (
+	"bytes"
+	"context"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+type section struct {
+	name    string
+	columns []render_xlsx.StreamColumn
+	rows    [][]any
+}
+
+func (s section) SheetName() string                   { return s.name }
+func (s section) Columns() []render_xlsx.StreamColumn { return s.columns }
+func (s section) Rows() iter.Seq[[]any]               { return slices.Values(s.rows) }
+
+var usage = section{
+	name: "CPU Usage",
+	columns: []render_xlsx.StreamColumn{
+		{Header: "CPU"},
+		{Header: "Average Usage (%)", Style: &excelize.Style{NumFmt: 10}},
+		{Header: "IOPS", Style: &excelize.Style{NumFmt: 3}},
+		{Header: "Read (MiB/s)", Style: &excelize.Style{NumFmt: 2}},
+	},
+	rows: [][]any{{"cpu0", 0.07, 1500.0, 0.1 + 0.2}, {"cpu1", float32(0.125), 3.0, nil}},
+}
+
+func TestWriteAppliesTheColumnFormats(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Layout = LayoutSingle
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, opts, usage); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	want := "Section,CPU,Average Usage (%),IOPS,Read (MiB/s)\n" +
+		"CPU Usage,cpu0,7.0000,1500,0.3000\n" +
+		"CPU Usage,cpu1,12.5000,3,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Write() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteUsesTheDecimalSeparator(t *testing.T) {
+	opts := Options{Delimiter: ';', DecimalSeparator: ',', Layout: LayoutSingle}
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, opts, usage); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	if want := "CPU Usage;cpu0;7,0000;1500;0,3000\nCPU Usage;cpu1;12,5000;3;\n"; buf.String() != want {
+		t.Errorf("Write() wrote %q, want %q", buf.String(), want)
+	}
+}
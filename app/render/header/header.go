@@ -0,0 +1,80 @@
+// Package header describes the block that opens every rendered report, whatever its format: what the
+// report is, the period it covers, when it was generated and where its data comes from.
+package header
+
+import (
+	"fmt"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04"
+
+// Header is the header block of a rendered report.
+type Header struct {
+	Title string
+	// DateFrom and DateTo are the covered period, in unix seconds.
+	DateFrom int64
+	DateTo   int64
+	// Location is the time zone the period and the generation time are shown in; nil means UTC.
+	Location    *time.Location
+	GeneratedAt time.Time
+	// Provider and ProviderVersion identify the data provider; the version is empty when unknown.
+	Provider        string
+	ProviderVersion string
+	RequestID       string
+}
+
+// Field is a labelled line of the header block.
+type Field struct {
+	Label string
+	Value string
+}
+
+// Period returns the covered period, e.g. "2024-01-01 00:00 to 2024-01-31 00:00 (Europe/Madrid)".
+func (h Header) Period() string {
+	return fmt.Sprintf("%s to %s (%s)", h.format(time.Unix(h.DateFrom, 0)), h.format(time.Unix(h.DateTo, 0)), h.location())
+}
+
+// Generated returns the generation time in the time zone of the header.
+func (h Header) Generated() string {
+	return fmt.Sprintf("%s (%s)", h.format(h.GeneratedAt), h.location())
+}
+
+// Source returns the data provider and its version, when known.
+func (h Header) Source() string {
+	if h.ProviderVersion == "" {
+		return h.Provider
+	}
+	return fmt.Sprintf("%s (version %s)", h.Provider, h.ProviderVersion)
+}
+
+// Fields returns the lines of the header block below the title, leaving out the unknown ones.
+func (h Header) Fields() []Field {
+	fields := []Field{
+		{Label: "Period", Value: h.Period()},
+		{Label: "Generated", Value: h.Generated()},
+	}
+	if h.Provider != "" {
+		fields = append(fields, Field{Label: "Data provider", Value: h.Source()})
+	}
+	if h.RequestID != "" {
+		fields = append(fields, Field{Label: "Request ID", Value: h.RequestID})
+	}
+	return fields
+}
+
+// In returns t in the time zone of the header.
+func (h Header) In(t time.Time) time.Time {
+	return t.In(h.location())
+}
+
+func (h Header) format(t time.Time) string {
+	return h.In(t).Format(timeLayout)
+}
+
+func (h Header) location() *time.Location {
+	if h.Location == nil {
+		return time.UTC
+	}
+	return h.Location
+}
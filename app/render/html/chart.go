@@ -0,0 +1,153 @@
+package html
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// cellRange matches the references of chart series, such as 'Sheet'!$B$2:$B$10, 'Sheet'!B2:B10 or 'Sheet'!$B$1.
+var cellRange = regexp.MustCompile(`!\$?([A-Z]+)\$?(\d+)(?::\$?([A-Z]+)\$?(\d+))?$`)
+
+// chartTypes maps the XLSX chart types to the drawn ones; other types are drawn as bars.
+var chartTypes = map[excelize.ChartType]chart.Type{
+	excelize.ColStacked: chart.StackedBar,
+	excelize.BarStacked: chart.StackedBar,
+	excelize.Line:       chart.Line,
+	excelize.Area:       chart.Area,
+}
+
+// legendPositions maps the XLSX legend positions to the drawn ones; the default is the bottom.
+var legendPositions = map[string]chart.LegendPosition{
+	"top":       chart.LegendTop,
+	"left":      chart.LegendLeft,
+	"right":     chart.LegendRight,
+	"top_right": chart.LegendRight,
+	"none":      chart.LegendNone,
+}
+
+// reference is a parsed cell range: a column and the first and last rows.
+type reference struct {
+	column   int
+	firstRow int
+	lastRow  int
+}
+
+func parseReference(value string) (reference, bool) {
+	match := cellRange.FindStringSubmatch(value)
+	if match == nil || (match[3] != "" && match[3] != match[1]) {
+		return reference{}, false
+	}
+	column, err := excelize.ColumnNameToNumber(match[1])
+	if err != nil {
+		return reference{}, false
+	}
+	ref := reference{column: column}
+	ref.firstRow, _ = strconv.Atoi(match[2])
+	ref.lastRow = ref.firstRow
+	if match[4] != "" {
+		ref.lastRow, _ = strconv.Atoi(match[4])
+	}
+	return ref, true
+}
+
+// resolveReference parses a cell range, or a named column of the table of sheetName, which spans
+// every data row.
+func resolveReference(value string, sheetName string, columns []render_xlsx.StreamColumn, rows [][]any) (reference, bool) {
+	if ref, ok := parseReference(value); ok {
+		return ref, true
+	}
+	for col := 1; col <= len(columns); col++ {
+		if value == render_xlsx.ColumnSeries(sheetName, col) {
+			return reference{column: col, firstRow: 2, lastRow: len(rows) + 1}, true
+		}
+	}
+	return reference{}, false
+}
+
+// chartSVG draws an XLSX chart of a sheet, reading the cell ranges of its series from the rows the sheet
+// yielded: row 1 is the header and row r the data row r-2.
+func chartSVG(sheetName string, sheetChart render_xlsx.SheetChart, columns []render_xlsx.StreamColumn, rows [][]any) (template.HTML, error) {
+	source := sheetChart.Chart
+	drawn := chart.Chart{
+		Type:   chart.Bar,
+		Title:  richText(source.Title),
+		XAxis:  richText(source.XAxis.Title),
+		YAxis:  richText(source.YAxis.Title),
+		Legend: legendPositions[source.Legend.Position],
+		Width:  int(source.Dimension.Width),
+		Height: int(source.Dimension.Height),
+	}
+	if chartType, ok := chartTypes[source.Type]; ok {
+		drawn.Type = chartType
+	}
+	for i, series := range source.Series {
+		values, ok := resolveReference(series.Values, sheetName, columns, rows)
+		if !ok {
+			return "", fmt.Errorf("unsupported chart values %q", series.Values)
+		}
+		if i == 0 {
+			categories, ok := resolveReference(series.Categories, sheetName, columns, rows)
+			if !ok {
+				return "", fmt.Errorf("unsupported chart categories %q", series.Categories)
+			}
+			column := columnAt(columns, categories.column-1)
+			for _, value := range cellValues(rows, categories) {
+				drawn.Categories = append(drawn.Categories, formatCell(value, column).Text)
+			}
+			if column := columnAt(columns, values.column-1); column.Style != nil && column.Style.NumFmt == 10 {
+				drawn.ValueFormat = chart.FormatPercent
+			}
+		}
+		name := series.Name
+		if ref, ok := parseReference(name); ok && ref.firstRow == 1 {
+			name = columnAt(columns, ref.column-1).Header
+		}
+		plotted := chart.Series{Name: name}
+		for _, value := range cellValues(rows, values) {
+			number, ok := toFloat(value)
+			if !ok {
+				number = math.NaN()
+			}
+			plotted.Values = append(plotted.Values, number)
+		}
+		drawn.Series = append(drawn.Series, plotted)
+	}
+
+	var buf bytes.Buffer
+	if err := drawn.SVG(&buf); err != nil {
+		return "", fmt.Errorf("failed to draw chart %q: %w", drawn.Title, err)
+	}
+	// The SVG is generated by the chart package, which escapes every text it writes.
+	return template.HTML(buf.String()), nil
+}
+
+// cellValues returns the values of the data rows of a range.
+func cellValues(rows [][]any, ref reference) []any {
+	values := make([]any, 0, max(ref.lastRow-ref.firstRow+1, 0))
+	for row := ref.firstRow; row <= ref.lastRow; row++ {
+		var value any
+		if i := row - 2; i >= 0 && i < len(rows) && ref.column-1 < len(rows[i]) {
+			value = rows[i][ref.column-1]
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func richText(runs []excelize.RichTextRun) string {
+	var text strings.Builder
+	for _, run := range runs {
+		text.WriteString(run.Text)
+	}
+	return text.String()
+}
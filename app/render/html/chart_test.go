@@ -0,0 +1,35 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+func TestChartSVGPlotsNamedColumns(t *testing.T) {
+	sheet := &render_xlsx.TableSheet{
+		Name:   "Disk Throughput",
+		Fields: []render_xlsx.StreamColumn{{Header: "Device"}, {Header: "Read (MiB/s)"}, {Header: "Write (MiB/s)"}},
+		Data:   [][]any{{"sda", 1.0, 2.0}, {"sdb", 3.0, 4.0}},
+		Chart:  &render_xlsx.TableChart{Type: excelize.Col, Title: "Disk Throughput", YAxis: "MiB/s", Series: []int{2, 3}},
+	}
+	charts := sheet.Charts(sheet.Name, 2, 3)
+	if got := charts[0].Chart.Series[0].Values; got != render_xlsx.ColumnSeries(sheet.Name, 2) {
+		t.Fatalf("series values = %q, want the named column", got)
+	}
+	svg, err := chartSVG(sheet.Name, charts[0], sheet.Fields, sheet.Data)
+	if err != nil {
+		t.Fatalf("chartSVG() = %v", err)
+	}
+	for _, want := range []string{"sda", "sdb", "Read (MiB/s)", "Write (MiB/s)"} {
+		if !strings.Contains(string(svg), want) {
+			t.Errorf("chart has no %q", want)
+		}
+	}
+	if _, err := chartSVG("Other", charts[0], sheet.Fields, sheet.Data); err == nil {
+		t.Error("chartSVG() resolved the named columns of another sheet")
+	}
+}
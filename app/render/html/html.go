@@ -0,0 +1,218 @@
+// Package html renders reports as a single self-contained HTML document: inline styles, SVG charts
+// drawn server-side and no external assets or scripts, so that it can be served, mailed or pasted
+// into a wiki as is.
+package html
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// ContentType is the MIME type of the rendered documents.
+const ContentType = "text/html; charset=utf-8"
+
+// DefaultMaxRows is the number of table rows shown per section when Options.MaxRows is zero.
+const DefaultMaxRows = 500
+
+// Options shapes the rendered document.
+type Options struct {
+	// Header is shown above the sections; a document without a title has no header block.
+	Header header.Header `json:"-"`
+	// Fragment leaves out the html, head and body elements, for embedding into an email body or a wiki page.
+	Fragment bool `json:"fragment"`
+	// MaxRows limits the rows shown in each table; charts still plot every row. Zero means DefaultMaxRows.
+	MaxRows int `json:"max_rows"`
+}
+
+// Validate checks that the row limit is usable.
+func (opts Options) Validate() error {
+	if opts.MaxRows < 0 {
+		return errors.New("max_rows must not be negative")
+	}
+	return nil
+}
+
+func (opts Options) maxRows() int {
+	if opts.MaxRows == 0 {
+		return DefaultMaxRows
+	}
+	return opts.MaxRows
+}
+
+type document struct {
+	Title    string
+	Period   string
+	Details  []header.Field
+	Fragment bool
+	Sections []section
+}
+
+type section struct {
+	Name        string
+	Unavailable string
+	Headers     []string
+	Rows        [][]cell
+	Omitted     int
+	Charts      []template.HTML
+}
+
+type cell struct {
+	Text    string
+	Numeric bool
+}
+
+// Write renders the sheets, in order, as the sections of one HTML document. The sheets are the same
+// models as the XLSX reports: each section shows the columns and rows of a sheet and its charts.
+func Write[S render_xlsx.StreamSheet](ctx context.Context, w io.Writer, opts Options, sheets ...S) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	doc := document{Title: opts.Header.Title, Fragment: opts.Fragment}
+	if doc.Title != "" {
+		fields := opts.Header.Fields()
+		doc.Period, doc.Details = fields[0].Value, fields[1:]
+	}
+	for _, sheet := range sheets {
+		rendered, err := renderSection(ctx, opts.maxRows(), sheet)
+		if err != nil {
+			return fmt.Errorf("failed to render section %q: %w", sheet.SheetName(), err)
+		}
+		doc.Sections = append(doc.Sections, rendered)
+	}
+
+	var buf bytes.Buffer
+	if err := page.Execute(&buf, doc); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func renderSection(ctx context.Context, maxRows int, sheet render_xlsx.StreamSheet) (section, error) {
+	rendered := section{Name: sheet.SheetName()}
+	columns := sheet.Columns()
+	var rows [][]any
+	for row := range sheet.Rows() {
+		if err := ctx.Err(); err != nil {
+			return section{}, err
+		}
+		rows = append(rows, row)
+	}
+	if len(columns) == 1 && columns[0].Header == render_xlsx.UnavailableHeader {
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			rendered.Unavailable = fmt.Sprint(rows[0][0])
+		}
+		return rendered, nil
+	}
+
+	for _, column := range columns {
+		rendered.Headers = append(rendered.Headers, column.Header)
+	}
+	for _, row := range rows[:min(len(rows), maxRows)] {
+		cells := make([]cell, len(row))
+		for i, value := range row {
+			cells[i] = formatCell(value, columnAt(columns, i))
+		}
+		rendered.Rows = append(rendered.Rows, cells)
+	}
+	rendered.Omitted = len(rows) - len(rendered.Rows)
+
+	if len(rows) == 0 {
+		return rendered, nil
+	}
+	for _, sheetChart := range sheet.Charts(sheet.SheetName(), 2, len(rows)+1) {
+		svg, err := chartSVG(sheet.SheetName(), sheetChart, columns, rows)
+		if err != nil {
+			return section{}, err
+		}
+		rendered.Charts = append(rendered.Charts, svg)
+	}
+	return rendered, nil
+}
+
+func columnAt(columns []render_xlsx.StreamColumn, i int) render_xlsx.StreamColumn {
+	if i < len(columns) {
+		return columns[i]
+	}
+	return render_xlsx.StreamColumn{}
+}
+
+// formatCell prints a value the way the number format of its XLSX column shows it.
+func formatCell(value any, column render_xlsx.StreamColumn) cell {
+	numFmt := 0
+	if column.Style != nil {
+		numFmt = column.Style.NumFmt
+	}
+	switch v := value.(type) {
+	case nil:
+		return cell{}
+	case time.Time:
+		return cell{Text: formatTime(v)}
+	case string:
+		return cell{Text: v}
+	}
+	number, ok := toFloat(value)
+	if !ok {
+		return cell{Text: fmt.Sprint(value)}
+	}
+	switch numFmt {
+	case 10:
+		return cell{Text: strconv.FormatFloat(number*100, 'f', 2, 64) + "%", Numeric: true}
+	case 2:
+		return cell{Text: strconv.FormatFloat(number, 'f', 2, 64), Numeric: true}
+	case 3:
+		return cell{Text: groupThousands(int64(math.Round(number))), Numeric: true}
+	default:
+		return cell{Text: strconv.FormatFloat(number, 'f', -1, 64), Numeric: true}
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func groupThousands(value int64) string {
+	digits := strconv.FormatInt(value, 10)
+	sign := ""
+	if value < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + grouped.String()
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04")
+}
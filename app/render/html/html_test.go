@@ -0,0 +1,176 @@
+package html
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// usageSheet is a sheet of n CPUs with a column chart of their usage.
+func usageSheet(n int) *render_xlsx.TableSheet {
+	sheet := &render_xlsx.TableSheet{
+		Name: "CPU Usage",
+		Fields: []render_xlsx.StreamColumn{
+			{Header: "CPU"},
+			{Header: "Average Usage (%)", Style: &excelize.Style{NumFmt: 10}},
+		},
+		Chart: &render_xlsx.TableChart{Type: excelize.Col, Title: "CPU Usage", YAxis: "Average Usage (%)", Series: []int{2}},
+	}
+	for i := range n {
+		sheet.Data = append(sheet.Data, []any{fmt.Sprintf("cpu%d", i), float64(i) / 100})
+	}
+	return sheet
+}
+
+func write(t *testing.T, opts Options, sheets ...*render_xlsx.TableSheet) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(context.Background(), &buf, opts, sheets...); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriteDocumentAndFragment(t *testing.T) {
+	h := header.Header{Title: "CPU Report", DateTo: 3600, GeneratedAt: time.Unix(3600, 0), RequestID: "r-1"}
+	full := write(t, Options{Header: h}, usageSheet(2))
+	for _, want := range []string{"<!DOCTYPE html>", "<title>CPU Report</title>", "<body", "</html>", "<h1", "r-1", "<svg "} {
+		if !strings.Contains(full, want) {
+			t.Errorf("document has no %q", want)
+		}
+	}
+
+	fragment := write(t, Options{Header: h, Fragment: true}, usageSheet(2))
+	if !strings.HasPrefix(fragment, "<div") {
+		t.Errorf("fragment starts with %.40q, want a div", fragment)
+	}
+	for _, unwanted := range []string{"<!DOCTYPE", "<html", "<head", "<body", "</html>"} {
+		if strings.Contains(fragment, unwanted) {
+			t.Errorf("fragment has %q", unwanted)
+		}
+	}
+	if !strings.Contains(fragment, "<h1") || !strings.Contains(fragment, "<svg ") {
+		t.Errorf("fragment lost the header or the chart")
+	}
+
+	untitled := write(t, Options{Fragment: true}, usageSheet(2))
+	if strings.Contains(untitled, "<h1") {
+		t.Errorf("document without a title has a header block")
+	}
+}
+
+func TestWriteLimitsTheRowsOfTables(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		rows    int
+		maxRows int
+		shown   int
+	}{
+		{"under the limit", 3, 5, 3},
+		{"at the limit", 5, 5, 5},
+		{"over the limit", 8, 5, 5},
+		{"default limit", DefaultMaxRows + 2, 0, DefaultMaxRows},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			rendered, err := renderSection(context.Background(), Options{MaxRows: test.maxRows}.maxRows(), usageSheet(test.rows))
+			if err != nil {
+				t.Fatalf("renderSection() = %v", err)
+			}
+			if len(rendered.Rows) != test.shown || rendered.Omitted != test.rows-test.shown {
+				t.Errorf("renderSection() shows %d rows and omits %d, want %d and %d", len(rendered.Rows), rendered.Omitted, test.shown, test.rows-test.shown)
+			}
+			if len(rendered.Charts) != 1 || !strings.Contains(string(rendered.Charts[0]), fmt.Sprintf("cpu%d", test.rows-1)) {
+				t.Errorf("the chart does not plot every row")
+			}
+		})
+	}
+
+	out := write(t, Options{MaxRows: 5}, usageSheet(8))
+	if !strings.Contains(out, "3 more rows are not shown") {
+		t.Errorf("document does not tell the omitted rows")
+	}
+	if out := write(t, Options{MaxRows: 5}, usageSheet(5)); strings.Contains(out, "more rows are not shown") {
+		t.Errorf("document tells omitted rows when none are")
+	}
+}
+
+func TestWriteUnavailableSection(t *testing.T) {
+	out := write(t, Options{}, &render_xlsx.TableSheet{Name: "Memory Usage", Unavailable: "the data provider did not answer"}, usageSheet(1))
+	unavailable := out[strings.Index(out, "Memory Usage"):strings.Index(out, "CPU Usage")]
+	if !strings.Contains(unavailable, "Data unavailable") || !strings.Contains(unavailable, "the data provider did not answer") {
+		t.Errorf("unavailable section = %q, want the placeholder and the reason", unavailable)
+	}
+	if strings.Contains(unavailable, "<table") || strings.Contains(unavailable, render_xlsx.UnavailableHeader+"</th>") {
+		t.Errorf("unavailable section has a table")
+	}
+	if !strings.Contains(out[strings.Index(out, "CPU Usage"):], "<table") {
+		t.Errorf("the section after the unavailable one lost its table")
+	}
+}
+
+func TestWriteEscapesText(t *testing.T) {
+	sheet := &render_xlsx.TableSheet{
+		Name:   "<script>alert(1)</script>",
+		Fields: []render_xlsx.StreamColumn{{Header: "A & B"}, {Header: "<i>Note</i>"}},
+		Data:   [][]any{{`"quoted" & <b>bold</b>`, "<img src=x onerror=alert(1)>"}},
+	}
+	out := write(t, Options{Header: header.Header{Title: "<Report>"}}, sheet)
+	for _, unwanted := range []string{"<script>", "<b>", "<i>", "<img", "<Report>"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("document has unescaped %q", unwanted)
+		}
+	}
+	for _, want := range []string{"&lt;script&gt;alert(1)&lt;/script&gt;", "A &amp; B", "&lt;i&gt;Note&lt;/i&gt;", "&#34;quoted&#34; &amp; &lt;b&gt;bold&lt;/b&gt;", "&lt;Report&gt;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("document has no %q", want)
+		}
+	}
+}
+
+func TestWriteStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Write(ctx, &bytes.Buffer{}, Options{}, usageSheet(3)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Write() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWriteRejectsNegativeMaxRows(t *testing.T) {
+	if err := Write(context.Background(), &bytes.Buffer{}, Options{MaxRows: -1}, usageSheet(1)); err == nil {
+		t.Error("Write() accepted a negative max_rows")
+	}
+}
+
+func TestFormatCell(t *testing.T) {
+	percent := render_xlsx.StreamColumn{Style: &excelize.Style{NumFmt: 10}}
+	decimal := render_xlsx.StreamColumn{Style: &excelize.Style{NumFmt: 2}}
+	integer := render_xlsx.StreamColumn{Style: &excelize.Style{NumFmt: 3}}
+	for _, test := range []struct {
+		value  any
+		column render_xlsx.StreamColumn
+		want   cell
+	}{
+		{nil, percent, cell{}},
+		{"cpu0", percent, cell{Text: "cpu0"}},
+		{0.125, percent, cell{Text: "12.50%", Numeric: true}},
+		{float32(2.5), decimal, cell{Text: "2.50", Numeric: true}},
+		{int64(-1234567), integer, cell{Text: "-1,234,567", Numeric: true}},
+		{999.6, integer, cell{Text: "1,000", Numeric: true}},
+		{1.5, render_xlsx.StreamColumn{}, cell{Text: "1.5", Numeric: true}},
+		{time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("CEST", 7200)), render_xlsx.StreamColumn{}, cell{Text: "2024-05-01 10:30"}},
+		{true, render_xlsx.StreamColumn{}, cell{Text: "true"}},
+	} {
+		if got := formatCell(test.value, test.column); got != test.want {
+			t.Errorf("formatCell(%v) = %+v, want %+v", test.value, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package html
+
+import "html/template"
+
+// page is the document template. Every style is inline, since email clients drop style elements.
+var page = template.Must(template.New("page").Parse(`{{if not .Fragment}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body style="margin:0;padding:24px;background:#ffffff;color:#222222;font-family:Helvetica,Arial,sans-serif;font-size:14px">
+{{end}}<div style="max-width:1000px;font-family:Helvetica,Arial,sans-serif;color:#222222">
+{{if .Title}}<h1 style="font-size:22px;margin:0 0 4px">{{.Title}}</h1>
+{{end}}{{if .Period}}<p style="margin:0 0 8px;color:#666666">{{.Period}}</p>
+{{end}}{{if .Details}}<p style="margin:0 0 24px;color:#666666;font-size:12px">{{range $i, $field := .Details}}{{if $i}} &middot; {{end}}{{$field.Label}}: {{$field.Value}}{{end}}</p>
+{{end}}{{range .Sections}}<h2 style="font-size:18px;margin:32px 0 12px;padding-bottom:4px;border-bottom:2px solid #4472C4">{{.Name}}</h2>
+{{if .Unavailable}}<p style="margin:0;padding:12px;background:#FFC7CE;color:#9C0006"><strong>Data unavailable</strong><br>{{.Unavailable}}</p>
+{{else}}<div style="overflow-x:auto">
+<table cellpadding="0" cellspacing="0" style="border-collapse:collapse;font-size:13px">
+<thead><tr>{{range .Headers}}<th style="padding:6px 8px;border:1px solid #BFBFBF;background:#D9E1F2;text-align:left;white-space:nowrap">{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{range $i, $row := .Rows}}<tr>{{range $row}}<td style="padding:4px 8px;border:1px solid #D9D9D9;white-space:nowrap{{if .Numeric}};text-align:right{{end}}">{{.Text}}</td>{{end}}</tr>
+{{end}}</tbody>
+</table>
+</div>
+{{if .Omitted}}<p style="margin:4px 0 0;color:#666666;font-size:12px">{{.Omitted}} more rows are not shown; the charts include every row.</p>
+{{end}}{{range .Charts}}<div style="margin:16px 0">{{.}}</div>
+{{end}}{{end}}{{end}}</div>
+{{if not .Fragment}}</body>
+</html>
+{{end}}`))
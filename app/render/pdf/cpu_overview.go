@@ -0,0 +1,108 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+)
+
+// CpuUsageStats is the average, maximum and minimum usage of a CPU, as ratios.
+type CpuUsageStats struct {
+	AvgUsage float64
+	MaxUsage float64
+	MinUsage float64
+}
+
+// CpuOverviewData is the system and user usage of one CPU. System or User is nil when the CPU is
+// missing from that dataset, and TotalAvgUsage, their added averages, when it is missing from either.
+type CpuOverviewData struct {
+	CPU           string
+	System        *CpuUsageStats
+	User          *CpuUsageStats
+	TotalAvgUsage *float64
+}
+
+// CpuOverviewReport joins the system and user usage per CPU with their total, the busy time of each core.
+type CpuOverviewReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []CpuOverviewData
+	// Threshold, when positive, highlights the CPUs whose total average usage is above it.
+	Threshold float64
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the overview page (table grouped by system, user and total, and a stacked bar chart of
+// the averages) to the PDF document. The total only has an average: the system and user extremes
+// need not coincide.
+func (r *CpuOverviewReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	return renderSection(ctx, doc, "Overview", r.Unavailable, func() error {
+		columns := []TableColumn{{Header: "CPU", Width: 26, Align: "L"}}
+		for range 2 {
+			for _, stat := range []string{"Avg", "Max", "Min"} {
+				columns = append(columns, TableColumn{Header: stat, Width: 18, Align: "R"})
+			}
+		}
+		columns = append(columns, TableColumn{Header: "Avg", Width: 18, Align: "R"})
+
+		// The group headings span the columns of each usage.
+		doc.SetFont(fontFamily, "B", 10)
+		doc.SetFillColor(220, 220, 220)
+		doc.CellFormat(columns[0].Width, lineHeight, "", "LTR", 0, "C", true, 0, "")
+		for _, group := range []string{"System", "User"} {
+			doc.CellFormat(3*18, lineHeight, group, "1", 0, "C", true, 0, "")
+		}
+		doc.CellFormat(18, lineHeight, "Total", "1", 0, "C", true, 0, "")
+		doc.Ln(-1)
+
+		rows := make([][]string, len(r.Data))
+		highlighted := make([]bool, len(r.Data))
+		categories := make([]string, len(r.Data))
+		system := chart.Series{Name: "System", Values: make([]float64, len(r.Data))}
+		user := chart.Series{Name: "User", Values: make([]float64, len(r.Data))}
+		for i, usage := range r.Data {
+			total := ""
+			if usage.TotalAvgUsage != nil {
+				total = formatPercent(*usage.TotalAvgUsage)
+			}
+			rows[i] = append(append(append([]string{usage.CPU}, formatStats(usage.System)...), formatStats(usage.User)...), total)
+			highlighted[i] = r.Threshold > 0 && usage.TotalAvgUsage != nil && *usage.TotalAvgUsage > r.Threshold
+			categories[i] = usage.CPU
+			if usage.System != nil {
+				system.Values[i] = usage.System.AvgUsage
+			}
+			if usage.User != nil {
+				user.Values[i] = usage.User.AvgUsage
+			}
+		}
+		if err := renderHighlightedTable(ctx, doc, columns, rows, highlighted); err != nil {
+			return err
+		}
+		if r.Threshold > 0 {
+			doc.SetFont(fontFamily, "I", 8)
+			doc.CellFormat(0, 6, fmt.Sprintf("Highlighted: total average usage above %s.", formatPercent(r.Threshold)), "", 1, "L", false, 0, "")
+		}
+		doc.Ln(6)
+		return renderChart(doc, &chart.Chart{
+			Type:        chart.StackedBar,
+			Title:       "CPU Average Usage (System + User)",
+			XAxis:       "CPU",
+			Categories:  categories,
+			Series:      []chart.Series{system, user},
+			ValueFormat: chart.FormatPercent,
+			Threshold:   r.Threshold,
+		})
+	})
+}
+
+// formatStats returns the avg/max/min cells of a usage, empty when it is missing.
+func formatStats(stats *CpuUsageStats) []string {
+	if stats == nil {
+		return []string{"", "", ""}
+	}
+	return []string{formatPercent(stats.AvgUsage), formatPercent(stats.MaxUsage), formatPercent(stats.MinUsage)}
+}
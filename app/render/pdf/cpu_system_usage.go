@@ -0,0 +1,33 @@
+package pdf
+
+import (
+	"context"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// CpuSystemUsageData holds the CPU system usage information for a single CPU.
+type CpuSystemUsageData struct {
+	CPU      string
+	AvgUsage float64
+	MaxUsage float64
+	MinUsage float64
+}
+
+// CpuSystemUsageReport holds the overall CPU system usage report data.
+type CpuSystemUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []CpuSystemUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the CPU system usage section (table and bar chart) to the PDF document.
+func (r *CpuSystemUsageReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	rows := make([]cpuUsageRow, len(r.Data))
+	for i, usage := range r.Data {
+		rows[i] = cpuUsageRow(usage)
+	}
+	return renderCpuUsageSection(ctx, doc, "CPU System Usage", rows, r.Unavailable)
+}
@@ -0,0 +1,33 @@
+package pdf
+
+import (
+	"context"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// CpuUserUsageData holds the CPU user usage information for a single CPU.
+type CpuUserUsageData struct {
+	CPU      string
+	AvgUsage float64
+	MaxUsage float64
+	MinUsage float64
+}
+
+// CpuUserUsageReport holds the overall CPU user usage report data.
+type CpuUserUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []CpuUserUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the CPU user usage section (table and bar chart) to the PDF document.
+func (r *CpuUserUsageReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	rows := make([]cpuUsageRow, len(r.Data))
+	for i, usage := range r.Data {
+		rows[i] = cpuUsageRow(usage)
+	}
+	return renderCpuUsageSection(ctx, doc, "CPU User Usage", rows, r.Unavailable)
+}
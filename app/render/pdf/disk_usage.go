@@ -0,0 +1,62 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// DiskUsageData holds the average I/O of one block device over the period.
+type DiskUsageData struct {
+	Device              string
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+	ReadIOPS            float64
+	WriteIOPS           float64
+}
+
+// DiskUsageReport holds the disk I/O report data.
+type DiskUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []DiskUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the disk I/O section (table and bar chart of the total throughput) to the PDF document.
+func (r *DiskUsageReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	return renderSection(ctx, doc, "Disk I/O", r.Unavailable, func() error {
+		columns := []TableColumn{
+			{Header: "Device", Width: 35, Align: "L"},
+			{Header: "Read (MiB/s)", Width: 35, Align: "R"},
+			{Header: "Write (MiB/s)", Width: 35, Align: "R"},
+			{Header: "Read IOPS", Width: 35, Align: "R"},
+			{Header: "Write IOPS", Width: 35, Align: "R"},
+		}
+		rows := make([][]string, len(r.Data))
+		bars := make([]bar, len(r.Data))
+		for i, usage := range r.Data {
+			rows[i] = []string{
+				usage.Device,
+				formatThroughput(usage.ReadBytesPerSecond),
+				formatThroughput(usage.WriteBytesPerSecond),
+				fmt.Sprintf("%.1f", usage.ReadIOPS),
+				fmt.Sprintf("%.1f", usage.WriteIOPS),
+			}
+			total := usage.ReadBytesPerSecond + usage.WriteBytesPerSecond
+			bars[i] = bar{Label: usage.Device, Value: total, Text: formatThroughput(total)}
+		}
+		if err := renderTable(ctx, doc, columns, rows); err != nil {
+			return err
+		}
+		doc.Ln(8)
+		renderBarChart(doc, "Total Throughput (MiB/s)", "Device", bars)
+		return nil
+	})
+}
+
+func formatThroughput(bytesPerSecond float64) string {
+	return fmt.Sprintf("%.2f", bytesPerSecond/(1<<20))
+}
@@ -0,0 +1,374 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+const (
+	fontFamily  = "Helvetica"
+	lineHeight  = 7.0
+	chartHeight = 90.0
+	chartBarGap = 2.0
+	// The charts drawn by render/chart are embedded as PNG images of this size in pixels.
+	chartImageWidth  = 800
+	chartImageHeight = 400
+)
+
+// NewDocument creates an empty A4 portrait document ready to receive report sections. The metadata
+// of the document describe the report of h, and every page after the title page repeats its title and
+// period at the top and its generation time and request ID at the bottom.
+func NewDocument(h header.Header) *gofpdf.Fpdf {
+	doc := gofpdf.New("P", "mm", "A4", "")
+	doc.SetTitle(h.Title, true)
+	doc.SetSubject(h.Period(), true)
+	doc.SetCreator("reports-rendering-go", true)
+	var keywords []string
+	for _, field := range h.Fields() {
+		keywords = append(keywords, field.Label+": "+field.Value)
+	}
+	doc.SetKeywords(strings.Join(keywords, "; "), true)
+	doc.SetCreationDate(h.GeneratedAt)
+	doc.SetAutoPageBreak(true, 15)
+	doc.AliasNbPages("")
+	doc.SetHeaderFunc(func() {
+		if doc.PageNo() == 1 {
+			return
+		}
+		doc.SetFont(fontFamily, "", 8)
+		doc.SetTextColor(102, 102, 102)
+		left, _, _, _ := doc.GetMargins()
+		doc.CellFormat(0, 5, h.Title, "", 0, "L", false, 0, "")
+		doc.SetX(left)
+		doc.CellFormat(0, 5, h.Period(), "B", 1, "R", false, 0, "")
+		doc.SetTextColor(0, 0, 0)
+		doc.Ln(4)
+	})
+	doc.SetFooterFunc(func() {
+		doc.SetY(-12)
+		doc.SetFont(fontFamily, "I", 8)
+		left, _, _, _ := doc.GetMargins()
+		footer := "Generated " + h.Generated()
+		if h.RequestID != "" {
+			footer += " - Request " + h.RequestID
+		}
+		doc.CellFormat(0, 8, footer, "", 0, "L", false, 0, "")
+		doc.SetX(left)
+		doc.CellFormat(0, 8, fmt.Sprintf("Page %d/{nb}", doc.PageNo()), "", 0, "R", false, 0, "")
+	})
+	return doc
+}
+
+// RenderTitlePage adds the cover page with the title of the report and its header block.
+func RenderTitlePage(doc *gofpdf.Fpdf, h header.Header) error {
+	doc.AddPage()
+	_, pageHeight := doc.GetPageSize()
+
+	doc.SetY(pageHeight / 3)
+	doc.SetFont(fontFamily, "B", 26)
+	doc.CellFormat(0, 14, h.Title, "", 1, "C", false, 0, "")
+
+	doc.Ln(6)
+	for _, field := range h.Fields() {
+		doc.SetFont(fontFamily, "B", 11)
+		doc.CellFormat(0, 6, field.Label, "", 1, "C", false, 0, "")
+		doc.SetFont(fontFamily, "", 12)
+		doc.CellFormat(0, 7, field.Value, "", 1, "C", false, 0, "")
+		doc.Ln(2)
+	}
+
+	if err := doc.Error(); err != nil {
+		return fmt.Errorf("failed to render title page: %w", err)
+	}
+	return nil
+}
+
+// cpuUsageRow is the common shape of a per-CPU table row.
+type cpuUsageRow struct {
+	CPU      string
+	AvgUsage float64
+	MaxUsage float64
+	MinUsage float64
+}
+
+// renderCpuUsageSection lays out a heading, the per-CPU avg/max/min table and a bar chart of the averages,
+// or a placeholder when the data is unavailable.
+func renderCpuUsageSection(ctx context.Context, doc *gofpdf.Fpdf, title string, rows []cpuUsageRow, unavailable string) error {
+	return renderSection(ctx, doc, title, unavailable, func() error {
+		cells := make([][]string, len(rows))
+		bars := make([]bar, len(rows))
+		for i, row := range rows {
+			cells[i] = []string{row.CPU, formatPercent(row.AvgUsage), formatPercent(row.MaxUsage), formatPercent(row.MinUsage)}
+			bars[i] = bar{Label: row.CPU, Value: row.AvgUsage, Text: formatPercent(row.AvgUsage)}
+		}
+		if err := renderTable(ctx, doc, cpuUsageColumns, cells); err != nil {
+			return err
+		}
+		doc.Ln(8)
+		renderBarChart(doc, "CPU Average Usage", "CPU", bars)
+		return nil
+	})
+}
+
+var cpuUsageColumns = []TableColumn{
+	{Header: "CPU", Width: 40, Align: "L"},
+	{Header: "Average Usage (%)", Width: 45, Align: "R"},
+	{Header: "Max Usage (%)", Width: 45, Align: "R"},
+	{Header: "Min Usage (%)", Width: 45, Align: "R"},
+}
+
+// renderSection starts a new page with the section heading, then renders the body, or a placeholder
+// when the data is unavailable.
+func renderSection(ctx context.Context, doc *gofpdf.Fpdf, title string, unavailable string, body func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	doc.AddPage()
+	return renderBlock(doc, title, unavailable, body)
+}
+
+// renderBlock continues the current page with a heading, starting a new page only when there is no
+// room left for it, then renders the body, or a placeholder when the data is unavailable.
+func renderBlock(doc *gofpdf.Fpdf, title string, unavailable string, body func() error) error {
+	_, pageHeight := doc.GetPageSize()
+	if doc.PageNo() == 0 || doc.GetY()+40 > pageHeight-15 {
+		doc.AddPage()
+	} else {
+		doc.Ln(6)
+	}
+	doc.SetFont(fontFamily, "B", 16)
+	doc.CellFormat(0, 10, title, "", 1, "L", false, 0, "")
+	doc.Ln(2)
+
+	if unavailable != "" {
+		renderUnavailable(doc, unavailable)
+	} else if err := body(); err != nil {
+		return err
+	}
+
+	if err := doc.Error(); err != nil {
+		return fmt.Errorf("failed to render section %q: %w", title, err)
+	}
+	return nil
+}
+
+// TableColumn describes a column of a section table. Align is a gofpdf alignment ("L", "C" or "R").
+// A zero Width shares the page width left by the other columns.
+type TableColumn struct {
+	Header string
+	Width  float64
+	Align  string
+}
+
+// renderTable draws a bordered table with a shaded header row and one row per entry of rows.
+func renderTable(ctx context.Context, doc *gofpdf.Fpdf, columns []TableColumn, rows [][]string) error {
+	return renderHighlightedTable(ctx, doc, columns, rows, nil)
+}
+
+// renderHighlightedTable draws a table like renderTable, filling the rows whose highlighted entry is
+// true in light red.
+func renderHighlightedTable(ctx context.Context, doc *gofpdf.Fpdf, columns []TableColumn, rows [][]string, highlighted []bool) error {
+	columns = fitColumns(doc, columns)
+	doc.SetFont(fontFamily, "B", 10)
+	doc.SetFillColor(220, 220, 220)
+	for _, column := range columns {
+		doc.CellFormat(column.Width, lineHeight, column.Header, "1", 0, "C", true, 0, "")
+	}
+	doc.Ln(-1)
+
+	doc.SetFont(fontFamily, "", 10)
+	doc.SetFillColor(255, 199, 206)
+	for r, row := range rows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		highlight := r < len(highlighted) && highlighted[r]
+		if highlight {
+			doc.SetTextColor(156, 0, 6)
+		}
+		for i, column := range columns {
+			doc.CellFormat(column.Width, lineHeight, row[i], "1", 0, column.Align, highlight, 0, "")
+		}
+		doc.SetTextColor(0, 0, 0)
+		doc.Ln(-1)
+	}
+	return nil
+}
+
+// fitColumns gives the columns without a width an equal share of the remaining page width.
+func fitColumns(doc *gofpdf.Fpdf, columns []TableColumn) []TableColumn {
+	left, _, right, _ := doc.GetMargins()
+	pageWidth, _ := doc.GetPageSize()
+	remaining, unsized := pageWidth-left-right, 0
+	for _, column := range columns {
+		remaining -= column.Width
+		if column.Width <= 0 {
+			unsized++
+		}
+	}
+	if unsized == 0 {
+		return columns
+	}
+	fitted := slices.Clone(columns)
+	for i := range fitted {
+		if fitted[i].Width <= 0 {
+			fitted[i].Width = max(remaining/float64(unsized), 15)
+		}
+	}
+	return fitted
+}
+
+// bar is one bar of a bar chart: its category label, its value and the text printed above it.
+type bar struct {
+	Label string
+	Value float64
+	Text  string
+}
+
+// renderBarChart draws a vertical bar chart scaled to the largest value, with axisLabel under the categories.
+func renderBarChart(doc *gofpdf.Fpdf, title string, axisLabel string, bars []bar) {
+	left, _, right, _ := doc.GetMargins()
+	pageWidth, pageHeight := doc.GetPageSize()
+	chartWidth := pageWidth - left - right
+
+	// Keep the chart on a single page together with its title and labels.
+	if doc.GetY()+chartHeight+30 > pageHeight-15 {
+		doc.AddPage()
+	}
+
+	if title != "" {
+		doc.SetFont(fontFamily, "B", 12)
+		doc.CellFormat(0, 8, title, "", 1, "C", false, 0, "")
+	}
+
+	top := doc.GetY() + 4
+	baseline := top + chartHeight
+
+	doc.SetDrawColor(0, 0, 0)
+	doc.Line(left, top, left, baseline)
+	doc.Line(left, baseline, left+chartWidth, baseline)
+
+	if len(bars) == 0 {
+		doc.SetFont(fontFamily, "I", 10)
+		doc.SetXY(left, top+chartHeight/2)
+		doc.CellFormat(chartWidth, lineHeight, "No data", "", 1, "C", false, 0, "")
+		doc.SetY(baseline + 4)
+		return
+	}
+
+	maxValue := 0.0
+	for _, b := range bars {
+		if b.Value > maxValue {
+			maxValue = b.Value
+		}
+	}
+	if maxValue <= 0 {
+		maxValue = 1
+	}
+
+	// With many bars the gap shrinks with the slot, so that bars keep a positive width, and the labels
+	// that would overlap are left out: only every few values and categories are printed.
+	slot := chartWidth / float64(len(bars))
+	gap := min(chartBarGap, slot/5)
+	barWidth := slot - gap
+	doc.SetFillColor(68, 114, 196)
+	doc.SetFont(fontFamily, "", 7)
+	widestText, widestLabel := 0.0, 0.0
+	for _, b := range bars {
+		widestText = max(widestText, doc.GetStringWidth(b.Text))
+		widestLabel = max(widestLabel, doc.GetStringWidth(b.Label))
+	}
+	everyText := labelInterval(widestText, slot)
+	everyLabel := labelInterval(widestLabel, slot)
+	for i, b := range bars {
+		barHeight := chartHeight * b.Value / maxValue
+		x := left + float64(i)*slot + gap/2
+		if barHeight > 0 {
+			doc.Rect(x, baseline-barHeight, barWidth, barHeight, "F")
+		}
+		center := x + barWidth/2
+		if i%everyText == 0 {
+			doc.SetXY(center-widestText/2-1, baseline-barHeight-5)
+			doc.CellFormat(widestText+2, 4, b.Text, "", 0, "C", false, 0, "")
+		}
+		if i%everyLabel == 0 {
+			doc.SetXY(center-widestLabel/2-1, baseline+1)
+			doc.CellFormat(widestLabel+2, 4, b.Label, "", 0, "C", false, 0, "")
+		}
+	}
+
+	doc.SetFont(fontFamily, "", 9)
+	doc.SetXY(left, baseline+7)
+	doc.CellFormat(chartWidth, 5, axisLabel, "", 1, "C", false, 0, "")
+}
+
+// labelInterval returns how many slots apart labels as wide as width are printed so that they do not
+// overlap: 1 when every label fits its slot.
+func labelInterval(width float64, slot float64) int {
+	return max(1, int(math.Ceil((width+1)/slot)))
+}
+
+// renderChart draws c as an image spanning the width of the page, on the next page when it does not
+// fit the current one.
+func renderChart(doc *gofpdf.Fpdf, c *chart.Chart) error {
+	c.Width, c.Height = chartImageWidth, chartImageHeight
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid chart: %w", err)
+	}
+	var image bytes.Buffer
+	if err := c.PNG(&image); err != nil {
+		return fmt.Errorf("failed to draw chart: %w", err)
+	}
+
+	left, _, right, _ := doc.GetMargins()
+	pageWidth, pageHeight := doc.GetPageSize()
+	width := pageWidth - left - right
+	height := width * chartImageHeight / chartImageWidth
+	if doc.GetY()+height > pageHeight-15 {
+		doc.AddPage()
+	}
+	// Images are registered by name; equal charts share one.
+	name := fmt.Sprintf("chart-%x", sha256.Sum256(image.Bytes()))
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	doc.RegisterImageOptionsReader(name, options, &image)
+	doc.ImageOptions(name, left, doc.GetY(), width, height, false, options, 0, "")
+	doc.SetY(doc.GetY() + height + 2)
+	return doc.Error()
+}
+
+// renderNoData writes the placeholder of a chart without categories.
+func renderNoData(doc *gofpdf.Fpdf) {
+	doc.SetFont(fontFamily, "I", 10)
+	doc.CellFormat(0, lineHeight, "No data", "", 1, "C", false, 0, "")
+}
+
+// renderUnavailable draws a highlighted box telling the reader that the section has no data.
+func renderUnavailable(doc *gofpdf.Fpdf, reason string) {
+	doc.SetFillColor(255, 199, 206)
+	doc.SetTextColor(156, 0, 6)
+	doc.SetFont(fontFamily, "B", 14)
+	doc.CellFormat(0, 12, "Data unavailable", "LTR", 1, "C", true, 0, "")
+	doc.SetFont(fontFamily, "", 10)
+	doc.MultiCell(0, 6, reason, "LBR", "C", true)
+	doc.SetTextColor(0, 0, 0)
+}
+
+// formatPercent renders a usage ratio with the same "0.00%" format used by the XLSX reports.
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.2f%%", value*100)
+}
+
+// formatBytes renders a byte count in GiB, the unit used by the memory and network sections.
+func formatBytes(value float64) string {
+	return fmt.Sprintf("%.2f GiB", value/(1<<30))
+}
@@ -0,0 +1,80 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+func TestLabelInterval(t *testing.T) {
+	for _, test := range []struct {
+		width, slot float64
+		want        int
+	}{
+		{width: 8, slot: 20, want: 1},
+		{width: 8, slot: 9, want: 1},
+		{width: 8, slot: 3, want: 3},
+		{width: 8, slot: 0.37, want: 25},
+	} {
+		if got := labelInterval(test.width, test.slot); got != test.want {
+			t.Errorf("labelInterval(%v, %v) = %d, want %d", test.width, test.slot, got, test.want)
+		}
+	}
+}
+
+func TestRenderBarChartOfManyCPUs(t *testing.T) {
+	doc := NewDocument(header.Header{Title: "CPU"})
+	doc.AddPage()
+	bars := make([]bar, 512)
+	for i := range bars {
+		bars[i] = bar{Label: fmt.Sprintf("cpu%d", i), Value: float64(i%100) / 100, Text: formatPercent(float64(i%100) / 100)}
+	}
+	renderBarChart(doc, "CPU Average Usage", "CPU", bars)
+	if err := doc.Error(); err != nil {
+		t.Fatalf("renderBarChart() = %v", err)
+	}
+}
+
+func TestChartSectionDrawsEverySeries(t *testing.T) {
+	doc := NewDocument(header.Header{Title: "Host"})
+	doc.AddPage()
+	section := &ChartSection{
+		Title:      "Disk Throughput",
+		Type:       "column",
+		Categories: []string{"sda", "sdb"},
+		Series: []ChartSeries{
+			{Name: "Read (MiB/s)", Values: []float64{1, 2}},
+			{Name: "Write (MiB/s)", Values: []float64{3, 4}},
+		},
+	}
+	if err := section.Render(context.Background(), doc); err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+	var out bytes.Buffer
+	if err := doc.Output(&out); err != nil {
+		t.Fatalf("Output() = %v", err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("/Subtype /Image")) {
+		t.Errorf("the chart is not embedded as an image")
+	}
+}
+
+func TestCpuOverviewReportOfManyCPUs(t *testing.T) {
+	doc := NewDocument(header.Header{Title: "CPU"})
+	report := &CpuOverviewReport{Threshold: 0.8}
+	for i := range 256 {
+		total := float64(i%100) / 100
+		report.Data = append(report.Data, CpuOverviewData{
+			CPU:           fmt.Sprintf("cpu%d", i),
+			System:        &CpuUsageStats{AvgUsage: total / 4, MaxUsage: total / 2},
+			User:          &CpuUsageStats{AvgUsage: total * 3 / 4, MaxUsage: total},
+			TotalAvgUsage: &total,
+		})
+	}
+	if err := report.Render(context.Background(), doc); err != nil {
+		t.Fatalf("Render() = %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package pdf
+
+import (
+	"context"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// MemoryUsageData holds one memory metric (used, cached or swap) over the period, in bytes.
+type MemoryUsageData struct {
+	Metric   string
+	AvgBytes float64
+	MaxBytes float64
+	MinBytes float64
+}
+
+// MemoryUsageReport holds the memory usage report data.
+type MemoryUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []MemoryUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the memory usage section (table and bar chart of the averages) to the PDF document.
+func (r *MemoryUsageReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	return renderSection(ctx, doc, "Memory Usage", r.Unavailable, func() error {
+		columns := []TableColumn{
+			{Header: "Metric", Width: 40, Align: "L"},
+			{Header: "Average", Width: 45, Align: "R"},
+			{Header: "Max", Width: 45, Align: "R"},
+			{Header: "Min", Width: 45, Align: "R"},
+		}
+		rows := make([][]string, len(r.Data))
+		bars := make([]bar, len(r.Data))
+		for i, usage := range r.Data {
+			rows[i] = []string{usage.Metric, formatBytes(usage.AvgBytes), formatBytes(usage.MaxBytes), formatBytes(usage.MinBytes)}
+			bars[i] = bar{Label: usage.Metric, Value: usage.AvgBytes, Text: formatBytes(usage.AvgBytes)}
+		}
+		if err := renderTable(ctx, doc, columns, rows); err != nil {
+			return err
+		}
+		doc.Ln(8)
+		renderBarChart(doc, "Average Memory Usage", "Metric", bars)
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package pdf
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// NetworkUsageData holds the traffic and errors of one network interface over the period.
+type NetworkUsageData struct {
+	Interface string
+	RxBytes   int64
+	TxBytes   int64
+	RxErrors  int64
+	TxErrors  int64
+}
+
+// NetworkUsageReport holds the network usage report data.
+type NetworkUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []NetworkUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the network usage section (table and bar chart of the total traffic) to the PDF document.
+func (r *NetworkUsageReport) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	return renderSection(ctx, doc, "Network Usage", r.Unavailable, func() error {
+		columns := []TableColumn{
+			{Header: "Interface", Width: 35, Align: "L"},
+			{Header: "Received", Width: 35, Align: "R"},
+			{Header: "Sent", Width: 35, Align: "R"},
+			{Header: "Receive Errors", Width: 35, Align: "R"},
+			{Header: "Send Errors", Width: 35, Align: "R"},
+		}
+		rows := make([][]string, len(r.Data))
+		bars := make([]bar, len(r.Data))
+		for i, usage := range r.Data {
+			rows[i] = []string{
+				usage.Interface,
+				formatBytes(float64(usage.RxBytes)),
+				formatBytes(float64(usage.TxBytes)),
+				strconv.FormatInt(usage.RxErrors, 10),
+				strconv.FormatInt(usage.TxErrors, 10),
+			}
+			total := float64(usage.RxBytes + usage.TxBytes)
+			bars[i] = bar{Label: usage.Interface, Value: total, Text: formatBytes(total)}
+		}
+		if err := renderTable(ctx, doc, columns, rows); err != nil {
+			return err
+		}
+		doc.Ln(8)
+		renderBarChart(doc, "Total Traffic", "Interface", bars)
+		return nil
+	})
+}
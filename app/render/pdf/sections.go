@@ -0,0 +1,139 @@
+package pdf
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+)
+
+// The sections below are generic building blocks for report definitions. Unlike the CPU, memory, disk
+// and network sections they do not start a new page, so that short sections share one.
+
+// TableSection is a titled table of preformatted cells.
+type TableSection struct {
+	Title   string
+	Columns []TableColumn
+	Rows    [][]string
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the table to the PDF document.
+func (s *TableSection) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return renderBlock(doc, s.Title, s.Unavailable, func() error {
+		return renderTable(ctx, doc, s.Columns, s.Rows)
+	})
+}
+
+// ChartSeries is one plotted series of a ChartSection.
+type ChartSeries struct {
+	Name   string
+	Values []float64
+}
+
+// ChartSection is a titled chart of every series: bar and column charts group the bars of a category,
+// line and area charts draw a line per series.
+type ChartSection struct {
+	Title string
+	// Type is "bar", "column", "line" or "area".
+	Type          string
+	CategoryLabel string
+	Categories    []string
+	Series        []ChartSeries
+	// ValueFormat formats the values of the value axis; chart.FormatNumber by default.
+	ValueFormat func(value float64) string
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// chartTypes maps the chart types of a ChartSection to the charts drawn.
+var chartTypes = map[string]chart.Type{"bar": chart.Bar, "column": chart.Bar, "line": chart.Line, "area": chart.Area}
+
+// Render adds the chart to the PDF document.
+func (s *ChartSection) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return renderBlock(doc, s.Title, s.Unavailable, func() error {
+		if len(s.Categories) == 0 {
+			renderNoData(doc)
+			return nil
+		}
+		c := &chart.Chart{Type: chartTypes[s.Type], XAxis: s.CategoryLabel, Categories: s.Categories, ValueFormat: s.ValueFormat}
+		for _, series := range s.Series {
+			c.Series = append(c.Series, chart.Series{Name: series.Name, Values: series.Values})
+		}
+		return renderChart(doc, c)
+	})
+}
+
+// KPI is one headline figure of a KPISection.
+type KPI struct {
+	Label string
+	Value string
+}
+
+// KPISection shows headline figures as a row of tiles.
+type KPISection struct {
+	Title string
+	KPIs  []KPI
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// Render adds the KPI tiles to the PDF document, wrapping to a new row every four tiles.
+func (s *KPISection) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return renderBlock(doc, s.Title, s.Unavailable, func() error {
+		const perRow, tileHeight = 4, 22.0
+		left, _, right, _ := doc.GetMargins()
+		pageWidth, _ := doc.GetPageSize()
+		tileWidth := (pageWidth - left - right) / perRow
+		for i, kpi := range s.KPIs {
+			if i%perRow == 0 && i > 0 {
+				doc.SetY(doc.GetY() + tileHeight + 2)
+			}
+			x, y := left+float64(i%perRow)*tileWidth, doc.GetY()
+			doc.SetFillColor(242, 242, 242)
+			doc.Rect(x+1, y, tileWidth-2, tileHeight, "F")
+			doc.SetXY(x+1, y+3)
+			doc.SetFont(fontFamily, "B", 14)
+			doc.CellFormat(tileWidth-2, 8, kpi.Value, "", 0, "C", false, 0, "")
+			doc.SetXY(x+1, y+12)
+			doc.SetFont(fontFamily, "", 9)
+			doc.CellFormat(tileWidth-2, 6, kpi.Label, "", 0, "C", false, 0, "")
+			doc.SetY(y)
+		}
+		doc.SetY(doc.GetY() + tileHeight + 2)
+		return nil
+	})
+}
+
+// TextSection is a titled block of free text. Blank lines separate paragraphs, whose lines are joined.
+type TextSection struct {
+	Title string
+	Text  string
+}
+
+// Render adds the text to the PDF document.
+func (s *TextSection) Render(ctx context.Context, doc *gofpdf.Fpdf) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return renderBlock(doc, s.Title, "", func() error {
+		doc.SetFont(fontFamily, "", 10)
+		for _, paragraph := range strings.Split(strings.TrimSpace(s.Text), "\n\n") {
+			doc.MultiCell(0, 5, strings.Join(strings.Fields(paragraph), " "), "", "L", false)
+			doc.Ln(2)
+		}
+		return nil
+	})
+}
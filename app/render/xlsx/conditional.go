@@ -0,0 +1,150 @@
+package xlsx
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ConditionalFormat is a list of conditional formatting rules over a range of a sheet, such as
+// "A2:J9". Like charts, formats are described so that they can be added once the rows are written.
+type ConditionalFormat struct {
+	Range string
+	Rules []ConditionalRule
+}
+
+// ConditionalRule is an excelize conditional format. Style, when set, is created in the workbook the
+// rule is added to and applied to the cells matching the rule.
+type ConditionalRule struct {
+	Options excelize.ConditionalFormatOptions
+	Style   *excelize.Style
+}
+
+// ConditionalSheet is a sheet with conditional formats over its data rows firstRow to lastRow. The
+// streaming writer adds them to every sheet the rows are written to.
+type ConditionalSheet interface {
+	ConditionalFormats(sheetName string, firstRow int, lastRow int) []ConditionalFormat
+}
+
+// AddConditionalFormats adds the described conditional formats to a sheet of file.
+func AddConditionalFormats(file *excelize.File, sheetName string, formats []ConditionalFormat) error {
+	for _, format := range formats {
+		rules := make([]excelize.ConditionalFormatOptions, len(format.Rules))
+		for i, rule := range format.Rules {
+			rules[i] = rule.Options
+			if rule.Style != nil {
+				style, err := file.NewConditionalStyle(rule.Style)
+				if err != nil {
+					return fmt.Errorf("failed to create conditional style: %w", err)
+				}
+				rules[i].Format = &style
+			}
+		}
+		if err := file.SetConditionalFormat(sheetName, format.Range, rules); err != nil {
+			return fmt.Errorf("failed to set conditional format of %s: %w", format.Range, err)
+		}
+	}
+	return nil
+}
+
+// Threshold colors the values of a metric above Warning amber and above Critical red. Limits are in
+// the unit of the cells, e.g. 0.9 for 90% in a percentage column; a zero limit is not applied.
+type Threshold struct {
+	Warning  float64 `json:"warning" yaml:"warning" description:"Values above it are highlighted in amber (0 for none)"`
+	Critical float64 `json:"critical" yaml:"critical" description:"Values above it are highlighted in red (0 for none)"`
+}
+
+// Validate checks that the limits are not negative and that Warning is below Critical when both are set.
+func (threshold Threshold) Validate() error {
+	if threshold.Warning < 0 || threshold.Critical < 0 {
+		return errors.New("limits must not be negative")
+	}
+	if threshold.Warning > 0 && threshold.Critical > 0 && threshold.Warning >= threshold.Critical {
+		return fmt.Errorf("warning (%g) must be below critical (%g)", threshold.Warning, threshold.Critical)
+	}
+	return nil
+}
+
+// Thresholds are the thresholds of the columns of a sheet, keyed by the field the column shows,
+// such as "max_usage".
+type Thresholds map[string]Threshold
+
+// Validate checks every threshold.
+func (thresholds Thresholds) Validate() error {
+	for field, threshold := range thresholds {
+		if err := threshold.Validate(); err != nil {
+			return fmt.Errorf("threshold of %q: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// ColumnHighlight describes the conditional formats of the data cells of a column.
+type ColumnHighlight struct {
+	Threshold Threshold
+	// DataBar draws a bar proportional to the value in every cell, from zero to the largest value.
+	DataBar bool
+	// Icons ranks the values of the column with traffic lights, red for the highest third.
+	Icons bool
+}
+
+// Styles of the threshold rules, the same colors as Excel's "Highlight Cells Rules" presets.
+var (
+	warningStyle  = excelize.Style{Font: &excelize.Font{Color: "9C5700"}, Fill: excelize.Fill{Type: "pattern", Color: []string{"FFEB9C"}, Pattern: 1}}
+	criticalStyle = excelize.Style{Font: &excelize.Font{Color: "9C0006"}, Fill: excelize.Fill{Type: "pattern", Color: []string{"FFC7CE"}, Pattern: 1}}
+)
+
+// rules returns the conditional formatting rules of the highlight, the critical one first so that
+// it takes precedence over the warning one.
+func (highlight ColumnHighlight) rules() []ConditionalRule {
+	var rules []ConditionalRule
+	limits := []struct {
+		value float64
+		style excelize.Style
+	}{{highlight.Threshold.Critical, criticalStyle}, {highlight.Threshold.Warning, warningStyle}}
+	for _, limit := range limits {
+		if limit.value <= 0 {
+			continue
+		}
+		style := limit.style
+		rules = append(rules, ConditionalRule{
+			Options: excelize.ConditionalFormatOptions{Type: "cell", Criteria: ">", Value: strconv.FormatFloat(limit.value, 'f', -1, 64)},
+			Style:   &style,
+		})
+	}
+	if highlight.DataBar {
+		rules = append(rules, ConditionalRule{Options: excelize.ConditionalFormatOptions{
+			Type: "data_bar", Criteria: "=", MinType: "num", MinValue: "0", MaxType: "max", BarColor: "638EC6",
+		}})
+	}
+	if highlight.Icons {
+		rules = append(rules, ConditionalRule{Options: excelize.ConditionalFormatOptions{
+			Type: "icon_set", IconStyle: "3TrafficLights1", ReverseIcons: true,
+		}})
+	}
+	return rules
+}
+
+// columnConditionalFormats returns the conditional formats of the highlighted columns over the data
+// rows firstRow to lastRow.
+func columnConditionalFormats(columns []StreamColumn, firstRow int, lastRow int) []ConditionalFormat {
+	if lastRow < firstRow {
+		return nil
+	}
+	var formats []ConditionalFormat
+	for i, column := range columns {
+		if column.Highlight == nil {
+			continue
+		}
+		rules := column.Highlight.rules()
+		if len(rules) == 0 {
+			continue
+		}
+		first, _ := excelize.CoordinatesToCellName(i+1, firstRow)
+		last, _ := excelize.CoordinatesToCellName(i+1, lastRow)
+		formats = append(formats, ConditionalFormat{Range: first + ":" + last, Rules: rules})
+	}
+	return formats
+}
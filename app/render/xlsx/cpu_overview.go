@@ -0,0 +1,147 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// CpuUsageStats is the average, maximum and minimum usage of a CPU, as ratios.
+type CpuUsageStats struct {
+	AvgUsage float64
+	MaxUsage float64
+	MinUsage float64
+}
+
+// CpuOverviewData is the system and user usage of one CPU. System or User is nil when the CPU is
+// missing from that dataset, and TotalAvgUsage, their added averages, when it is missing from either.
+type CpuOverviewData struct {
+	CPU           string
+	System        *CpuUsageStats
+	User          *CpuUsageStats
+	TotalAvgUsage *float64
+}
+
+// CpuOverviewReport joins the system and user usage per CPU with their total, the busy time of each core.
+type CpuOverviewReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []CpuOverviewData
+	// Threshold, when positive, highlights the CPUs whose total average usage is above it.
+	Threshold float64
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// totalAvgColumn is the column of the total average usage, the one compared with the threshold.
+const totalAvgColumn = "H"
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *CpuOverviewReport) SheetName() string {
+	return "Overview"
+}
+
+// Render creates the overview sheet in file. It stops early when ctx is done.
+func (r *CpuOverviewReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the overview table and its highlighting, and returns the charts to anchor on the sheet.
+func (r *CpuOverviewReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	if lastRow > 1 {
+		if err := AddConditionalFormats(file, sheetName, r.ConditionalFormats(sheetName, 2, lastRow)); err != nil {
+			return nil, err
+		}
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// Columns returns the CPU column followed by the avg/max/min of the system and user usage and the
+// total average. The total has no max or min: the system and user extremes need not coincide.
+func (r *CpuOverviewReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	percent := &excelize.Style{NumFmt: 10, Border: borders()}
+	columns := []StreamColumn{labelColumn("CPU")}
+	for _, group := range []string{"System", "User"} {
+		for _, stat := range []string{"Avg", "Max", "Min"} {
+			columns = append(columns, StreamColumn{Header: fmt.Sprintf("%s %s (%%)", group, stat), Width: 16, Style: percent, Summarize: true})
+		}
+	}
+	return append(columns, StreamColumn{Header: "Total Avg (%)", Width: 16, Style: percent, Summarize: true})
+}
+
+// Rows yields one row per CPU. The cells of a missing dataset, and the totals of its CPUs, are left empty.
+func (r *CpuOverviewReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			row := make([]any, 8)
+			row[0] = usage.CPU
+			if usage.System != nil {
+				row[1], row[2], row[3] = usage.System.AvgUsage, usage.System.MaxUsage, usage.System.MinUsage
+			}
+			if usage.User != nil {
+				row[4], row[5], row[6] = usage.User.AvgUsage, usage.User.MaxUsage, usage.User.MinUsage
+			}
+			if usage.TotalAvgUsage != nil {
+				row[7] = *usage.TotalAvgUsage
+			}
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns a stacked column chart of the average system and user usage of every CPU.
+func (r *CpuOverviewReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || lastRow < firstRow {
+		return nil
+	}
+	chart := columnChart(sheetName, "CPU Average Usage (System + User)", "Average Usage (%)", firstRow, lastRow, r.Columns(), 2, 5)
+	chart.Chart.Type = excelize.ColStacked
+	return []SheetChart{chart}
+}
+
+// ConditionalFormats highlights the rows of the CPUs whose total average usage is above the threshold.
+func (r *CpuOverviewReport) ConditionalFormats(sheetName string, firstRow int, lastRow int) []ConditionalFormat {
+	if r.Unavailable != "" || r.Threshold <= 0 || lastRow < firstRow {
+		return nil
+	}
+	return []ConditionalFormat{{
+		Range: fmt.Sprintf("A%d:%s%d", firstRow, totalAvgColumn, lastRow),
+		Rules: []ConditionalRule{{
+			Options: excelize.ConditionalFormatOptions{
+				Type:     "formula",
+				Criteria: fmt.Sprintf("$%s%d>%s", totalAvgColumn, firstRow, strconv.FormatFloat(r.Threshold, 'f', -1, 64)),
+			},
+			Style: &excelize.Style{
+				Font: &excelize.Font{Color: "9C0006"},
+				Fill: excelize.Fill{Type: "pattern", Color: []string{"FFC7CE"}, Pattern: 1},
+			},
+		}},
+	}}
+}
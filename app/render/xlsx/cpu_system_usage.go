@@ -1,7 +1,9 @@
 package xlsx
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"log"
 
 	"github.com/xuri/excelize/v2"
@@ -18,33 +20,52 @@ type CpuSystemUsageReport struct {
 	DateFrom int64
 	DateTo   int64
 	Data     []CpuSystemUsageData
+	// Thresholds highlight the avg_usage, max_usage and min_usage columns.
+	Thresholds Thresholds
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
 }
 
-func (r *CpuSystemUsageReport) Render(file *excelize.File) error {
-	sheetName := "CPU System Usage"
+func (r *CpuSystemUsageReport) SheetName() string {
+	return "CPU System Usage"
+}
+
+func (r *CpuSystemUsageReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+func (r *CpuSystemUsageReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
 	_, err := file.NewSheet(sheetName)
 	if err != nil {
-		return fmt.Errorf("failed to create sheet: %w", err)
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
 	}
 
 	boldFont := &excelize.Font{Bold: true}
 
 	if err := file.SetColWidth(sheetName, "A", "A", 15); err != nil {
-		return fmt.Errorf("failed to set column width: %w", err)
+		return nil, fmt.Errorf("failed to set column width: %w", err)
 	}
 	if err := file.SetColWidth(sheetName, "B", "D", 18); err != nil {
-		return fmt.Errorf("failed to set column width: %w", err)
+		return nil, fmt.Errorf("failed to set column width: %w", err)
 	}
 
 	headers := []string{"CPU", "Average Usage (%)", "Max Usage (%)", "Min Usage (%)"}
 	for colNum, header := range headers {
 		cell, _ := excelize.CoordinatesToCellName(colNum+1, 1)
 		if err := file.SetCellValue(sheetName, cell, header); err != nil {
-			return fmt.Errorf("failed to set cell value for header: %w", err)
+			return nil, fmt.Errorf("failed to set cell value for header: %w", err)
 		}
 		styleID, _ := file.NewStyle(&excelize.Style{Font: boldFont})
 		if err := file.SetCellStyle(sheetName, cell, cell, styleID); err != nil {
-			return fmt.Errorf("failed to set cell style for header: %w", err)
+			return nil, fmt.Errorf("failed to set cell style for header: %w", err)
 		}
 	}
 
@@ -58,7 +79,7 @@ func (r *CpuSystemUsageReport) Render(file *excelize.File) error {
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create data cell style: %w", err)
+		return nil, fmt.Errorf("failed to create data cell style: %w", err)
 	}
 
 	textCellStyleID, err := file.NewStyle(&excelize.Style{
@@ -70,87 +91,92 @@ func (r *CpuSystemUsageReport) Render(file *excelize.File) error {
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create text cell style: %w", err)
+		return nil, fmt.Errorf("failed to create text cell style: %w", err)
 	}
 
 	for rowNum, usage := range r.Data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		row := rowNum + 2
 
 		cellCPU, _ := excelize.CoordinatesToCellName(1, row)
 		if err := file.SetCellValue(sheetName, cellCPU, usage.CPU); err != nil {
-			return fmt.Errorf("failed to set CPU: %w", err)
+			return nil, fmt.Errorf("failed to set CPU: %w", err)
 		}
 		if err := file.SetCellStyle(sheetName, cellCPU, cellCPU, textCellStyleID); err != nil {
-			return fmt.Errorf("failed to set style for CPU: %w", err)
+			return nil, fmt.Errorf("failed to set style for CPU: %w", err)
 		}
 
 		cellAvg, _ := excelize.CoordinatesToCellName(2, row)
 		if err := file.SetCellValue(sheetName, cellAvg, usage.AvgUsage); err != nil {
-			return fmt.Errorf("failed to set AvgUsage: %w", err)
+			return nil, fmt.Errorf("failed to set AvgUsage: %w", err)
 		}
 		if err := file.SetCellStyle(sheetName, cellAvg, cellAvg, dataCellStyleID); err != nil {
-			return fmt.Errorf("failed to set style for AvgUsage: %w", err)
+			return nil, fmt.Errorf("failed to set style for AvgUsage: %w", err)
 		}
 
 		cellMax, _ := excelize.CoordinatesToCellName(3, row)
 		if err := file.SetCellValue(sheetName, cellMax, usage.MaxUsage); err != nil {
-			return fmt.Errorf("failed to set MaxUsage: %w", err)
+			return nil, fmt.Errorf("failed to set MaxUsage: %w", err)
 		}
 		if err := file.SetCellStyle(sheetName, cellMax, cellMax, dataCellStyleID); err != nil {
-			return fmt.Errorf("failed to set style for MaxUsage: %w", err)
+			return nil, fmt.Errorf("failed to set style for MaxUsage: %w", err)
 		}
 
 		cellMin, _ := excelize.CoordinatesToCellName(4, row)
 		if err := file.SetCellValue(sheetName, cellMin, usage.MinUsage); err != nil {
-			return fmt.Errorf("failed to set MinUsage: %w", err)
+			return nil, fmt.Errorf("failed to set MinUsage: %w", err)
 		}
 		if err := file.SetCellStyle(sheetName, cellMin, cellMin, dataCellStyleID); err != nil {
 			log.Printf("failed to set style for MinUsage: %v", err)
 		}
 	}
+	if err := AddConditionalFormats(file, sheetName, r.ConditionalFormats(sheetName, 2, len(r.Data)+1)); err != nil {
+		return nil, err
+	}
+	if err := addSummary(file, sheetName, r.Columns(), len(r.Data)+1); err != nil {
+		return nil, err
+	}
+	if err := addTable(file, sheetName, len(headers), len(r.Data)+1); err != nil {
+		return nil, err
+	}
 
-	chartRowOffset := len(r.Data) + 4
-	chartCell := fmt.Sprintf("A%d", chartRowOffset)
+	return r.Charts(sheetName, 2, len(r.Data)+1), nil
+}
 
-	if err := file.AddChart(sheetName, chartCell, &excelize.Chart{
-		Type: excelize.Col3DClustered,
-		Title: []excelize.RichTextRun{
-			{Text: "CPU Average Usage"},
-		},
-		XAxis: excelize.ChartAxis{
-			Title: []excelize.RichTextRun{
-				{Text: "CPU"},
-			},
-		},
-		YAxis: excelize.ChartAxis{
-			Title: []excelize.RichTextRun{
-				{Text: "Average Usage (%)"},
-			},
-		},
-		Series: []excelize.ChartSeries{
-			{
-				Name:       "Average Usage (%)",
-				Values:     fmt.Sprintf("'%s'!B2:B%d", sheetName, len(r.Data)+1),
-				Categories: fmt.Sprintf("'%s'!A2:A%d", sheetName, len(r.Data)+1),
-				Line:       excelize.ChartLine{Width: 2},
-			},
-		},
-		PlotArea: excelize.ChartPlotArea{
-			ShowVal:         true,
-			ShowCatName:     false,
-			ShowSerName:     false,
-			ShowLeaderLines: false,
-		},
-		Legend: excelize.ChartLegend{
-			Position: "top",
-		},
-		Dimension: excelize.ChartDimension{
-			Width:  960,
-			Height: 560,
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to add chart: %w", err)
+func (r *CpuSystemUsageReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	return cpuUsageColumns(r.Thresholds)
+}
+
+func (r *CpuSystemUsageReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			if !yield([]any{usage.CPU, usage.AvgUsage, usage.MaxUsage, usage.MinUsage}) {
+				return
+			}
+		}
 	}
+}
 
-	return nil
+func (r *CpuSystemUsageReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" {
+		return nil
+	}
+	return []SheetChart{averageUsageChart(sheetName, firstRow, lastRow)}
+}
+
+// ConditionalFormats returns the threshold, data bar and icon set formats of the rows firstRow to lastRow.
+func (r *CpuSystemUsageReport) ConditionalFormats(sheetName string, firstRow int, lastRow int) []ConditionalFormat {
+	if r.Unavailable != "" {
+		return nil
+	}
+	return columnConditionalFormats(r.Columns(), firstRow, lastRow)
 }
@@ -0,0 +1,185 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	timelineTimeFormat  = "yyyy-mm-dd hh:mm"
+	timelineChartWidth  = 720
+	timelineChartHeight = 400
+	// timelineChartRows is the number of default-height rows a timeline chart spans, plus a gap.
+	timelineChartRows = 22
+)
+
+// CpuUsageTimelineSample holds the usage of a CPU over the bucket starting at Timestamp (unix seconds).
+type CpuUsageTimelineSample struct {
+	Timestamp int64
+	AvgUsage  float64
+	MaxUsage  float64
+	MinUsage  float64
+}
+
+// CpuUsageTimelineData holds the samples of a single CPU, ordered by time.
+type CpuUsageTimelineData struct {
+	CPU     string
+	Samples []CpuUsageTimelineSample
+}
+
+// CpuUsageTimelineReport renders the usage of every CPU per time bucket, one row per bucket and
+// avg/max/min columns per CPU, with a line chart per CPU beside the table.
+type CpuUsageTimelineReport struct {
+	// Name is the sheet name, e.g. "CPU System Timeline".
+	Name     string
+	DateFrom int64
+	DateTo   int64
+	Step     time.Duration
+	Data     []CpuUsageTimelineData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *CpuUsageTimelineReport) SheetName() string {
+	return r.Name
+}
+
+// Render creates the timeline sheet in file. It stops early when ctx is done.
+func (r *CpuUsageTimelineReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the timeline table and returns the per-CPU charts to anchor on the sheet.
+func (r *CpuUsageTimelineReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// RowCount returns the number of data rows, one per time bucket.
+func (r *CpuUsageTimelineReport) RowCount() int {
+	return len(r.timestamps())
+}
+
+// Columns returns the time column followed by the avg/max/min columns of every CPU.
+func (r *CpuUsageTimelineReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	timeFormat := timelineTimeFormat
+	columns := []StreamColumn{{Header: "Time (UTC)", Width: 18, Style: &excelize.Style{CustomNumFmt: &timeFormat}}}
+	percent := &excelize.Style{NumFmt: 10}
+	for _, cpu := range r.Data {
+		columns = append(columns,
+			StreamColumn{Header: cpu.CPU + " Avg (%)", Width: 14, Style: percent, Summarize: true},
+			StreamColumn{Header: cpu.CPU + " Max (%)", Width: 14, Style: percent, Summarize: true},
+			StreamColumn{Header: cpu.CPU + " Min (%)", Width: 14, Style: percent, Summarize: true},
+		)
+	}
+	return columns
+}
+
+// Rows yields one row per time bucket. CPUs without a sample for a bucket get empty cells.
+func (r *CpuUsageTimelineReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		next := make([]int, len(r.Data))
+		for _, timestamp := range r.timestamps() {
+			row := make([]any, 1+3*len(r.Data))
+			row[0] = time.Unix(timestamp, 0).UTC()
+			for i, cpu := range r.Data {
+				if next[i] < len(cpu.Samples) && cpu.Samples[next[i]].Timestamp == timestamp {
+					sample := cpu.Samples[next[i]]
+					row[1+3*i], row[2+3*i], row[3+3*i] = sample.AvgUsage, sample.MaxUsage, sample.MinUsage
+					next[i]++
+				}
+			}
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns a line chart of the average and maximum usage of every CPU over the rows firstRow
+// to lastRow, stacked beside the table.
+func (r *CpuUsageTimelineReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || lastRow < firstRow {
+		return nil
+	}
+	anchorCol, _ := excelize.ColumnNumberToName(len(r.Data)*3 + 3)
+	table := tableRange{sheet: sheetName, columns: 1 + 3*len(r.Data), firstRow: firstRow, lastRow: lastRow}
+	categories := table.series(1)
+	charts := make([]SheetChart, len(r.Data))
+	for i, cpu := range r.Data {
+		charts[i] = SheetChart{
+			Cell: fmt.Sprintf("%s%d", anchorCol, 1+i*timelineChartRows),
+			Chart: &excelize.Chart{
+				Type:  excelize.Line,
+				Title: []excelize.RichTextRun{{Text: cpu.CPU}},
+				XAxis: excelize.ChartAxis{
+					Title: []excelize.RichTextRun{{Text: "Time (UTC)"}},
+				},
+				YAxis: excelize.ChartAxis{
+					Title: []excelize.RichTextRun{{Text: "Usage (%)"}},
+				},
+				Series: []excelize.ChartSeries{
+					{
+						Name:       "Average Usage (%)",
+						Categories: categories,
+						Values:     table.series(2 + 3*i),
+						Line:       excelize.ChartLine{Width: 1.5},
+					},
+					{
+						Name:       "Max Usage (%)",
+						Categories: categories,
+						Values:     table.series(3 + 3*i),
+						Line:       excelize.ChartLine{Width: 1},
+					},
+				},
+				Legend: excelize.ChartLegend{
+					Position: "top",
+				},
+				Dimension: excelize.ChartDimension{
+					Width:  timelineChartWidth,
+					Height: timelineChartHeight,
+				},
+			},
+		}
+	}
+	return charts
+}
+
+// timestamps returns the sorted bucket timestamps of every CPU.
+func (r *CpuUsageTimelineReport) timestamps() []int64 {
+	var timestamps []int64
+	for _, cpu := range r.Data {
+		for _, sample := range cpu.Samples {
+			timestamps = append(timestamps, sample.Timestamp)
+		}
+	}
+	slices.Sort(timestamps)
+	return slices.Compact(timestamps)
+}
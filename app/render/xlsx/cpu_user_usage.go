@@ -1,7 +1,9 @@
 package xlsx
 
 import (
+	"context"
 	"fmt"
+	"iter"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -19,14 +21,36 @@ type CpuUserUsageReport struct {
 	DateFrom int64
 	DateTo   int64
 	Data     []CpuUserUsageData
+	// Thresholds highlight the avg_usage, max_usage and min_usage columns.
+	Thresholds Thresholds
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
 }
 
-// Render creates an XLSX report of CPU system usage using excelize.
-func (r *CpuUserUsageReport) Render(file *excelize.File) error {
-	sheetName := "CPU User Usage"
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *CpuUserUsageReport) SheetName() string {
+	return "CPU User Usage"
+}
+
+// Render creates an XLSX report of CPU user usage using excelize. It stops early when ctx is done.
+func (r *CpuUserUsageReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the usage table and returns the charts to anchor on the sheet, so that they
+// can be added after the sheet is merged into another workbook.
+func (r *CpuUserUsageReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
 	_, err := file.NewSheet(sheetName)
 	if err != nil {
-		return fmt.Errorf("failed to create sheet: %w", err)
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
 	}
 
 	boldFont := &excelize.Font{Bold: true}
@@ -39,7 +63,7 @@ func (r *CpuUserUsageReport) Render(file *excelize.File) error {
 	headers := []string{"CPU", "Average Usage (%)", "Max Usage (%)", "Min Usage (%)"}
 	headerStyle, err := file.NewStyle(&excelize.Style{Font: boldFont})
 	if err != nil {
-		return fmt.Errorf("failed to create header style: %w", err)
+		return nil, fmt.Errorf("failed to create header style: %w", err)
 	}
 	for col, title := range headers {
 		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
@@ -58,48 +82,65 @@ func (r *CpuUserUsageReport) Render(file *excelize.File) error {
 
 	// Data
 	for i, usage := range r.Data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		row := i + 2
 		_ = setStyledCell(file, sheetName, 1, row, usage.CPU, textStyle)
 		_ = setStyledCell(file, sheetName, 2, row, usage.AvgUsage, numStyle)
 		_ = setStyledCell(file, sheetName, 3, row, usage.MaxUsage, numStyle)
 		_ = setStyledCell(file, sheetName, 4, row, usage.MinUsage, numStyle)
 	}
+	if err := AddConditionalFormats(file, sheetName, r.ConditionalFormats(sheetName, 2, len(r.Data)+1)); err != nil {
+		return nil, err
+	}
+	if err := addSummary(file, sheetName, r.Columns(), len(r.Data)+1); err != nil {
+		return nil, err
+	}
+	if err := addTable(file, sheetName, len(headers), len(r.Data)+1); err != nil {
+		return nil, err
+	}
 
-	// Chart
-	chartRow := len(r.Data) + 4
-	chartCell := fmt.Sprintf("A%d", chartRow)
+	return r.Charts(sheetName, 2, len(r.Data)+1), nil
+}
 
-	err = file.AddChart(sheetName, chartCell, &excelize.Chart{
-		Type:  excelize.Col3DClustered,
-		Title: []excelize.RichTextRun{{Text: "CPU Average Usage"}},
-		XAxis: excelize.ChartAxis{
-			Title: []excelize.RichTextRun{{Text: "CPU"}},
-		},
-		YAxis: excelize.ChartAxis{
-			Title: []excelize.RichTextRun{{Text: "Average Usage (%)"}},
-		},
-		Series: []excelize.ChartSeries{{
-			Name:       "Average Usage (%)",
-			Values:     fmt.Sprintf("'%s'!B2:B%d", sheetName, len(r.Data)+1),
-			Categories: fmt.Sprintf("'%s'!A2:A%d", sheetName, len(r.Data)+1),
-			Line:       excelize.ChartLine{Width: 2},
-		}},
-		PlotArea: excelize.ChartPlotArea{
-			ShowVal: true,
-		},
-		Legend: excelize.ChartLegend{
-			Position: "top",
-		},
-		Dimension: excelize.ChartDimension{
-			Width:  960,
-			Height: 560,
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("failed to add chart: %w", err)
+// Columns returns the columns of the streamed sheet.
+func (r *CpuUserUsageReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
 	}
+	return cpuUsageColumns(r.Thresholds)
+}
+
+// Rows yields the streamed rows, one per CPU.
+func (r *CpuUserUsageReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			if !yield([]any{usage.CPU, usage.AvgUsage, usage.MaxUsage, usage.MinUsage}) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns the average usage chart of the rows firstRow to lastRow of a sheet.
+func (r *CpuUserUsageReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" {
+		return nil
+	}
+	return []SheetChart{averageUsageChart(sheetName, firstRow, lastRow)}
+}
 
-	return nil
+// ConditionalFormats returns the threshold, data bar and icon set formats of the rows firstRow to lastRow.
+func (r *CpuUserUsageReport) ConditionalFormats(sheetName string, firstRow int, lastRow int) []ConditionalFormat {
+	if r.Unavailable != "" {
+		return nil
+	}
+	return columnConditionalFormats(r.Columns(), firstRow, lastRow)
 }
 
 func setStyledCell(file *excelize.File, sheet string, col, row int, value interface{}, style int) error {
@@ -118,3 +159,62 @@ func borders() []excelize.Border {
 		{Type: "bottom", Color: "000000", Style: 1},
 	}
 }
+
+// cpuUsageColumns describes the per-CPU usage table of the streamed sheets. The average has a data
+// bar and the maximum an icon set, and every usage column is highlighted above its threshold and summarized.
+func cpuUsageColumns(thresholds Thresholds) []StreamColumn {
+	numberStyle := &excelize.Style{NumFmt: 10, Border: borders()}
+	return []StreamColumn{
+		{Header: "CPU", Width: 15, Style: &excelize.Style{Border: borders()}},
+		{Header: "Average Usage (%)", Width: 18, Style: numberStyle, Summarize: true, Highlight: &ColumnHighlight{Threshold: thresholds["avg_usage"], DataBar: true}},
+		{Header: "Max Usage (%)", Width: 18, Style: numberStyle, Summarize: true, Highlight: &ColumnHighlight{Threshold: thresholds["max_usage"], Icons: true}},
+		{Header: "Min Usage (%)", Width: 18, Style: numberStyle, Summarize: true, Highlight: &ColumnHighlight{Threshold: thresholds["min_usage"]}},
+	}
+}
+
+// CpuUsageMetrics are the fields of the CPU usage sheets that thresholds can apply to.
+var CpuUsageMetrics = []string{"avg_usage", "max_usage", "min_usage"}
+
+// averageUsageChart plots the average usage of the rows firstRow to lastRow below the table.
+func averageUsageChart(sheetName string, firstRow int, lastRow int) SheetChart {
+	table := tableRange{sheet: sheetName, columns: 4, firstRow: firstRow, lastRow: lastRow}
+	return SheetChart{
+		Cell: chartAnchor(lastRow),
+		Chart: &excelize.Chart{
+			Type:  excelize.Col3DClustered,
+			Title: []excelize.RichTextRun{{Text: "CPU Average Usage"}},
+			XAxis: excelize.ChartAxis{
+				Title: []excelize.RichTextRun{{Text: "CPU"}},
+			},
+			YAxis: excelize.ChartAxis{
+				Title: []excelize.RichTextRun{{Text: "Average Usage (%)"}},
+			},
+			Series: []excelize.ChartSeries{{
+				Name:       table.header(2),
+				Values:     table.series(2),
+				Categories: table.series(1),
+				Line:       excelize.ChartLine{Width: 2},
+			}},
+			PlotArea: excelize.ChartPlotArea{
+				ShowVal: true,
+			},
+			Legend: excelize.ChartLegend{
+				Position: "top",
+			},
+			Dimension: excelize.ChartDimension{
+				Width:  960,
+				Height: 560,
+			},
+		},
+	}
+}
+
+// chartAnchor places a chart two rows below the summary rows of the table, or beside the table when
+// the sheet is full.
+func chartAnchor(lastRow int) string {
+	row := lastRow + summaryHeight + 3
+	if row > MaxSheetRows {
+		return "F2"
+	}
+	return fmt.Sprintf("A%d", row)
+}
@@ -0,0 +1,240 @@
+package xlsx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+// DashboardSheetName is the name of the dashboard sheet.
+const DashboardSheetName = "Dashboard"
+
+// DashboardReport is the first sheet of the full workbook: the header block of the report and headline
+// KPIs. Every KPI is a formula over the data rows of the overview and CPU usage sheets, including their
+// continuation sheets when they were streamed, so the dashboard always agrees with them.
+type DashboardReport struct {
+	Header   header.Header
+	Overview *CpuOverviewReport
+	System   *CpuSystemUsageReport
+	User     *CpuUserUsageReport
+}
+
+// dashboardKPI is a row of the KPI block: formulas naming the CPU, empty for the KPIs of every CPU,
+// and computing the figure, and the sheet they refer to.
+type dashboardKPI struct {
+	label  string
+	cpu    string
+	value  string
+	source string
+}
+
+// AddDashboard adds the dashboard to a rendered workbook, as its first and active sheet, with the page
+// header and footer of the report. The KPIs of the sheets without data are left out.
+func AddDashboard(file *excelize.File, r *DashboardReport) error {
+	sheetName := DashboardSheetName
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return fmt.Errorf("failed to create sheet: %w", err)
+	}
+	titleStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 16}})
+	if err != nil {
+		return fmt.Errorf("failed to create title style: %w", err)
+	}
+	boldStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create label style: %w", err)
+	}
+	textStyle, err := file.NewStyle(&excelize.Style{Border: borders()})
+	if err != nil {
+		return fmt.Errorf("failed to create text style: %w", err)
+	}
+	percentStyle, err := file.NewStyle(&excelize.Style{NumFmt: 10, Border: borders()})
+	if err != nil {
+		return fmt.Errorf("failed to create value style: %w", err)
+	}
+	linkStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}, Border: borders()})
+	if err != nil {
+		return fmt.Errorf("failed to create link style: %w", err)
+	}
+
+	for i, width := range []float64{32, 18, 16, 20} {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		if err := file.SetColWidth(sheetName, col, col, width); err != nil {
+			return fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+	if err := setStyledCell(file, sheetName, 1, 1, "CPU Usage Dashboard", titleStyle); err != nil {
+		return fmt.Errorf("failed to set dashboard title: %w", err)
+	}
+	fields := r.Header.Fields()
+	for i, field := range fields {
+		if err := setStyledCell(file, sheetName, 1, 3+i, field.Label, boldStyle); err != nil {
+			return fmt.Errorf("failed to set dashboard header: %w", err)
+		}
+		if err := file.SetCellValue(sheetName, fmt.Sprintf("B%d", 3+i), field.Value); err != nil {
+			return fmt.Errorf("failed to set dashboard header: %w", err)
+		}
+	}
+	if err := file.SetHeaderFooter(sheetName, PageHeaderFooter(r.Header)); err != nil {
+		return fmt.Errorf("failed to set header and footer: %w", err)
+	}
+
+	// The KPI block starts below the header block, after a blank row.
+	kpiRow := 3 + len(fields) + 1
+	kpis := r.kpis(file)
+	if len(kpis) == 0 {
+		if err := setStyledCell(file, sheetName, 1, kpiRow, "No data available for the period.", boldStyle); err != nil {
+			return fmt.Errorf("failed to set dashboard placeholder: %w", err)
+		}
+		return moveToFront(file, sheetName)
+	}
+	for col, title := range []string{"KPI", "CPU", "Usage (%)", "Source"} {
+		if err := setStyledCell(file, sheetName, col+1, kpiRow, title, boldStyle); err != nil {
+			return fmt.Errorf("failed to set KPI header: %w", err)
+		}
+	}
+	for i, kpi := range kpis {
+		row := kpiRow + 1 + i
+		if err := setStyledCell(file, sheetName, 1, row, kpi.label, textStyle); err != nil {
+			return fmt.Errorf("failed to set KPI label: %w", err)
+		}
+		if kpi.cpu == "" {
+			if err := setStyledCell(file, sheetName, 2, row, "All", textStyle); err != nil {
+				return fmt.Errorf("failed to set KPI CPU: %w", err)
+			}
+		} else if err := setStyledFormula(file, sheetName, fmt.Sprintf("B%d", row), kpi.cpu, textStyle); err != nil {
+			return err
+		}
+		if err := setStyledFormula(file, sheetName, fmt.Sprintf("C%d", row), kpi.value, percentStyle); err != nil {
+			return err
+		}
+		if err := setStyledCell(file, sheetName, 4, row, kpi.source, linkStyle); err != nil {
+			return fmt.Errorf("failed to set KPI source: %w", err)
+		}
+		if err := file.SetCellHyperLink(sheetName, fmt.Sprintf("D%d", row), quoteSheetName(kpi.source)+"!A1", "Location"); err != nil {
+			return fmt.Errorf("failed to link source sheet: %w", err)
+		}
+	}
+	return moveToFront(file, sheetName)
+}
+
+// kpis returns the KPIs of the sheets that have data rows.
+func (r *DashboardReport) kpis(file *excelize.File) []dashboardKPI {
+	var kpis []dashboardKPI
+	if r.Overview != nil && r.Overview.Unavailable == "" {
+		if overview := sheetParts(file, r.Overview.SheetName()); len(overview) > 0 {
+			totalAvg := overview.columns(8)
+			kpis = append(kpis,
+				dashboardKPI{"Busiest CPU (total average)", overview.busiestCPU(8), fmt.Sprintf("MAX(%s)", totalAvg), r.Overview.SheetName()},
+				dashboardKPI{"Fleet average (total)", "", fmt.Sprintf("AVERAGE(%s)", totalAvg), r.Overview.SheetName()},
+			)
+		}
+	}
+	for _, usage := range []struct {
+		kind        string
+		sheet       string
+		unavailable string
+	}{
+		{"system", r.System.SheetName(), r.System.Unavailable},
+		{"user", r.User.SheetName(), r.User.Unavailable},
+	} {
+		if usage.unavailable != "" {
+			continue
+		}
+		parts := sheetParts(file, usage.sheet)
+		if len(parts) == 0 {
+			continue
+		}
+		kpis = append(kpis,
+			dashboardKPI{"Fleet average (" + usage.kind + ")", "", fmt.Sprintf("AVERAGE(%s)", parts.columns(2)), usage.sheet},
+			dashboardKPI{"Peak " + usage.kind + " usage", parts.busiestCPU(3), fmt.Sprintf("MAX(%s)", parts.columns(3)), usage.sheet},
+		)
+	}
+	return kpis
+}
+
+// tableParts are the tables of a sheet and of its continuation sheets, in order.
+type tableParts []tableRange
+
+// sheetParts returns the tables of sheetName and of its continuation sheets, read from the names of
+// their columns. Sheets without data rows have no table and are left out.
+func sheetParts(file *excelize.File, sheetName string) tableParts {
+	var parts tableParts
+	for _, part := range continuationSheets(file, sheetName) {
+		for _, definedName := range file.GetDefinedName() {
+			if definedName.Scope != part || definedName.Name != (tableRange{sheet: part}).columnName(1) {
+				continue
+			}
+			if table, ok := parseTableColumn(part, definedName.RefersTo); ok {
+				parts = append(parts, table)
+			}
+		}
+	}
+	return parts
+}
+
+// columns returns the data cells of column col of every part, separated by commas, e.g.
+// "'Overview'!$H$2:$H$9,'Overview (2)'!$H$2:$H$5".
+func (parts tableParts) columns(col int) string {
+	refs := make([]string, len(parts))
+	for i, table := range parts {
+		refs[i] = table.column(col)
+	}
+	return strings.Join(refs, ",")
+}
+
+// busiestCPU returns the formula naming the CPU, in the first column, with the highest value in column
+// col of any part: the CPU of the first part holding that value.
+func (parts tableParts) busiestCPU(col int) string {
+	peak := fmt.Sprintf("MAX(%s)", parts.columns(col))
+	formula := ""
+	for i := len(parts) - 1; i >= 0; i-- {
+		lookup := fmt.Sprintf("INDEX(%s,MATCH(%s,%s,0))", parts[i].column(1), peak, parts[i].column(col))
+		if formula == "" {
+			formula = lookup
+		} else {
+			formula = fmt.Sprintf("IFERROR(%s,%s)", lookup, formula)
+		}
+	}
+	return formula
+}
+
+// moveToFront makes sheetName the first and active sheet of file. MoveSheet leaves the names local to
+// a sheet on the position the sheet had, so they are defined again on their sheets after the move.
+func moveToFront(file *excelize.File, sheetName string) error {
+	if first := file.GetSheetName(0); first != sheetName {
+		var local []excelize.DefinedName
+		for _, definedName := range file.GetDefinedName() {
+			if definedName.Scope == "Workbook" {
+				continue
+			}
+			if err := file.DeleteDefinedName(&definedName); err != nil {
+				return fmt.Errorf("failed to move name %s: %w", definedName.Name, err)
+			}
+			local = append(local, definedName)
+		}
+		if err := file.MoveSheet(sheetName, first); err != nil {
+			return fmt.Errorf("failed to move sheet %q: %w", sheetName, err)
+		}
+		for _, definedName := range local {
+			if err := file.SetDefinedName(&definedName); err != nil {
+				return fmt.Errorf("failed to move name %s: %w", definedName.Name, err)
+			}
+		}
+	}
+	file.SetActiveSheet(0)
+	return nil
+}
+
+// setStyledFormula sets the formula and the style of a cell.
+func setStyledFormula(file *excelize.File, sheetName string, cell string, formula string, style int) error {
+	if err := file.SetCellFormula(sheetName, cell, formula); err != nil {
+		return fmt.Errorf("failed to set formula of cell %s: %w", cell, err)
+	}
+	if err := file.SetCellStyle(sheetName, cell, cell, style); err != nil {
+		return fmt.Errorf("failed to set style of cell %s: %w", cell, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package xlsx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+// dashboardCells returns the CPU and value cells of every KPI of the dashboard, by label.
+func dashboardCells(t *testing.T, file *excelize.File) map[string][2]string {
+	t.Helper()
+	rows, err := file.GetRows(DashboardSheetName)
+	if err != nil {
+		t.Fatalf("GetRows() = %v", err)
+	}
+	kpis := make(map[string][2]string)
+	for i, row := range rows {
+		if len(row) == 0 || !strings.Contains(row[0], "average") && !strings.HasPrefix(row[0], "Peak") {
+			continue
+		}
+		cpu, _ := excelize.CoordinatesToCellName(2, i+1)
+		value, _ := excelize.CoordinatesToCellName(3, i+1)
+		kpis[row[0]] = [2]string{cpu, value}
+	}
+	return kpis
+}
+
+func TestDashboardCoversTheContinuationSheets(t *testing.T) {
+	overview, system, user := cpuReports(10)
+	// 4 data rows per sheet: the CPUs continue on two more sheets.
+	file, err := StreamWorkbook[StreamSheet](context.Background(), StreamOptions{Threshold: 1, RowLimit: 10}, overview, system, user)
+	if err != nil {
+		t.Fatalf("StreamWorkbook() = %v", err)
+	}
+	if err := AddDashboard(file, &DashboardReport{Header: header.Header{Title: "CPU"}, Overview: overview, System: system, User: user}); err != nil {
+		t.Fatalf("AddDashboard() = %v", err)
+	}
+	defer file.Close()
+
+	kpis := dashboardCells(t, file)
+	if len(kpis) != 6 {
+		t.Fatalf("dashboard KPIs = %v, want 6", kpis)
+	}
+	formula, _ := file.GetCellFormula(DashboardSheetName, kpis["Fleet average (system)"][1])
+	want := "AVERAGE('CPU System Usage'!$B$2:$B$5,'CPU System Usage (2)'!$B$2:$B$5,'CPU System Usage (3)'!$B$2:$B$3)"
+	if formula != want {
+		t.Errorf("fleet average = %q, want %q", formula, want)
+	}
+
+	// The busiest CPUs are the last ones, on the last continuation sheet.
+	for label, want := range map[string][2]string{
+		"Busiest CPU (total average)": {"cpu9", "1"},
+		"Fleet average (total)":       {"", "0.55"},
+		"Fleet average (user)":        {"", "0.275"},
+		"Peak system usage":           {"cpu9", "1"},
+		"Peak user usage":             {"cpu9", "1"},
+	} {
+		if want[0] != "" {
+			if cpu, err := file.CalcCellValue(DashboardSheetName, kpis[label][0], excelize.Options{RawCellValue: true}); err != nil || cpu != want[0] {
+				t.Errorf("%s CPU = %q, %v, want %q", label, cpu, err, want[0])
+			}
+		}
+		if value, err := file.CalcCellValue(DashboardSheetName, kpis[label][1], excelize.Options{RawCellValue: true}); err != nil || value != want[1] {
+			t.Errorf("%s = %q, %v, want %q", label, value, err, want[1])
+		}
+	}
+}
+
+func TestDashboardOfASingleSheet(t *testing.T) {
+	overview, system, user := cpuReports(3)
+	file, err := RenderWorkbook[Report](context.Background(), overview, system, user)
+	if err != nil {
+		t.Fatalf("RenderWorkbook() = %v", err)
+	}
+	if err := AddDashboard(file, &DashboardReport{Header: header.Header{Title: "CPU"}, Overview: overview, System: system, User: user}); err != nil {
+		t.Fatalf("AddDashboard() = %v", err)
+	}
+	defer file.Close()
+
+	cells := dashboardCells(t, file)["Peak user usage"]
+	cpu, _ := file.GetCellFormula(DashboardSheetName, cells[0])
+	if want := "INDEX('CPU User Usage'!$A$2:$A$4,MATCH(MAX('CPU User Usage'!$C$2:$C$4),'CPU User Usage'!$C$2:$C$4,0))"; cpu != want {
+		t.Errorf("peak user CPU = %q, want %q", cpu, want)
+	}
+}
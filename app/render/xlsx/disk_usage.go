@@ -0,0 +1,94 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DiskUsageData holds the average I/O of one block device over the period.
+type DiskUsageData struct {
+	Device              string
+	ReadBytesPerSecond  float64
+	WriteBytesPerSecond float64
+	ReadIOPS            float64
+	WriteIOPS           float64
+}
+
+// DiskUsageReport holds the disk I/O report data, throughput rendered in MiB/s.
+type DiskUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []DiskUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *DiskUsageReport) SheetName() string {
+	return "Disk IO"
+}
+
+// Render creates the disk I/O sheet in file. It stops early when ctx is done.
+func (r *DiskUsageReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the I/O table and returns the charts to anchor on the sheet.
+func (r *DiskUsageReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// Columns returns the columns of the sheet, one row per device.
+func (r *DiskUsageReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	return []StreamColumn{
+		labelColumn("Device"),
+		decimalColumn("Read (MiB/s)"),
+		decimalColumn("Write (MiB/s)"),
+		decimalColumn("Read IOPS"),
+		decimalColumn("Write IOPS"),
+	}
+}
+
+// Rows yields one row per device, throughput converted to MiB/s.
+func (r *DiskUsageReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			if !yield([]any{usage.Device, usage.ReadBytesPerSecond / bytesPerMiB, usage.WriteBytesPerSecond / bytesPerMiB, usage.ReadIOPS, usage.WriteIOPS}) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns a column chart of the read and write throughput of every device.
+func (r *DiskUsageReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || lastRow < firstRow {
+		return nil
+	}
+	return []SheetChart{columnChart(sheetName, "Disk Throughput", "MiB/s", firstRow, lastRow, r.Columns(), 2, 3)}
+}
@@ -0,0 +1,93 @@
+package xlsx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+// HeaderSheetName is the name of the sheet listing the header block of a report, see HeaderSheet.
+const HeaderSheetName = "Report"
+
+// maxHeaderFooterText is the length the texts of the page header and footer are cut to, so that
+// each stays within the 255 characters Excel allows even with every ampersand escaped.
+const maxHeaderFooterText = 80
+
+// HeaderSheet returns the sheet listing the title and the header block of a report, one field per row.
+func HeaderSheet(h header.Header) *TableSheet {
+	sheet := &TableSheet{
+		Name:   HeaderSheetName,
+		Fields: []StreamColumn{{Header: "Field", Width: 18}, {Header: "Value", Width: 60}},
+		Data:   [][]any{{"Title", h.Title}},
+	}
+	for _, field := range h.Fields() {
+		sheet.Data = append(sheet.Data, []any{field.Label, field.Value})
+	}
+	return sheet
+}
+
+// PageHeaderFooter returns the printed page header and footer of the sheets of a report: its title
+// and period at the top, its generation time, request ID and page number at the bottom.
+func PageHeaderFooter(h header.Header) *excelize.HeaderFooterOptions {
+	footer := "Generated " + escapeHeaderFooter(h.Generated())
+	if h.RequestID != "" {
+		footer += " - Request " + escapeHeaderFooter(h.RequestID)
+	}
+	return &excelize.HeaderFooterOptions{
+		OddHeader: fmt.Sprintf("&L&B%s&R%s", escapeHeaderFooter(h.Title), escapeHeaderFooter(h.Period())),
+		OddFooter: fmt.Sprintf("&L%s&RPage &P of &N", footer),
+	}
+}
+
+// ApplyHeader fills the document properties of a workbook rendered in memory from the header block
+// of its report and sets the page header and footer of every sheet.
+func ApplyHeader(file *excelize.File, h header.Header) error {
+	if err := SetDocumentProperties(file, h); err != nil {
+		return err
+	}
+	options := PageHeaderFooter(h)
+	for _, sheetName := range file.GetSheetList() {
+		if err := file.SetHeaderFooter(sheetName, options); err != nil {
+			return fmt.Errorf("failed to set header and footer of sheet %q: %w", sheetName, err)
+		}
+	}
+	return nil
+}
+
+// SetDocumentProperties fills the document properties of a workbook from the header block of its
+// report. Unlike ApplyHeader, it leaves the sheets alone, so that it can complete a streamed workbook:
+// the StreamWriter serializes its sheets when flushed, and they get their page header and footer from
+// StreamOptions.HeaderFooter instead.
+func SetDocumentProperties(file *excelize.File, h header.Header) error {
+	var keywords []string
+	for _, field := range h.Fields() {
+		keywords = append(keywords, field.Label+": "+field.Value)
+	}
+	generated := h.GeneratedAt.UTC().Format(time.RFC3339)
+	if err := file.SetDocProps(&excelize.DocProperties{
+		Title:       h.Title,
+		Subject:     h.Period(),
+		Creator:     "reports-rendering-go",
+		Created:     generated,
+		Modified:    generated,
+		Identifier:  h.RequestID,
+		Description: h.Source(),
+		Keywords:    strings.Join(keywords, "; "),
+	}); err != nil {
+		return fmt.Errorf("failed to set document properties: %w", err)
+	}
+	return nil
+}
+
+// escapeHeaderFooter cuts a text to maxHeaderFooterText and doubles its ampersands, which Excel
+// would read as formatting codes.
+func escapeHeaderFooter(text string) string {
+	if runes := []rune(text); len(runes) > maxHeaderFooterText {
+		text = string(runes[:maxHeaderFooterText])
+	}
+	return strings.ReplaceAll(text, "&", "&&")
+}
@@ -0,0 +1,58 @@
+package xlsx
+
+import "github.com/xuri/excelize/v2"
+
+const (
+	bytesPerGiB = 1 << 30
+	bytesPerMiB = 1 << 20
+)
+
+// decimalColumn describes a bordered, summarized numeric column shown with two decimals.
+func decimalColumn(header string) StreamColumn {
+	return StreamColumn{Header: header, Width: 18, Style: &excelize.Style{NumFmt: 2, Border: borders()}, Summarize: true}
+}
+
+// integerColumn describes a bordered, summarized numeric column shown with thousands separators.
+func integerColumn(header string) StreamColumn {
+	return StreamColumn{Header: header, Width: 18, Style: &excelize.Style{NumFmt: 3, Border: borders()}, Summarize: true}
+}
+
+// labelColumn describes the bordered text column that names each row.
+func labelColumn(header string) StreamColumn {
+	return StreamColumn{Header: header, Width: 15, Style: &excelize.Style{Border: borders()}}
+}
+
+// columnChart plots the given value columns of the table rows firstRow to lastRow below the table,
+// one series per named column titled by its header and the first column as categories.
+func columnChart(sheetName string, title string, yAxis string, firstRow int, lastRow int, columns []StreamColumn, valueColumns ...int) SheetChart {
+	table := tableRange{sheet: sheetName, columns: len(columns), firstRow: firstRow, lastRow: lastRow}
+	series := make([]excelize.ChartSeries, len(valueColumns))
+	for i, column := range valueColumns {
+		series[i] = excelize.ChartSeries{
+			Name:       table.header(column),
+			Categories: table.series(1),
+			Values:     table.series(column),
+		}
+	}
+	return SheetChart{
+		Cell: chartAnchor(lastRow),
+		Chart: &excelize.Chart{
+			Type:  excelize.Col,
+			Title: []excelize.RichTextRun{{Text: title}},
+			XAxis: excelize.ChartAxis{
+				Title: []excelize.RichTextRun{{Text: columns[0].Header}},
+			},
+			YAxis: excelize.ChartAxis{
+				Title: []excelize.RichTextRun{{Text: yAxis}},
+			},
+			Series: series,
+			Legend: excelize.ChartLegend{
+				Position: "top",
+			},
+			Dimension: excelize.ChartDimension{
+				Width:  960,
+				Height: 480,
+			},
+		},
+	}
+}
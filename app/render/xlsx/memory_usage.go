@@ -0,0 +1,92 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MemoryUsageData holds one memory metric (used, cached or swap) over the period, in bytes.
+type MemoryUsageData struct {
+	Metric   string
+	AvgBytes float64
+	MaxBytes float64
+	MinBytes float64
+}
+
+// MemoryUsageReport holds the memory usage report data, rendered in GiB.
+type MemoryUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []MemoryUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *MemoryUsageReport) SheetName() string {
+	return "Memory Usage"
+}
+
+// Render creates the memory usage sheet in file. It stops early when ctx is done.
+func (r *MemoryUsageReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the usage table and returns the charts to anchor on the sheet.
+func (r *MemoryUsageReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// Columns returns the columns of the sheet, one row per metric.
+func (r *MemoryUsageReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	return []StreamColumn{
+		labelColumn("Metric"),
+		decimalColumn("Average (GiB)"),
+		decimalColumn("Max (GiB)"),
+		decimalColumn("Min (GiB)"),
+	}
+}
+
+// Rows yields one row per metric, converted to GiB.
+func (r *MemoryUsageReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			if !yield([]any{usage.Metric, usage.AvgBytes / bytesPerGiB, usage.MaxBytes / bytesPerGiB, usage.MinBytes / bytesPerGiB}) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns a column chart of the average and maximum of every metric.
+func (r *MemoryUsageReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || lastRow < firstRow {
+		return nil
+	}
+	return []SheetChart{columnChart(sheetName, "Memory Usage", "GiB", firstRow, lastRow, r.Columns(), 2, 3)}
+}
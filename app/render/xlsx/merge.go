@@ -0,0 +1,226 @@
+package xlsx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MergeSheet copies a sheet of src into dst under the same name, keeping cell values, formulas,
+// styles, column widths and row heights of the used range, merged cells, conditional formats, tables,
+// frozen panes, the names local to the sheet and the workbook-level names that refer to it. Tables and
+// workbook-level names already used in dst get a numeric suffix. Charts cannot be read back from a workbook and must be added to dst
+// separately, see AddCharts.
+func MergeSheet(dst *excelize.File, src *excelize.File, sheetName string) error {
+	if _, err := dst.NewSheet(sheetName); err != nil {
+		return fmt.Errorf("failed to create sheet %q: %w", sheetName, err)
+	}
+
+	rows, err := src.GetRows(sheetName, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return fmt.Errorf("failed to read rows of %q: %w", sheetName, err)
+	}
+	maxCol := 0
+	for _, row := range rows {
+		maxCol = max(maxCol, len(row))
+	}
+
+	styles := make(map[int]int)
+	for rowIdx, row := range rows {
+		for colIdx := 0; colIdx < maxCol; colIdx++ {
+			cell, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+1)
+			value := ""
+			if colIdx < len(row) {
+				value = row[colIdx]
+			}
+			if err := copyCell(dst, src, sheetName, cell, value); err != nil {
+				return err
+			}
+			if err := copyCellStyle(dst, src, sheetName, cell, styles); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := copyDimensions(dst, src, sheetName, len(rows), maxCol); err != nil {
+		return err
+	}
+
+	mergeCells, err := src.GetMergeCells(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to read merged cells of %q: %w", sheetName, err)
+	}
+	for _, mergeCell := range mergeCells {
+		if err := dst.MergeCell(sheetName, mergeCell.GetStartAxis(), mergeCell.GetEndAxis()); err != nil {
+			return fmt.Errorf("failed to merge cells %s: %w", mergeCell[0], err)
+		}
+	}
+	if err := copyConditionalFormats(dst, src, sheetName); err != nil {
+		return err
+	}
+	return copyTables(dst, src, sheetName)
+}
+
+// copyTables recreates the tables, the panes and the workbook-level names of a sheet in dst.
+func copyTables(dst *excelize.File, src *excelize.File, sheetName string) error {
+	tables, err := src.GetTables(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to read tables of %q: %w", sheetName, err)
+	}
+	for _, table := range tables {
+		if err := addUniquelyNamed(table.Name, excelize.ErrExistsTableName, func(name string) error {
+			table.Name = name
+			return dst.AddTable(sheetName, &table)
+		}); err != nil {
+			return fmt.Errorf("failed to add table %s: %w", table.Range, err)
+		}
+	}
+
+	panes, err := src.GetPanes(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to read panes of %q: %w", sheetName, err)
+	}
+	if panes.Freeze || panes.Split {
+		if err := dst.SetPanes(sheetName, &panes); err != nil {
+			return fmt.Errorf("failed to set panes of %q: %w", sheetName, err)
+		}
+	}
+
+	prefix := quoteSheetName(sheetName) + "!"
+	for _, definedName := range src.GetDefinedName() {
+		if definedName.Scope == sheetName {
+			if err := dst.SetDefinedName(&definedName); err != nil {
+				return fmt.Errorf("failed to define name %s: %w", definedName.Name, err)
+			}
+			continue
+		}
+		if definedName.Scope != "Workbook" || !strings.HasPrefix(definedName.RefersTo, prefix) {
+			continue
+		}
+		// GetDefinedName reports the workbook scope by name, SetDefinedName expects it empty.
+		definedName.Scope = ""
+		if err := addUniquelyNamed(definedName.Name, excelize.ErrDefinedNameDuplicate, func(name string) error {
+			definedName.Name = name
+			return dst.SetDefinedName(&definedName)
+		}); err != nil {
+			return fmt.Errorf("failed to define name %s: %w", definedName.Name, err)
+		}
+	}
+	return nil
+}
+
+// copyConditionalFormats recreates the conditional formats of a sheet in dst, with their styles.
+func copyConditionalFormats(dst *excelize.File, src *excelize.File, sheetName string) error {
+	formats, err := src.GetConditionalFormats(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to read conditional formats of %q: %w", sheetName, err)
+	}
+	for rangeRef, rules := range formats {
+		for i, rule := range rules {
+			if rule.Format == nil {
+				continue
+			}
+			style, err := src.GetConditionalStyle(*rule.Format)
+			if err != nil {
+				return fmt.Errorf("failed to read conditional style %d: %w", *rule.Format, err)
+			}
+			dstStyle, err := dst.NewConditionalStyle(style)
+			if err != nil {
+				return fmt.Errorf("failed to create conditional style: %w", err)
+			}
+			rules[i].Format = &dstStyle
+		}
+		if err := dst.SetConditionalFormat(sheetName, rangeRef, rules); err != nil {
+			return fmt.Errorf("failed to set conditional format of %s: %w", rangeRef, err)
+		}
+	}
+	return nil
+}
+
+// copyCell copies a formula or a value, keeping numbers and booleans typed.
+func copyCell(dst *excelize.File, src *excelize.File, sheetName string, cell string, value string) error {
+	formula, err := src.GetCellFormula(sheetName, cell)
+	if err != nil {
+		return fmt.Errorf("failed to read formula of %s: %w", cell, err)
+	}
+	if formula != "" {
+		if err := dst.SetCellFormula(sheetName, cell, formula); err != nil {
+			return fmt.Errorf("failed to set formula of %s: %w", cell, err)
+		}
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+
+	cellType, err := src.GetCellType(sheetName, cell)
+	if err != nil {
+		return fmt.Errorf("failed to read type of %s: %w", cell, err)
+	}
+	var typed any = value
+	switch cellType {
+	case excelize.CellTypeNumber, excelize.CellTypeUnset, excelize.CellTypeDate:
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			typed = number
+		}
+	case excelize.CellTypeBool:
+		typed = value == "1" || value == "TRUE"
+	}
+	if err := dst.SetCellValue(sheetName, cell, typed); err != nil {
+		return fmt.Errorf("failed to set value of %s: %w", cell, err)
+	}
+	return nil
+}
+
+// copyCellStyle recreates the style of a cell in dst, reusing styles already copied.
+func copyCellStyle(dst *excelize.File, src *excelize.File, sheetName string, cell string, styles map[int]int) error {
+	srcStyle, err := src.GetCellStyle(sheetName, cell)
+	if err != nil {
+		return fmt.Errorf("failed to read style of %s: %w", cell, err)
+	}
+	if srcStyle == 0 {
+		return nil
+	}
+	dstStyle, ok := styles[srcStyle]
+	if !ok {
+		style, err := src.GetStyle(srcStyle)
+		if err != nil {
+			return fmt.Errorf("failed to read style %d: %w", srcStyle, err)
+		}
+		if dstStyle, err = dst.NewStyle(style); err != nil {
+			return fmt.Errorf("failed to create style: %w", err)
+		}
+		styles[srcStyle] = dstStyle
+	}
+	return dst.SetCellStyle(sheetName, cell, cell, dstStyle)
+}
+
+// copyDimensions copies the column widths and row heights that differ from the defaults.
+func copyDimensions(dst *excelize.File, src *excelize.File, sheetName string, rowCount int, colCount int) error {
+	for colIdx := 1; colIdx <= colCount; colIdx++ {
+		col, _ := excelize.ColumnNumberToName(colIdx)
+		width, err := src.GetColWidth(sheetName, col)
+		if err != nil {
+			return fmt.Errorf("failed to read width of column %s: %w", col, err)
+		}
+		if current, _ := dst.GetColWidth(sheetName, col); width != current {
+			if err := dst.SetColWidth(sheetName, col, col, width); err != nil {
+				return fmt.Errorf("failed to set width of column %s: %w", col, err)
+			}
+		}
+	}
+	for row := 1; row <= rowCount; row++ {
+		height, err := src.GetRowHeight(sheetName, row)
+		if err != nil {
+			return fmt.Errorf("failed to read height of row %d: %w", row, err)
+		}
+		if current, _ := dst.GetRowHeight(sheetName, row); height != current {
+			if err := dst.SetRowHeight(sheetName, row, height); err != nil {
+				return fmt.Errorf("failed to set height of row %d: %w", row, err)
+			}
+		}
+	}
+	return nil
+}
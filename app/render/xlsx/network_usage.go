@@ -0,0 +1,94 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// NetworkUsageData holds the traffic and errors of one network interface over the period.
+type NetworkUsageData struct {
+	Interface string
+	RxBytes   int64
+	TxBytes   int64
+	RxErrors  int64
+	TxErrors  int64
+}
+
+// NetworkUsageReport holds the network usage report data, traffic rendered in GiB.
+type NetworkUsageReport struct {
+	DateFrom int64
+	DateTo   int64
+	Data     []NetworkUsageData
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *NetworkUsageReport) SheetName() string {
+	return "Network Usage"
+}
+
+// Render creates the network usage sheet in file. It stops early when ctx is done.
+func (r *NetworkUsageReport) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the traffic table and returns the charts to anchor on the sheet.
+func (r *NetworkUsageReport) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// Columns returns the columns of the sheet, one row per interface.
+func (r *NetworkUsageReport) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	return []StreamColumn{
+		labelColumn("Interface"),
+		decimalColumn("Received (GiB)"),
+		decimalColumn("Sent (GiB)"),
+		integerColumn("Receive Errors"),
+		integerColumn("Send Errors"),
+	}
+}
+
+// Rows yields one row per interface, traffic converted to GiB.
+func (r *NetworkUsageReport) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, usage := range r.Data {
+			if !yield([]any{usage.Interface, float64(usage.RxBytes) / bytesPerGiB, float64(usage.TxBytes) / bytesPerGiB, usage.RxErrors, usage.TxErrors}) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns a column chart of the received and sent traffic of every interface.
+func (r *NetworkUsageReport) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || lastRow < firstRow {
+		return nil
+	}
+	return []SheetChart{columnChart(sheetName, "Network Traffic", "GiB", firstRow, lastRow, r.Columns(), 2, 3)}
+}
@@ -0,0 +1,44 @@
+package xlsx
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// UnavailableHeader is the header of the placeholder column of a sheet whose data could not be fetched.
+const UnavailableHeader = "Data unavailable"
+
+// renderUnavailable fills a sheet whose data could not be fetched with a visible placeholder.
+func renderUnavailable(file *excelize.File, sheetName string, reason string) error {
+	style, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "9C0006", Size: 14},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"FFC7CE"}, Pattern: 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create placeholder style: %w", err)
+	}
+	if err := file.SetColWidth(sheetName, "A", "A", 100); err != nil {
+		return fmt.Errorf("failed to set column width: %w", err)
+	}
+	if err := file.SetCellValue(sheetName, "A1", UnavailableHeader); err != nil {
+		return fmt.Errorf("failed to set placeholder: %w", err)
+	}
+	if err := file.SetCellStyle(sheetName, "A1", "A1", style); err != nil {
+		return fmt.Errorf("failed to set placeholder style: %w", err)
+	}
+	if err := file.SetCellValue(sheetName, "A2", reason); err != nil {
+		return fmt.Errorf("failed to set placeholder reason: %w", err)
+	}
+	return nil
+}
+
+// isUnavailable reports whether columns are those of a placeholder.
+func isUnavailable(columns []StreamColumn) bool {
+	return len(columns) == 1 && columns[0].Header == UnavailableHeader
+}
+
+// unavailableColumns describes the placeholder of a streamed sheet whose data could not be fetched.
+func unavailableColumns() []StreamColumn {
+	return []StreamColumn{{Header: UnavailableHeader, Width: 100}}
+}
@@ -0,0 +1,263 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxSheetRows is the largest row limit a worksheet supports, the header row included.
+const MaxSheetRows = excelize.TotalRows
+
+// StreamOptions configures the streaming rendering path used for large datasets.
+type StreamOptions struct {
+	// Threshold is the number of data rows above which a report is streamed. Zero disables streaming.
+	Threshold int
+	// RowLimit is the number of rows per sheet, header included, before rolling over to a
+	// continuation sheet. Zero or values above MaxSheetRows mean MaxSheetRows.
+	RowLimit int
+	// HeaderFooter, when set, is the printed page header and footer of every streamed sheet.
+	HeaderFooter *excelize.HeaderFooterOptions
+}
+
+// Enabled reports whether a report with rowCount data rows should be streamed.
+func (opts StreamOptions) Enabled(rowCount int) bool {
+	return opts.Threshold > 0 && rowCount > opts.Threshold
+}
+
+func (opts StreamOptions) rowLimit() int {
+	if opts.RowLimit <= 1 || opts.RowLimit > MaxSheetRows {
+		return MaxSheetRows
+	}
+	return opts.RowLimit
+}
+
+// StreamColumn describes a column of a streamed sheet. Style applies to its data cells, and
+// Highlight, when set, adds conditional formats to them. Summarize adds the column to the summary
+// rows below the table; the first column holds their labels and is never summarized.
+type StreamColumn struct {
+	Header    string
+	Width     float64
+	Style     *excelize.Style
+	Highlight *ColumnHighlight
+	Summarize bool
+}
+
+// StreamSheet is a report section written row by row with excelize's StreamWriter, so that its
+// rows never have to be held as cells of an in-memory workbook.
+type StreamSheet interface {
+	SheetName() string
+	Columns() []StreamColumn
+	Rows() iter.Seq[[]any]
+	// Charts returns the charts of a sheet holding the data rows firstRow to lastRow.
+	Charts(sheetName string, firstRow int, lastRow int) []SheetChart
+}
+
+// StreamWorkbook writes the sheets into a new workbook with the StreamWriter. A sheet with more rows
+// than the row limit continues on "<name> (2)", "<name> (3)" and so on, each with its own header,
+// summary rows and charts. The caller writes the returned workbook, typically straight to the response, and closes it.
+func StreamWorkbook[S StreamSheet](ctx context.Context, opts StreamOptions, sheets ...S) (*excelize.File, error) {
+	file := excelize.NewFile()
+	defaultSheet := file.GetSheetName(0)
+	for _, sheet := range sheets {
+		if err := streamSheet(ctx, file, opts.rowLimit(), opts.HeaderFooter, sheet); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to render sheet %q: %w", sheet.SheetName(), err)
+		}
+	}
+	if len(sheets) > 0 {
+		if err := file.DeleteSheet(defaultSheet); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to delete default sheet: %w", err)
+		}
+		file.SetActiveSheet(0)
+	}
+	if err := recalculateOnLoad(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// sheetWriter is the stream writer of the sheet currently receiving rows.
+type sheetWriter struct {
+	name   string
+	writer *excelize.StreamWriter
+	row    int
+}
+
+func streamSheet(ctx context.Context, file *excelize.File, rowLimit int, headerFooter *excelize.HeaderFooterOptions, sheet StreamSheet) error {
+	columns := sheet.Columns()
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create header style: %w", err)
+	}
+	styles := make([]int, len(columns))
+	for i, column := range columns {
+		if column.Style == nil {
+			continue
+		}
+		if styles[i], err = file.NewStyle(column.Style); err != nil {
+			return fmt.Errorf("failed to create column style: %w", err)
+		}
+	}
+
+	var current *sheetWriter
+	var names []string
+	conditional, _ := sheet.(ConditionalSheet)
+	tabular := len(columns) > 0 && !isUnavailable(columns)
+	// Each sheet keeps room for its summary rows below the data rows.
+	summarized := hasSummary(columns)
+	dataRowLimit := rowLimit
+	if summarized {
+		dataRowLimit = max(rowLimit-summaryHeight, 2)
+	}
+	// Summary rows, tables, charts, conditional formats and the page header are added before flushing: the stream
+	// writer serializes the rest of the worksheet when it flushes, and later changes would be lost.
+	finish := func() error {
+		if current == nil {
+			return nil
+		}
+		if summarized && current.row > 1 {
+			if err := writeStreamSummary(current, columns, headerStyle, styles); err != nil {
+				return err
+			}
+		}
+		if tabular && current.row > 1 {
+			if err := addStreamTable(file, current, len(columns)); err != nil {
+				return err
+			}
+		}
+		if current.row > 1 {
+			if err := AddCharts(file, current.name, sheet.Charts(current.name, 2, current.row)); err != nil {
+				return err
+			}
+			if conditional != nil {
+				if err := AddConditionalFormats(file, current.name, conditional.ConditionalFormats(current.name, 2, current.row)); err != nil {
+					return err
+				}
+			}
+		}
+		if headerFooter != nil {
+			if err := file.SetHeaderFooter(current.name, headerFooter); err != nil {
+				return fmt.Errorf("failed to set header and footer: %w", err)
+			}
+		}
+		if err := current.writer.Flush(); err != nil {
+			return fmt.Errorf("failed to flush sheet %q: %w", current.name, err)
+		}
+		names = append(names, current.name)
+		return nil
+	}
+	start := func() error {
+		name := sheet.SheetName()
+		if len(names) > 0 {
+			name = continuationSheetName(name, len(names)+1)
+		}
+		if _, err := file.NewSheet(name); err != nil {
+			return fmt.Errorf("failed to create sheet: %w", err)
+		}
+		writer, err := file.NewStreamWriter(name)
+		if err != nil {
+			return fmt.Errorf("failed to create stream writer: %w", err)
+		}
+		if tabular {
+			if err := writer.SetPanes(frozenHeader()); err != nil {
+				return fmt.Errorf("failed to freeze header: %w", err)
+			}
+		}
+		header := make([]any, len(columns))
+		for i, column := range columns {
+			if column.Width > 0 {
+				if err := writer.SetColWidth(i+1, i+1, column.Width); err != nil {
+					return fmt.Errorf("failed to set column width: %w", err)
+				}
+			}
+			header[i] = excelize.Cell{StyleID: headerStyle, Value: column.Header}
+		}
+		if err := writer.SetRow("A1", header); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		current = &sheetWriter{name: name, writer: writer, row: 1}
+		return nil
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+	for values := range sheet.Rows() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if current.row >= dataRowLimit {
+			if err := finish(); err != nil {
+				return err
+			}
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		current.row++
+		row := make([]any, len(values))
+		for i, value := range values {
+			if i < len(styles) && styles[i] != 0 {
+				row[i] = excelize.Cell{StyleID: styles[i], Value: value}
+			} else {
+				row[i] = value
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, current.row)
+		if err := current.writer.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", current.row, err)
+		}
+	}
+	return finish()
+}
+
+// writeStreamSummary writes the summary rows below the rows written to a streamed sheet, leaving a
+// blank row, like addSummary does for in-memory sheets.
+func writeStreamSummary(sheet *sheetWriter, columns []StreamColumn, labelStyle int, styles []int) error {
+	for i, row := range summaryRows(sheet.name, columns, 2, sheet.row, labelStyle, styles) {
+		cell, _ := excelize.CoordinatesToCellName(1, sheet.row+2+i)
+		if err := sheet.writer.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write summary row: %w", err)
+		}
+	}
+	return nil
+}
+
+// continuationSheetName returns the name of the nth sheet of a streamed sheet, n from 2.
+func continuationSheetName(sheetName string, n int) string {
+	return fmt.Sprintf("%s (%d)", sheetName, n)
+}
+
+// continuationSheets returns sheetName followed by its continuation sheets in file, in order.
+func continuationSheets(file *excelize.File, sheetName string) []string {
+	var sheets []string
+	for n := 1; ; n++ {
+		name := sheetName
+		if n > 1 {
+			name = continuationSheetName(sheetName, n)
+		}
+		if index, _ := file.GetSheetIndex(name); index < 0 {
+			return sheets
+		}
+		sheets = append(sheets, name)
+	}
+}
+
+// addStreamTable makes the rows written to a streamed sheet a table and names its data rows, like
+// addTable does for in-memory sheets.
+func addStreamTable(file *excelize.File, sheet *sheetWriter, columns int) error {
+	table := tableRange{sheet: sheet.name, columns: columns, firstRow: 2, lastRow: sheet.row}
+	base := uniqueTableBase(file, sheet.name)
+	if err := sheet.writer.AddTable(&excelize.Table{Range: table.ref(), Name: base + tableNameSuffix, StyleName: TableStyle}); err != nil {
+		return fmt.Errorf("failed to add table: %w", err)
+	}
+	if err := file.SetDefinedName(&excelize.DefinedName{Name: base + dataNameSuffix, RefersTo: table.body()}); err != nil {
+		return fmt.Errorf("failed to define name of table data: %w", err)
+	}
+	return defineColumns(file, table)
+}
@@ -0,0 +1,177 @@
+package xlsx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var chartSeriesPattern = regexp.MustCompile(`<(?:\w+:)?f>([^<]*)</(?:\w+:)?f>`)
+
+// streamedSheet returns a sheet of rows CPU rows with a summarized, highlighted column and a chart.
+func streamedSheet(name string, rows int) *TableSheet {
+	sheet := &TableSheet{
+		Name: name,
+		Fields: []StreamColumn{
+			{Header: "CPU"},
+			{Header: "Usage", Style: &excelize.Style{NumFmt: 10}, Summarize: true, Highlight: &ColumnHighlight{DataBar: true}},
+		},
+		Chart: &TableChart{Type: excelize.Col, Title: name, YAxis: "Usage", Series: []int{2}},
+	}
+	for i := range rows {
+		sheet.Data = append(sheet.Data, []any{fmt.Sprintf("cpu%d", i), float64(i) / 100})
+	}
+	return sheet
+}
+
+// reopen writes the workbook and reads it back, as a client would.
+func reopen(t *testing.T, file *excelize.File) *excelize.File {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := file.Write(&buf); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	_ = file.Close()
+	reopened, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() = %v", err)
+	}
+	t.Cleanup(func() { _ = reopened.Close() })
+	return reopened
+}
+
+func TestStreamWorkbookRollsOverToContinuationSheets(t *testing.T) {
+	// 10 rows per sheet: the header, 4 data rows and the 5 rows of the summary.
+	sheet := streamedSheet("CPU's", 10)
+	file, err := StreamWorkbook(context.Background(), StreamOptions{Threshold: 1, RowLimit: 10}, sheet)
+	if err != nil {
+		t.Fatalf("StreamWorkbook() = %v", err)
+	}
+	file = reopen(t, file)
+
+	parts := []struct {
+		name string
+		rows int
+	}{{"CPU's", 4}, {"CPU's (2)", 4}, {"CPU's (3)", 2}}
+	if sheets := file.GetSheetList(); !slices.Equal(sheets, []string{"CPU's", "CPU's (2)", "CPU's (3)"}) {
+		t.Fatalf("GetSheetList() = %q", sheets)
+	}
+
+	var cpus []string
+	for _, part := range parts {
+		lastRow := part.rows + 1
+		rows, err := file.GetRows(part.name)
+		if err != nil {
+			t.Fatalf("GetRows(%q) = %v", part.name, err)
+		}
+		if len(rows) > 10 {
+			t.Errorf("%s has %d rows, above the row limit", part.name, len(rows))
+		}
+		if !slices.Equal(rows[0], []string{"CPU", "Usage"}) {
+			t.Errorf("%s header = %q", part.name, rows[0])
+		}
+		for _, row := range rows[1:lastRow] {
+			cpus = append(cpus, row[0])
+		}
+
+		// Every part has its own table, data name, summary, chart and conditional formats, over its own rows only.
+		data := fmt.Sprintf("%s!$B$2:$B$%d", quoteSheetName(part.name), lastRow)
+		tables, err := file.GetTables(part.name)
+		if err != nil || len(tables) != 1 || tables[0].Range != fmt.Sprintf("A1:B%d", lastRow) {
+			t.Errorf("%s tables = %+v, %v, want A1:B%d", part.name, tables, err, lastRow)
+		}
+		if refersTo := definedName(file, tableBaseName(part.name)+dataNameSuffix, "Workbook"); refersTo != fmt.Sprintf("%s!$A$2:$B$%d", quoteSheetName(part.name), lastRow) {
+			t.Errorf("%s data name refers to %q", part.name, refersTo)
+		}
+		for i, function := range summaryFunctions {
+			cell := fmt.Sprintf("B%d", lastRow+2+i)
+			formula, _ := file.GetCellFormula(part.name, cell)
+			if want := fmt.Sprintf(function.formula, data); formula != want {
+				t.Errorf("%s!%s = %q, want %q", part.name, cell, formula, want)
+			}
+		}
+		formats, err := file.GetConditionalFormats(part.name)
+		if _, ok := formats[fmt.Sprintf("B2:B%d", lastRow)]; err != nil || len(formats) != 1 || !ok {
+			t.Errorf("%s conditional formats = %v, %v, want B2:B%d", part.name, formats, err, lastRow)
+		}
+	}
+	want := make([]string, 10)
+	for i := range want {
+		want[i] = "cpu" + strconv.Itoa(i)
+	}
+	if !slices.Equal(cpus, want) {
+		t.Errorf("data rows = %q, want %q", cpus, want)
+	}
+
+	// One chart per part, each plotting the named columns of its own sheet.
+	var series []string
+	file.Pkg.Range(func(key, value any) bool {
+		if path := key.(string); strings.HasPrefix(path, "xl/charts/chart") {
+			for _, match := range chartSeriesPattern.FindAllStringSubmatch(string(value.([]byte)), -1) {
+				ref := html.UnescapeString(match[1])
+				resolved := resolveReference(file, ref)
+				if resolved == ref && !strings.HasSuffix(ref, "$1") {
+					t.Errorf("chart series %s is not a named column", ref)
+				}
+				series = append(series, resolved)
+			}
+		}
+		return true
+	})
+	for _, part := range parts {
+		for _, ref := range []string{
+			fmt.Sprintf("%s!$A$2:$A$%d", quoteSheetName(part.name), part.rows+1),
+			fmt.Sprintf("%s!$B$2:$B$%d", quoteSheetName(part.name), part.rows+1),
+		} {
+			if !slices.Contains(series, ref) {
+				t.Errorf("no chart series of %s, in %q", ref, series)
+			}
+		}
+	}
+}
+
+func TestStreamWorkbookKeepsSmallSheetsWhole(t *testing.T) {
+	file, err := StreamWorkbook(context.Background(), StreamOptions{Threshold: 1, RowLimit: 100}, streamedSheet("Usage", 3))
+	if err != nil {
+		t.Fatalf("StreamWorkbook() = %v", err)
+	}
+	file = reopen(t, file)
+	if sheets := file.GetSheetList(); !slices.Equal(sheets, []string{"Usage"}) {
+		t.Fatalf("GetSheetList() = %q", sheets)
+	}
+	if formula, _ := file.GetCellFormula("Usage", "B6"); formula != "AVERAGE('Usage'!$B$2:$B$4)" {
+		t.Errorf("summary = %q", formula)
+	}
+}
+
+// definedName returns what the name defined in scope, a sheet or "Workbook", refers to, or "" when
+// there is no such name.
+func definedName(file *excelize.File, name string, scope string) string {
+	for _, definedName := range file.GetDefinedName() {
+		if definedName.Name == name && definedName.Scope == scope {
+			return definedName.RefersTo
+		}
+	}
+	return ""
+}
+
+// resolveReference returns the range a chart series refers to, following the name of a column.
+func resolveReference(file *excelize.File, ref string) string {
+	sheet, name, ok := strings.Cut(ref, "!")
+	if !ok || strings.HasPrefix(name, "$") {
+		return ref
+	}
+	sheet = strings.ReplaceAll(strings.Trim(sheet, "'"), "''", "'")
+	if refersTo := definedName(file, name, sheet); refersTo != "" {
+		return refersTo
+	}
+	return ref
+}
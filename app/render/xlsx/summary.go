@@ -0,0 +1,104 @@
+package xlsx
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// summaryFunctions are the aggregates of the summary rows below a table, one row each, in order.
+var summaryFunctions = []struct {
+	label   string
+	formula string
+}{
+	{"Average", "AVERAGE(%s)"},
+	{"Maximum", "MAX(%s)"},
+	{"Minimum", "MIN(%s)"},
+	// PERCENTILE rather than PERCENTILE.INC, which older readers only know with a prefix.
+	{"95th percentile", "PERCENTILE(%s,0.95)"},
+}
+
+// summaryHeight is the number of rows the summary takes below the last data row: a blank row and one
+// row per aggregate.
+var summaryHeight = 1 + len(summaryFunctions)
+
+// hasSummary reports whether a column of the table is summarized.
+func hasSummary(columns []StreamColumn) bool {
+	for _, column := range columns {
+		if column.Summarize {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryRows returns the summary rows of the data rows firstRow to lastRow: the aggregate name in
+// the first column and, in every summarized column, a formula over its data cells. styles are the
+// styles of the data cells of the columns.
+func summaryRows(sheetName string, columns []StreamColumn, firstRow int, lastRow int, labelStyle int, styles []int) [][]any {
+	table := tableRange{sheet: sheetName, columns: len(columns), firstRow: firstRow, lastRow: lastRow}
+	rows := make([][]any, len(summaryFunctions))
+	for i, function := range summaryFunctions {
+		row := make([]any, len(columns))
+		row[0] = excelize.Cell{StyleID: labelStyle, Value: function.label}
+		for col := 1; col < len(columns); col++ {
+			if columns[col].Summarize {
+				row[col] = excelize.Cell{StyleID: styles[col], Formula: fmt.Sprintf(function.formula, table.column(col+1))}
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// addSummary writes the summary rows below the data rows 2 to lastRow of an in-memory sheet, leaving
+// a blank row. Sheets without a summarized column or data rows, or without room left, are left as
+// they are.
+func addSummary(file *excelize.File, sheetName string, columns []StreamColumn, lastRow int) error {
+	if !hasSummary(columns) || lastRow < 2 || lastRow+summaryHeight > MaxSheetRows {
+		return nil
+	}
+	labelStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return fmt.Errorf("failed to create summary label style: %w", err)
+	}
+	styles := make([]int, len(columns))
+	for i, column := range columns {
+		if column.Style == nil || !column.Summarize {
+			continue
+		}
+		if styles[i], err = file.NewStyle(column.Style); err != nil {
+			return fmt.Errorf("failed to create summary style: %w", err)
+		}
+	}
+	for i, row := range summaryRows(sheetName, columns, 2, lastRow, labelStyle, styles) {
+		for j, value := range row {
+			summary, ok := value.(excelize.Cell)
+			if !ok {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(j+1, lastRow+2+i)
+			if summary.Formula != "" {
+				if err := file.SetCellFormula(sheetName, cell, summary.Formula); err != nil {
+					return fmt.Errorf("failed to set summary formula: %w", err)
+				}
+			} else if err := file.SetCellValue(sheetName, cell, summary.Value); err != nil {
+				return fmt.Errorf("failed to set summary label: %w", err)
+			}
+			if err := file.SetCellStyle(sheetName, cell, cell, summary.StyleID); err != nil {
+				return fmt.Errorf("failed to set summary style: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// recalculateOnLoad asks spreadsheet applications to compute every formula when the workbook is
+// opened, since the formulas are written without cached values.
+func recalculateOnLoad(file *excelize.File) error {
+	fullCalcOnLoad := true
+	if err := file.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &fullCalcOnLoad}); err != nil {
+		return fmt.Errorf("failed to set calculation properties: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package xlsx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TableStyle is the built-in style of the tables of the data sheets.
+const TableStyle = "TableStyleMedium2"
+
+// Suffixes of the table of a sheet and of the workbook-level defined name of its data rows. They keep
+// both names apart and never look like a cell reference.
+const (
+	tableNameSuffix = "_Table"
+	dataNameSuffix  = "_Data"
+)
+
+// tableRange locates the table of a sheet: the header row above the data rows firstRow to lastRow of
+// its columns 1 to columns. Chart series and defined names refer to the table through it.
+type tableRange struct {
+	sheet    string
+	columns  int
+	firstRow int
+	lastRow  int
+}
+
+// ref returns the range of the table including its header row, e.g. "A1:D7".
+func (t tableRange) ref() string {
+	first, _ := excelize.CoordinatesToCellName(1, t.firstRow-1)
+	last, _ := excelize.CoordinatesToCellName(t.columns, t.lastRow)
+	return first + ":" + last
+}
+
+// body returns the absolute reference of the data rows, e.g. "'CPU System Usage'!$A$2:$D$7".
+func (t tableRange) body() string {
+	first, _ := excelize.ColumnNumberToName(1)
+	last, _ := excelize.ColumnNumberToName(t.columns)
+	return fmt.Sprintf("%s!$%s$%d:$%s$%d", quoteSheetName(t.sheet), first, t.firstRow, last, t.lastRow)
+}
+
+// column returns the absolute reference of the data cells of column col, e.g. "'CPU System Usage'!$B$2:$B$7".
+func (t tableRange) column(col int) string {
+	name, _ := excelize.ColumnNumberToName(col)
+	return fmt.Sprintf("%s!$%s$%d:$%s$%d", quoteSheetName(t.sheet), name, t.firstRow, name, t.lastRow)
+}
+
+// columnName returns the name of the data cells of column col, defined on the sheet of the table by
+// defineColumns, e.g. "CPU_System_Usage_B".
+func (t tableRange) columnName(col int) string {
+	name, _ := excelize.ColumnNumberToName(col)
+	return tableBaseName(t.sheet) + "_" + name
+}
+
+// series returns the reference of a chart series to the data cells of column col: the name of the
+// column qualified by its sheet, e.g. "'CPU System Usage'!CPU_System_Usage_B".
+func (t tableRange) series(col int) string {
+	return quoteSheetName(t.sheet) + "!" + t.columnName(col)
+}
+
+// ColumnSeries returns the reference of chart series to the data cells of column col of the table of
+// sheetName, e.g. "'CPU System Usage'!CPU_System_Usage_B", so that other formats can resolve them.
+func ColumnSeries(sheetName string, col int) string {
+	return tableRange{sheet: sheetName}.series(col)
+}
+
+// header returns the absolute reference of the header cell of column col.
+func (t tableRange) header(col int) string {
+	name, _ := excelize.ColumnNumberToName(col)
+	return fmt.Sprintf("%s!$%s$%d", quoteSheetName(t.sheet), name, t.firstRow-1)
+}
+
+// parseTableColumn returns the data rows of the table of sheetName that a column reference such as
+// "'CPU System Usage'!$A$2:$A$7" spans, and false when refersTo is not a range of that sheet.
+func parseTableColumn(sheetName string, refersTo string) (tableRange, bool) {
+	cells, ok := strings.CutPrefix(refersTo, quoteSheetName(sheetName)+"!")
+	if !ok {
+		return tableRange{}, false
+	}
+	first, last, ok := strings.Cut(strings.ReplaceAll(cells, "$", ""), ":")
+	if !ok {
+		return tableRange{}, false
+	}
+	_, firstRow, err := excelize.CellNameToCoordinates(first)
+	if err != nil {
+		return tableRange{}, false
+	}
+	_, lastRow, err := excelize.CellNameToCoordinates(last)
+	if err != nil {
+		return tableRange{}, false
+	}
+	return tableRange{sheet: sheetName, firstRow: firstRow, lastRow: lastRow}, true
+}
+
+// quoteSheetName quotes a sheet name for a cell reference.
+func quoteSheetName(sheetName string) string {
+	return "'" + strings.ReplaceAll(sheetName, "'", "''") + "'"
+}
+
+// tableBaseName turns a sheet name into the base of its table and defined names: letters, digits
+// and underscores, starting with a letter or an underscore.
+func tableBaseName(sheetName string) string {
+	var b strings.Builder
+	for _, r := range sheetName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else if !strings.HasSuffix(b.String(), "_") {
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "_" + name
+	}
+	return name
+}
+
+// frozenHeader freezes the header row of a sheet.
+func frozenHeader() *excelize.Panes {
+	return &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+		Selection:   []excelize.Selection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}},
+	}
+}
+
+// uniqueTableBase returns the base of the table and defined names of a sheet, with a numeric suffix
+// when another sheet of file already uses it.
+func uniqueTableBase(file *excelize.File, sheetName string) string {
+	taken := make(map[string]bool)
+	for _, definedName := range file.GetDefinedName() {
+		taken[strings.ToLower(definedName.Name)] = true
+	}
+	base := tableBaseName(sheetName)
+	unique := base
+	for n := 2; taken[strings.ToLower(unique+dataNameSuffix)]; n++ {
+		unique = fmt.Sprintf("%s_%d", base, n)
+	}
+	return unique
+}
+
+// addTable turns the header row and the data rows up to lastRow of an in-memory sheet into a table
+// with an autofilter, freezes the header row and names the data rows in the workbook. Sheets without
+// data rows are left as they are.
+func addTable(file *excelize.File, sheetName string, columns int, lastRow int) error {
+	if columns == 0 || lastRow < 2 {
+		return nil
+	}
+	table := tableRange{sheet: sheetName, columns: columns, firstRow: 2, lastRow: lastRow}
+	base := uniqueTableBase(file, sheetName)
+	if err := file.AddTable(sheetName, &excelize.Table{Range: table.ref(), Name: base + tableNameSuffix, StyleName: TableStyle}); err != nil {
+		return fmt.Errorf("failed to add table: %w", err)
+	}
+	if err := file.SetPanes(sheetName, frozenHeader()); err != nil {
+		return fmt.Errorf("failed to freeze header: %w", err)
+	}
+	if err := file.SetDefinedName(&excelize.DefinedName{Name: base + dataNameSuffix, RefersTo: table.body()}); err != nil {
+		return fmt.Errorf("failed to define name of table data: %w", err)
+	}
+	return defineColumns(file, table)
+}
+
+// defineColumns names the data cells of every column of the table on its sheet, for the chart series
+// to refer to. The names are local to the sheet, so the continuation sheets of a streamed sheet name
+// their own rows.
+func defineColumns(file *excelize.File, table tableRange) error {
+	for col := 1; col <= table.columns; col++ {
+		if err := file.SetDefinedName(&excelize.DefinedName{Name: table.columnName(col), RefersTo: table.column(col), Scope: table.sheet}); err != nil {
+			return fmt.Errorf("failed to define name of table column: %w", err)
+		}
+	}
+	return nil
+}
+
+// addUniquelyNamed calls add with name, then with name_2, name_3, ... while add fails with taken.
+func addUniquelyNamed(name string, taken error, add func(name string) error) error {
+	err := add(name)
+	for n := 2; errors.Is(err, taken); n++ {
+		err = add(fmt.Sprintf("%s_%d", name, n))
+	}
+	return err
+}
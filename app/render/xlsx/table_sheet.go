@@ -0,0 +1,104 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TableChart plots some columns of a TableSheet against its first column.
+type TableChart struct {
+	Type  excelize.ChartType
+	Title string
+	YAxis string
+	// Series are the 1-based numbers of the plotted columns.
+	Series []int
+}
+
+// TableSheet is a generic sheet: a table of values with an optional chart below it. It renders the
+// sections of report definitions, whose columns are only known at runtime.
+type TableSheet struct {
+	Name string
+	// Fields are the columns of the table, in order.
+	Fields []StreamColumn
+	Data   [][]any
+	Chart  *TableChart
+	// Unavailable, when set, is the reason the data could not be fetched; a placeholder is rendered instead.
+	Unavailable string
+}
+
+// SheetName returns the name of the worksheet rendered by the report.
+func (r *TableSheet) SheetName() string {
+	return r.Name
+}
+
+// Render creates the sheet in file. It stops early when ctx is done.
+func (r *TableSheet) Render(ctx context.Context, file *excelize.File) error {
+	charts, err := r.RenderSheet(ctx, file)
+	if err != nil {
+		return err
+	}
+	return AddCharts(file, r.SheetName(), charts)
+}
+
+// RenderSheet writes the table and returns the chart to anchor on the sheet.
+func (r *TableSheet) RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error) {
+	sheetName := r.SheetName()
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+	if r.Unavailable != "" {
+		return nil, renderUnavailable(file, sheetName, r.Unavailable)
+	}
+	lastRow, err := renderTable(ctx, file, sheetName, r.Columns(), r.Rows())
+	if err != nil {
+		return nil, err
+	}
+	if err := AddConditionalFormats(file, sheetName, r.ConditionalFormats(sheetName, 2, lastRow)); err != nil {
+		return nil, err
+	}
+	return r.Charts(sheetName, 2, lastRow), nil
+}
+
+// Columns returns the columns of the table.
+func (r *TableSheet) Columns() []StreamColumn {
+	if r.Unavailable != "" {
+		return unavailableColumns()
+	}
+	return r.Fields
+}
+
+// Rows yields the rows of the table.
+func (r *TableSheet) Rows() iter.Seq[[]any] {
+	return func(yield func([]any) bool) {
+		if r.Unavailable != "" {
+			yield([]any{r.Unavailable})
+			return
+		}
+		for _, row := range r.Data {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
+
+// Charts returns the chart of the rows firstRow to lastRow, if the sheet has one.
+func (r *TableSheet) Charts(sheetName string, firstRow int, lastRow int) []SheetChart {
+	if r.Unavailable != "" || r.Chart == nil || lastRow < firstRow {
+		return nil
+	}
+	chart := columnChart(sheetName, r.Chart.Title, r.Chart.YAxis, firstRow, lastRow, r.Fields, r.Chart.Series...)
+	chart.Chart.Type = r.Chart.Type
+	return []SheetChart{chart}
+}
+
+// ConditionalFormats returns the conditional formats of the highlighted columns over the rows firstRow to lastRow.
+func (r *TableSheet) ConditionalFormats(sheetName string, firstRow int, lastRow int) []ConditionalFormat {
+	if r.Unavailable != "" {
+		return nil
+	}
+	return columnConditionalFormats(r.Fields, firstRow, lastRow)
+}
@@ -0,0 +1,365 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// A template workbook binds report data in two ways:
+//
+//   - a text cell holding "{{name}}" takes the value of the placeholder name: a cell holding only the
+//     placeholder takes the value itself, keeping the number format of the cell, and longer text takes
+//     its formatted text;
+//   - a defined name referring to one row of a sheet takes the rows of the table of the same name. The
+//     row is the template of the data rows: it is duplicated once per row, with its styles, conditional
+//     formats and formulas, and the values fill its cells from the left.
+//
+// The formulas, defined names, tables and chart series that refer to a range ending on the template row
+// are stretched to the last data row, so that totals and charts cover every row. Each table needs rows
+// of its own: everything below its template row moves down as rows are added.
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.]*)\s*\}\}`)
+	// rangePattern matches a range reference of a formula, with an optional sheet name.
+	rangePattern = regexp.MustCompile(`((?:'(?:[^']|'')+'|[A-Za-z_][A-Za-z0-9_.]*)!)?(\$?[A-Za-z]{1,3}\$?)(\d+):(\$?[A-Za-z]{1,3}\$?)(\d+)`)
+	// chartRefPattern matches the references of chart series, with or without the namespace prefix.
+	chartRefPattern = regexp.MustCompile(`<((?:\w+:)?f)>([^<]*)</(?:\w+:)?f>`)
+	tableRefPattern = regexp.MustCompile(`\bref="([A-Z]+)(\d+):([A-Z]+)(\d+)"`)
+)
+
+// TemplateValue is the value of a placeholder: Value replaces a cell holding only the placeholder
+// and Text replaces the placeholder within longer text.
+type TemplateValue struct {
+	Value any
+	Text  string
+}
+
+// TemplateData is the report data bound to a template workbook.
+type TemplateData struct {
+	// Values are the placeholders, by name.
+	Values map[string]TemplateValue
+	// Tables are the rows written to the defined names, by name, ignoring case as Excel does.
+	Tables map[string][][]any
+}
+
+// TemplateInfo lists what a template workbook binds.
+type TemplateInfo struct {
+	// Placeholders are the names of the placeholders of its cells.
+	Placeholders []string
+	// Tables are the defined names that refer to a single row, which can take the rows of a table.
+	Tables []string
+}
+
+// InspectTemplate opens the template workbook at path and returns what it binds, so that a
+// definition can be checked against its template.
+func InspectTemplate(path string) (TemplateInfo, error) {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return TemplateInfo{}, fmt.Errorf("failed to open template: %w", err)
+	}
+	defer file.Close()
+
+	var info TemplateInfo
+	err = eachTemplateCell(file, func(sheetName string, cell string, value string) error {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+			if !slices.Contains(info.Placeholders, match[1]) {
+				info.Placeholders = append(info.Placeholders, match[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return TemplateInfo{}, err
+	}
+	for _, name := range file.GetDefinedName() {
+		if binding, ok := parseTemplateRow(name.RefersTo); ok && binding.sheet != "" {
+			info.Tables = append(info.Tables, name.Name)
+		}
+	}
+	return info, nil
+}
+
+// RenderTemplate opens the template workbook at path and fills it with data. The template file is
+// left unchanged; the caller writes the returned workbook and closes it.
+func RenderTemplate(ctx context.Context, path string, data TemplateData) (*excelize.File, error) {
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template: %w", err)
+	}
+	if err := fillTemplate(ctx, file, data); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := recalculateOnLoad(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func fillTemplate(ctx context.Context, file *excelize.File, data TemplateData) error {
+	// The placeholders are replaced first, while the sheets hold only the template cells.
+	err := eachTemplateCell(file, func(sheetName string, cell string, value string) error {
+		if !strings.Contains(value, "{{") {
+			return nil
+		}
+		if match := placeholderPattern.FindStringSubmatch(value); match != nil && match[0] == strings.TrimSpace(value) {
+			if placeholder, ok := data.Values[match[1]]; ok {
+				return file.SetCellValue(sheetName, cell, placeholder.Value)
+			}
+			return nil
+		}
+		replaced := placeholderPattern.ReplaceAllStringFunc(value, func(text string) string {
+			if placeholder, ok := data.Values[placeholderPattern.FindStringSubmatch(text)[1]]; ok {
+				return placeholder.Text
+			}
+			return text
+		})
+		if replaced == value {
+			return nil
+		}
+		return file.SetCellValue(sheetName, cell, replaced)
+	})
+	if err != nil {
+		return err
+	}
+
+	tables := make(map[string][][]any, len(data.Tables))
+	for name, rows := range data.Tables {
+		tables[strings.ToLower(name)] = rows
+	}
+	for _, name := range file.GetDefinedName() {
+		rows, ok := tables[strings.ToLower(name.Name)]
+		if !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// The defined names move as rows are added above them, so each one is read again.
+		binding, ok := lookupTemplateRow(file, name.Name, name.Scope)
+		if !ok {
+			return fmt.Errorf("defined name %q does not refer to a single row", name.Name)
+		}
+		if err := fillTemplateRows(ctx, file, binding, rows); err != nil {
+			return fmt.Errorf("failed to fill %q: %w", name.Name, err)
+		}
+	}
+	return nil
+}
+
+// templateRow is the template row of a table: the cells firstCol to lastCol of row.
+type templateRow struct {
+	sheet    string
+	firstCol int
+	lastCol  int
+	row      int
+}
+
+// parseTemplateRow parses a defined name referring to a single row, such as "'Sales'!$A$5:$D$5".
+func parseTemplateRow(refersTo string) (templateRow, bool) {
+	refersTo = strings.TrimPrefix(strings.TrimSpace(refersTo), "=")
+	separator := strings.LastIndex(refersTo, "!")
+	if separator < 0 {
+		return templateRow{}, false
+	}
+	sheetName := refersTo[:separator]
+	if strings.HasPrefix(sheetName, "'") && strings.HasSuffix(sheetName, "'") && len(sheetName) > 1 {
+		sheetName = strings.ReplaceAll(sheetName[1:len(sheetName)-1], "''", "'")
+	}
+	first, last, _ := strings.Cut(strings.ReplaceAll(refersTo[separator+1:], "$", ""), ":")
+	if last == "" {
+		last = first
+	}
+	firstCol, firstRow, err := excelize.CellNameToCoordinates(first)
+	if err != nil {
+		return templateRow{}, false
+	}
+	lastCol, lastRow, err := excelize.CellNameToCoordinates(last)
+	if err != nil || firstRow != lastRow {
+		return templateRow{}, false
+	}
+	return templateRow{sheet: sheetName, firstCol: min(firstCol, lastCol), lastCol: max(firstCol, lastCol), row: firstRow}, true
+}
+
+func lookupTemplateRow(file *excelize.File, name string, scope string) (templateRow, bool) {
+	for _, definedName := range file.GetDefinedName() {
+		if definedName.Name == name && definedName.Scope == scope {
+			return parseTemplateRow(definedName.RefersTo)
+		}
+	}
+	return templateRow{}, false
+}
+
+// fillTemplateRows duplicates the template row once per row after the first, writes the rows and
+// stretches the ranges ending on the template row over them. Without rows, the template cells are cleared.
+func fillTemplateRows(ctx context.Context, file *excelize.File, binding templateRow, rows [][]any) error {
+	if len(rows) == 0 {
+		for col := binding.firstCol; col <= binding.lastCol; col++ {
+			cell, _ := excelize.CoordinatesToCellName(col, binding.row)
+			if err := file.SetCellValue(binding.sheet, cell, nil); err != nil {
+				return fmt.Errorf("failed to clear cell %s: %w", cell, err)
+			}
+		}
+		return nil
+	}
+	// Every copy goes right below the template row, pushing the previous ones down.
+	for range len(rows) - 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := file.DuplicateRowTo(binding.sheet, binding.row, binding.row+1); err != nil {
+			return fmt.Errorf("failed to duplicate template row: %w", err)
+		}
+	}
+	for i, values := range rows {
+		for j, value := range values[:min(len(values), binding.lastCol-binding.firstCol+1)] {
+			cell, _ := excelize.CoordinatesToCellName(binding.firstCol+j, binding.row+i)
+			if err := file.SetCellValue(binding.sheet, cell, value); err != nil {
+				return fmt.Errorf("failed to set cell %s: %w", cell, err)
+			}
+		}
+	}
+	return stretchRanges(file, binding.sheet, binding.row, binding.row+len(rows)-1)
+}
+
+// stretchRanges extends the ranges of sheetName that end on row, starting on it or above, down to
+// last: in the formulas outside the rows row to last, the defined names, the tables and the charts.
+func stretchRanges(file *excelize.File, sheetName string, row int, last int) error {
+	if last == row {
+		return nil
+	}
+	for _, current := range file.GetSheetList() {
+		// The rows hold every cell with a value or a formula.
+		rows, err := file.GetRows(current, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return fmt.Errorf("failed to read sheet %q: %w", current, err)
+		}
+		for r, cells := range rows {
+			if current == sheetName && r+1 >= row && r+1 <= last {
+				continue
+			}
+			for c := range cells {
+				cell, _ := excelize.CoordinatesToCellName(c+1, r+1)
+				formula, err := file.GetCellFormula(current, cell)
+				if err != nil || formula == "" {
+					continue
+				}
+				if stretched := stretchFormula(formula, current, sheetName, row, last); stretched != formula {
+					if err := file.SetCellFormula(current, cell, stretched); err != nil {
+						return fmt.Errorf("failed to set formula of cell %s: %w", cell, err)
+					}
+				}
+			}
+		}
+	}
+
+	for _, definedName := range file.GetDefinedName() {
+		stretched := stretchFormula(definedName.RefersTo, "", sheetName, row, last)
+		if stretched == definedName.RefersTo {
+			continue
+		}
+		if err := file.DeleteDefinedName(&excelize.DefinedName{Name: definedName.Name, Scope: definedName.Scope}); err != nil {
+			return fmt.Errorf("failed to update defined name %q: %w", definedName.Name, err)
+		}
+		definedName.RefersTo = stretched
+		if err := file.SetDefinedName(&definedName); err != nil {
+			return fmt.Errorf("failed to update defined name %q: %w", definedName.Name, err)
+		}
+	}
+
+	// Tables and charts are kept as the parts read from the template, and are updated in place.
+	tables, err := file.GetTables(sheetName)
+	if err != nil {
+		return fmt.Errorf("failed to read tables: %w", err)
+	}
+	file.Pkg.Range(func(key, value any) bool {
+		path, content := key.(string), string(value.([]byte))
+		switch {
+		case strings.HasPrefix(path, "xl/tables/"):
+			if !slices.ContainsFunc(tables, func(table excelize.Table) bool {
+				return strings.Contains(content, ` name="`+html.EscapeString(table.Name)+`"`)
+			}) {
+				return true
+			}
+			content = tableRefPattern.ReplaceAllStringFunc(content, func(ref string) string {
+				match := tableRefPattern.FindStringSubmatch(ref)
+				if end, _ := strconv.Atoi(match[4]); end != row {
+					return ref
+				}
+				return fmt.Sprintf(`ref="%s%s:%s%d"`, match[1], match[2], match[3], last)
+			})
+		case strings.HasPrefix(path, "xl/charts/chart"):
+			content = chartRefPattern.ReplaceAllStringFunc(content, func(ref string) string {
+				match := chartRefPattern.FindStringSubmatch(ref)
+				formula := stretchFormula(html.UnescapeString(match[2]), "", sheetName, row, last)
+				return "<" + match[1] + ">" + html.EscapeString(formula) + "</" + match[1] + ">"
+			})
+		default:
+			return true
+		}
+		file.Pkg.Store(path, []byte(content))
+		return true
+	})
+	return nil
+}
+
+// stretchFormula extends the ranges of sheetName in formula, written on formulaSheet, that end on
+// row and start on it or above, down to last. Text within quotes is left alone.
+func stretchFormula(formula string, formulaSheet string, sheetName string, row int, last int) string {
+	parts := strings.Split(formula, `"`)
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = rangePattern.ReplaceAllStringFunc(parts[i], func(ref string) string {
+			match := rangePattern.FindStringSubmatch(ref)
+			refSheet := formulaSheet
+			if match[1] != "" {
+				refSheet = strings.TrimSuffix(match[1], "!")
+				if strings.HasPrefix(refSheet, "'") {
+					refSheet = strings.ReplaceAll(refSheet[1:len(refSheet)-1], "''", "'")
+				}
+			}
+			start, _ := strconv.Atoi(match[3])
+			end, _ := strconv.Atoi(match[5])
+			if refSheet != sheetName || end != row || start > row {
+				return ref
+			}
+			return match[1] + match[2] + match[3] + ":" + match[4] + strconv.Itoa(last)
+		})
+	}
+	return strings.Join(parts, `"`)
+}
+
+// eachTemplateCell calls fn with the text of every text cell of file without a formula.
+func eachTemplateCell(file *excelize.File, fn func(sheetName string, cell string, value string) error) error {
+	for _, sheetName := range file.GetSheetList() {
+		rows, err := file.GetRows(sheetName, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return fmt.Errorf("failed to read sheet %q: %w", sheetName, err)
+		}
+		for r, row := range rows {
+			for c, value := range row {
+				if value == "" {
+					continue
+				}
+				cell, _ := excelize.CoordinatesToCellName(c+1, r+1)
+				if cellType, _ := file.GetCellType(sheetName, cell); cellType != excelize.CellTypeSharedString && cellType != excelize.CellTypeInlineString {
+					continue
+				}
+				if formula, _ := file.GetCellFormula(sheetName, cell); formula != "" {
+					continue
+				}
+				if err := fn(sheetName, cell, value); err != nil {
+					return fmt.Errorf("failed to fill cell %s of sheet %q: %w", cell, sheetName, err)
+				}
+			}
+		}
+	}
+	return nil
+}
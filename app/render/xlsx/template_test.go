@@ -0,0 +1,213 @@
+package xlsx
+
+import (
+	"context"
+	"html"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// templateSheet has a quoted name, so that every reference to it is quoted.
+const templateSheet = "Bob's Sales"
+
+// writeTemplate saves a template with the placeholders of the title and the period, and two bound
+// tables on the same sheet: "items" on row 4 and "others" on row 11, each an Excel table with a total
+// below it. Another sheet, a defined name and a chart refer to the items too.
+func writeTemplate(t *testing.T) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer file.Close()
+	if err := file.SetSheetName("Sheet1", templateSheet); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.NewSheet("Summary"); err != nil {
+		t.Fatal(err)
+	}
+	for cell, value := range map[string]any{
+		"A1": "{{title}}", "B1": "Period: {{period}}",
+		"A3": "Item", "B3": "Amount", "A4": "", "B4": 0, "A6": "Total",
+		"A10": "Other", "B10": "Amount", "A11": "", "B11": 0,
+	} {
+		if err := file.SetCellValue(templateSheet, cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for cell, formula := range map[string]string{
+		"B6":  "SUM(B4:B4)",
+		"B7":  "AVERAGE('Bob''s Sales'!B4:B4)",
+		"B8":  `CONCATENATE("B4:B4 ",SUM(B4:B4))`,
+		"B13": "SUM(B11:B11)",
+	} {
+		if err := file.SetCellFormula(templateSheet, cell, formula); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := file.SetCellFormula("Summary", "A1", "SUM('Bob''s Sales'!B4:B4)"); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []excelize.Table{{Range: "A3:B4", Name: "Items"}, {Range: "A10:B11", Name: "Others"}} {
+		if err := file.AddTable(templateSheet, &table); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, refersTo := range map[string]string{
+		"items":        "'Bob''s Sales'!$A$4:$B$4",
+		"others":       "'Bob''s Sales'!$A$11:$B$11",
+		"items_amount": "'Bob''s Sales'!$B$4:$B$4",
+	} {
+		if err := file.SetDefinedName(&excelize.DefinedName{Name: name, RefersTo: refersTo}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := file.AddChart(templateSheet, "D3", &excelize.Chart{
+		Type: excelize.Col,
+		Series: []excelize.ChartSeries{{
+			Name:       "'Bob''s Sales'!$B$3",
+			Categories: "'Bob''s Sales'!$A$4:$A$4",
+			Values:     "'Bob''s Sales'!$B$4:$B$4",
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "template.xlsx")
+	if err := file.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// renderTemplate renders the template with the tables and reads the result back.
+func renderTemplate(t *testing.T, tables map[string][][]any) *excelize.File {
+	t.Helper()
+	file, err := RenderTemplate(context.Background(), writeTemplate(t), TemplateData{
+		Values: map[string]TemplateValue{"title": {Value: "Sales", Text: "Sales"}, "period": {Value: "Q1", Text: "Q1"}},
+		Tables: tables,
+	})
+	if err != nil {
+		t.Fatalf("RenderTemplate() = %v", err)
+	}
+	return reopen(t, file)
+}
+
+// checkFormulas compares the formulas of the cells of a sheet.
+func checkFormulas(t *testing.T, file *excelize.File, sheetName string, want map[string]string) {
+	t.Helper()
+	for cell, formula := range want {
+		if got, _ := file.GetCellFormula(sheetName, cell); got != formula {
+			t.Errorf("%s!%s = %q, want %q", sheetName, cell, got, formula)
+		}
+	}
+}
+
+// checkTables compares the ranges of the tables of the template sheet, by name.
+func checkTables(t *testing.T, file *excelize.File, want map[string]string) {
+	t.Helper()
+	tables, err := file.GetTables(templateSheet)
+	if err != nil {
+		t.Fatalf("GetTables() = %v", err)
+	}
+	got := make(map[string]string, len(tables))
+	for _, table := range tables {
+		got[table.Name] = table.Range
+	}
+	for name, ref := range want {
+		if got[name] != ref {
+			t.Errorf("table %s = %q, want %q", name, got[name], ref)
+		}
+	}
+}
+
+// chartRefs returns the references of the chart series.
+func chartRefs(file *excelize.File) []string {
+	var refs []string
+	file.Pkg.Range(func(key, value any) bool {
+		if strings.HasPrefix(key.(string), "xl/charts/chart") {
+			for _, match := range chartRefPattern.FindAllStringSubmatch(string(value.([]byte)), -1) {
+				refs = append(refs, html.UnescapeString(match[2]))
+			}
+		}
+		return true
+	})
+	return refs
+}
+
+func TestInspectTemplate(t *testing.T) {
+	info, err := InspectTemplate(writeTemplate(t))
+	if err != nil {
+		t.Fatalf("InspectTemplate() = %v", err)
+	}
+	slices.Sort(info.Placeholders)
+	slices.Sort(info.Tables)
+	if !slices.Equal(info.Placeholders, []string{"period", "title"}) {
+		t.Errorf("placeholders = %q", info.Placeholders)
+	}
+	if !slices.Equal(info.Tables, []string{"items", "items_amount", "others"}) {
+		t.Errorf("tables = %q", info.Tables)
+	}
+}
+
+func TestRenderTemplateStretchesTheRangesOfTheRows(t *testing.T) {
+	file := renderTemplate(t, map[string][][]any{
+		"Items":  {{"pens", 3}, {"books", 5}, {"ink", 7}},
+		"others": {{"paper", 11}, {"tape", 13}},
+	})
+
+	if title, _ := file.GetCellValue(templateSheet, "A1"); title != "Sales" {
+		t.Errorf("title = %q", title)
+	}
+	if period, _ := file.GetCellValue(templateSheet, "B1"); period != "Period: Q1" {
+		t.Errorf("period = %q", period)
+	}
+	rows, _ := file.GetRows(templateSheet)
+	for row, want := range map[int]string{4: "pens", 5: "books", 6: "ink", 13: "paper", 14: "tape"} {
+		if got := rows[row-1][0]; got != want {
+			t.Errorf("A%d = %q, want %q", row, got, want)
+		}
+	}
+
+	// The items add two rows, moving the totals and the others down; the others add one more.
+	checkFormulas(t, file, templateSheet, map[string]string{
+		"B8":  "SUM(B4:B6)",
+		"B9":  "AVERAGE('Bob''s Sales'!B4:B6)",
+		"B10": `CONCATENATE("B4:B4 ",SUM(B4:B6))`,
+		"B16": "SUM(B13:B14)",
+	})
+	checkFormulas(t, file, "Summary", map[string]string{"A1": "SUM('Bob''s Sales'!B4:B6)"})
+	checkTables(t, file, map[string]string{"Items": "A3:B6", "Others": "A12:B14"})
+	if refersTo := definedName(file, "items_amount", "Workbook"); refersTo != "'Bob''s Sales'!$B$4:$B$6" {
+		t.Errorf("items_amount refers to %q", refersTo)
+	}
+	refs := chartRefs(file)
+	for _, want := range []string{"'Bob''s Sales'!$A$4:$A$6", "'Bob''s Sales'!$B$4:$B$6"} {
+		if !slices.Contains(refs, want) {
+			t.Errorf("chart series %q, want %q", refs, want)
+		}
+	}
+}
+
+func TestRenderTemplateWithoutRows(t *testing.T) {
+	file := renderTemplate(t, map[string][][]any{
+		"items":  {{"pens", 3}},
+		"others": {},
+	})
+
+	// One row fills the template row, none clears it; either way nothing moves.
+	if item, _ := file.GetCellValue(templateSheet, "A4"); item != "pens" {
+		t.Errorf("A4 = %q", item)
+	}
+	if amount, _ := file.GetCellValue(templateSheet, "B11"); amount != "" {
+		t.Errorf("B11 = %q, want it cleared", amount)
+	}
+	checkFormulas(t, file, templateSheet, map[string]string{
+		"B6":  "SUM(B4:B4)",
+		"B13": "SUM(B11:B11)",
+	})
+	checkTables(t, file, map[string]string{"Items": "A3:B4", "Others": "A10:B11"})
+	if !slices.Contains(chartRefs(file), "'Bob''s Sales'!$B$4:$B$4") {
+		t.Errorf("chart series %q", chartRefs(file))
+	}
+}
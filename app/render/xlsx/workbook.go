@@ -0,0 +1,164 @@
+package xlsx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+// SheetChart is a chart anchored at Cell of a sheet. Charts are described rather than added
+// directly so that they survive merging a sheet into another workbook.
+type SheetChart struct {
+	Cell  string
+	Chart *excelize.Chart
+}
+
+// Sheet is a report section rendered as a single worksheet.
+type Sheet interface {
+	SheetName() string
+	// RenderSheet writes the sheet into file and returns the charts to add to it.
+	RenderSheet(ctx context.Context, file *excelize.File) ([]SheetChart, error)
+}
+
+// Report is a sheet that can be rendered both in memory and with the streaming writer.
+type Report interface {
+	Sheet
+	StreamSheet
+}
+
+// AddCharts adds the described charts to a sheet of file.
+func AddCharts(file *excelize.File, sheetName string, charts []SheetChart) error {
+	for _, chart := range charts {
+		if err := file.AddChart(sheetName, chart.Cell, chart.Chart); err != nil {
+			return fmt.Errorf("failed to add chart: %w", err)
+		}
+	}
+	return nil
+}
+
+// RenderWorkbook renders every sheet concurrently into its own workbook and merges them, in order,
+// into a new workbook. The first sheet is the active one.
+func RenderWorkbook[S Sheet](ctx context.Context, sheets ...S) (*excelize.File, error) {
+	files := make([]*excelize.File, len(sheets))
+	charts := make([][]SheetChart, len(sheets))
+	defer func() {
+		for _, file := range files {
+			if file != nil {
+				_ = file.Close()
+			}
+		}
+	}()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, sheet := range sheets {
+		files[i] = excelize.NewFile()
+		group.Go(func() error {
+			sheetCharts, err := sheet.RenderSheet(groupCtx, files[i])
+			if err != nil {
+				return fmt.Errorf("failed to render sheet %q: %w", sheet.SheetName(), err)
+			}
+			charts[i] = sheetCharts
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	workbook := excelize.NewFile()
+	defaultSheet := workbook.GetSheetName(0)
+	for i, sheet := range sheets {
+		if err := ctx.Err(); err != nil {
+			_ = workbook.Close()
+			return nil, err
+		}
+		if err := MergeSheet(workbook, files[i], sheet.SheetName()); err != nil {
+			_ = workbook.Close()
+			return nil, err
+		}
+		if err := AddCharts(workbook, sheet.SheetName(), charts[i]); err != nil {
+			_ = workbook.Close()
+			return nil, fmt.Errorf("failed to render sheet %q: %w", sheet.SheetName(), err)
+		}
+	}
+
+	if len(sheets) > 0 {
+		if err := workbook.DeleteSheet(defaultSheet); err != nil {
+			_ = workbook.Close()
+			return nil, fmt.Errorf("failed to delete default sheet: %w", err)
+		}
+		if idx, err := workbook.GetSheetIndex(sheets[0].SheetName()); err == nil {
+			workbook.SetActiveSheet(idx)
+		}
+	}
+	if err := recalculateOnLoad(workbook); err != nil {
+		_ = workbook.Close()
+		return nil, err
+	}
+	return workbook, nil
+}
+
+// renderTable writes a header row and the rows below it into a sheet of an in-memory workbook, styled
+// like a streamed sheet, made a table and followed by its summary rows, and returns the last data row.
+func renderTable(ctx context.Context, file *excelize.File, sheetName string, columns []StreamColumn, rows iter.Seq[[]any]) (int, error) {
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create header style: %w", err)
+	}
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+		if column.Width > 0 {
+			col, _ := excelize.ColumnNumberToName(i + 1)
+			if err := file.SetColWidth(sheetName, col, col, column.Width); err != nil {
+				return 0, fmt.Errorf("failed to set column width: %w", err)
+			}
+		}
+	}
+	if err := file.SetSheetRow(sheetName, "A1", &header); err != nil {
+		return 0, fmt.Errorf("failed to write header: %w", err)
+	}
+	if len(columns) > 0 {
+		lastHeader, _ := excelize.CoordinatesToCellName(len(columns), 1)
+		if err := file.SetCellStyle(sheetName, "A1", lastHeader, headerStyle); err != nil {
+			return 0, fmt.Errorf("failed to set header style: %w", err)
+		}
+	}
+
+	row := 1
+	for values := range rows {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		row++
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := file.SetSheetRow(sheetName, cell, &values); err != nil {
+			return 0, fmt.Errorf("failed to write row %d: %w", row, err)
+		}
+	}
+
+	for i, column := range columns {
+		if column.Style == nil || row < 2 {
+			continue
+		}
+		style, err := file.NewStyle(column.Style)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create column style: %w", err)
+		}
+		first, _ := excelize.CoordinatesToCellName(i+1, 2)
+		last, _ := excelize.CoordinatesToCellName(i+1, row)
+		if err := file.SetCellStyle(sheetName, first, last, style); err != nil {
+			return 0, fmt.Errorf("failed to set column style: %w", err)
+		}
+	}
+	if err := addSummary(file, sheetName, columns, row); err != nil {
+		return 0, err
+	}
+	if err := addTable(file, sheetName, len(columns), row); err != nil {
+		return 0, err
+	}
+	return row, nil
+}
@@ -0,0 +1,60 @@
+package xlsx
+
+import (
+	"context"
+	"html"
+	"strings"
+	"testing"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+// cpuReports returns the overview, system and user usage reports of cpus CPUs.
+func cpuReports(cpus int) (*CpuOverviewReport, *CpuSystemUsageReport, *CpuUserUsageReport) {
+	overview, system, user := &CpuOverviewReport{}, &CpuSystemUsageReport{}, &CpuUserUsageReport{}
+	for i := range cpus {
+		cpu := "cpu" + string(rune('0'+i))
+		usage := float64(i+1) / 20
+		total := 2 * usage
+		stats := &CpuUsageStats{AvgUsage: usage, MaxUsage: 2 * usage, MinUsage: usage / 2}
+		overview.Data = append(overview.Data, CpuOverviewData{CPU: cpu, System: stats, User: stats, TotalAvgUsage: &total})
+		system.Data = append(system.Data, CpuSystemUsageData{CPU: cpu, AvgUsage: usage, MaxUsage: 2 * usage, MinUsage: usage / 2})
+		user.Data = append(user.Data, CpuUserUsageData{CPU: cpu, AvgUsage: usage, MaxUsage: 2 * usage, MinUsage: usage / 2})
+	}
+	return overview, system, user
+}
+
+func TestRenderWorkbookChartsPlotTheNamedColumnsOfTheirSheet(t *testing.T) {
+	overview, system, user := cpuReports(3)
+	file, err := RenderWorkbook[Report](context.Background(), overview, system, user)
+	if err != nil {
+		t.Fatalf("RenderWorkbook() = %v", err)
+	}
+	// The dashboard moves in front of the sheets the column names are local to.
+	if err := AddDashboard(file, &DashboardReport{Header: header.Header{Title: "CPU"}, Overview: overview, System: system, User: user}); err != nil {
+		t.Fatalf("AddDashboard() = %v", err)
+	}
+	file = reopen(t, file)
+
+	charts := 0
+	file.Pkg.Range(func(key, value any) bool {
+		if !strings.HasPrefix(key.(string), "xl/charts/chart") {
+			return true
+		}
+		charts++
+		for _, match := range chartSeriesPattern.FindAllStringSubmatch(string(value.([]byte)), -1) {
+			ref := html.UnescapeString(match[1])
+			if strings.HasSuffix(ref, "$1") {
+				continue
+			}
+			sheet, _, _ := strings.Cut(ref, "!")
+			if resolved := resolveReference(file, ref); resolved == ref || !strings.HasPrefix(resolved, sheet+"!$") {
+				t.Errorf("chart series %s refers to %q, want a range of its sheet", ref, resolved)
+			}
+		}
+		return true
+	})
+	if charts != 3 {
+		t.Errorf("workbook has %d charts, want 3", charts)
+	}
+}
@@ -0,0 +1,98 @@
+package reportdef
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+)
+
+// Record is one row of a dataset, keyed by field name.
+type Record map[string]any
+
+// Dataset is a source of records that definitions can refer to by name.
+type Dataset struct {
+	Fields []string
+	fetch  func(ctx context.Context, source datasource.DataSource, dateFrom int64, dateTo int64) ([]Record, error)
+}
+
+// HasField reports whether the records of the dataset have field.
+func (dataset *Dataset) HasField(field string) bool {
+	return slices.Contains(dataset.Fields, field)
+}
+
+// datasets are the datasets known to definitions, keyed by the name used in the YAML files. Field
+// names match the JSON fixtures of the file data source.
+var datasets = map[string]*Dataset{
+	"cpu_system_usage": cpuUsageDataset(datasource.CpuSystem),
+	"cpu_user_usage":   cpuUsageDataset(datasource.CpuUser),
+	"memory_usage": {
+		Fields: []string{"metric", "avg_bytes", "max_bytes", "min_bytes"},
+		fetch: func(ctx context.Context, source datasource.DataSource, dateFrom int64, dateTo int64) ([]Record, error) {
+			usages, err := source.MemoryUsage(ctx, dateFrom, dateTo)
+			return records(usages, func(u datasource.MemoryUsage) Record {
+				return Record{"metric": u.Metric, "avg_bytes": u.AvgBytes, "max_bytes": u.MaxBytes, "min_bytes": u.MinBytes}
+			}), err
+		},
+	},
+	"disk_usage": {
+		Fields: []string{"device", "read_bytes_per_second", "write_bytes_per_second", "read_iops", "write_iops"},
+		fetch: func(ctx context.Context, source datasource.DataSource, dateFrom int64, dateTo int64) ([]Record, error) {
+			usages, err := source.DiskUsage(ctx, dateFrom, dateTo)
+			return records(usages, func(u datasource.DiskUsage) Record {
+				return Record{
+					"device":                 u.Device,
+					"read_bytes_per_second":  u.ReadBytesPerSecond,
+					"write_bytes_per_second": u.WriteBytesPerSecond,
+					"read_iops":              u.ReadIOPS,
+					"write_iops":             u.WriteIOPS,
+				}
+			}), err
+		},
+	},
+	"network_usage": {
+		Fields: []string{"interface", "rx_bytes", "tx_bytes", "rx_errors", "tx_errors"},
+		fetch: func(ctx context.Context, source datasource.DataSource, dateFrom int64, dateTo int64) ([]Record, error) {
+			usages, err := source.NetworkUsage(ctx, dateFrom, dateTo)
+			return records(usages, func(u datasource.NetworkUsage) Record {
+				return Record{"interface": u.Interface, "rx_bytes": u.RxBytes, "tx_bytes": u.TxBytes, "rx_errors": u.RxErrors, "tx_errors": u.TxErrors}
+			}), err
+		},
+	},
+}
+
+func cpuUsageDataset(kind datasource.UsageKind) *Dataset {
+	return &Dataset{
+		Fields: []string{"cpu", "avg_usage", "max_usage", "min_usage"},
+		fetch: func(ctx context.Context, source datasource.DataSource, dateFrom int64, dateTo int64) ([]Record, error) {
+			usages, err := source.CpuUsage(ctx, kind, dateFrom, dateTo)
+			return records(usages, func(u datasource.CpuUsage) Record {
+				return Record{"cpu": u.CPU, "avg_usage": u.AvgUsage, "max_usage": u.MaxUsage, "min_usage": u.MinUsage}
+			}), err
+		},
+	}
+}
+
+func records[T any](items []T, record func(T) Record) []Record {
+	result := make([]Record, len(items))
+	for i, item := range items {
+		result[i] = record(item)
+	}
+	return result
+}
+
+// Data holds the fetched records of a report, keyed by the names of its DataRefs.
+type Data map[string]datasource.Result[[]Record]
+
+// Fetch fetches every dataset of the definition concurrently, applying policy to failures.
+func Fetch(ctx context.Context, definition *Definition, source datasource.DataSource, dateFrom int64, dateTo int64, policy datasource.FailurePolicy) (Data, error) {
+	refs := make(map[string]DataRef, len(definition.Data))
+	names := make([]string, len(definition.Data))
+	for i, ref := range definition.Data {
+		refs[ref.Name] = ref
+		names[i] = ref.Name
+	}
+	return datasource.FetchEach(ctx, policy, names, func(ctx context.Context, name string) ([]Record, error) {
+		return datasets[refs[name].Dataset].fetch(ctx, source, dateFrom, dateTo)
+	})
+}
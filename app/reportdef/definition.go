@@ -0,0 +1,389 @@
+// Package reportdef interprets report definitions: YAML files that list the datasets a report reads
+// and the sections (tables, charts, KPIs and text) it renders, so that a report can be added without
+// writing a handler for it.
+package reportdef
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// Output formats a definition can be rendered to.
+const (
+	FormatXlsx = "xlsx"
+	FormatPdf  = "pdf"
+	FormatCsv  = "csv"
+	FormatHtml = "html"
+)
+
+// SupportedFormats lists every output format, in the order used when a definition does not restrict them.
+var SupportedFormats = []string{FormatXlsx, FormatPdf, FormatCsv, FormatHtml}
+
+// Section types.
+const (
+	SectionTable = "table"
+	SectionChart = "chart"
+	SectionKPI   = "kpi"
+	SectionText  = "text"
+)
+
+// Chart types of chart sections.
+const (
+	ChartBar    = "bar"
+	ChartColumn = "column"
+	ChartLine   = "line"
+	ChartArea   = "area"
+)
+
+var (
+	idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	// namePattern matches the names binding sections and KPIs to a template, which are both Excel
+	// defined names and placeholders.
+	namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+)
+
+// headerPlaceholders are the placeholders of templates filled from the report header, see XlsxTemplateData.
+var headerPlaceholders = []string{"title", "period", "generated", "provider", "request_id"}
+
+// Definition describes a report: the datasets it reads and the sections it renders, in order.
+type Definition struct {
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// Formats restricts the output formats; empty means every supported format.
+	Formats []string `yaml:"formats"`
+	// Template is the path of an XLSX template workbook the report is rendered into instead of a new
+	// workbook, relative to the definition file. Sections and KPIs fill it through their names.
+	Template string    `yaml:"template"`
+	Data     []DataRef `yaml:"data"`
+	Sections []Section `yaml:"sections"`
+}
+
+// DataRef names a dataset so that sections can refer to it.
+type DataRef struct {
+	Name    string `yaml:"name"`
+	Dataset string `yaml:"dataset"`
+}
+
+// Section is one block of the report. Which fields apply depends on Type:
+// table uses Data and Columns, chart uses Data, Chart, Category and Series, kpi uses Data and KPIs,
+// and text uses Text.
+type Section struct {
+	Type  string `yaml:"type"`
+	Title string `yaml:"title"`
+	// Name binds the section to the template: table and chart rows fill the defined name of the same
+	// name and text fills the placeholder {{name}}. KPI sections name their KPIs instead.
+	Name     string   `yaml:"name"`
+	Data     string   `yaml:"data"`
+	Columns  []Column `yaml:"columns"`
+	Chart    string   `yaml:"chart"`
+	Category string   `yaml:"category"`
+	Series   []Column `yaml:"series"`
+	KPIs     []KPI    `yaml:"kpis"`
+	Text     string   `yaml:"text"`
+}
+
+// Column maps a dataset field to a table column or a chart series.
+type Column struct {
+	Field  string `yaml:"field"`
+	Header string `yaml:"header"`
+	// Format is one of the number formats (percent, decimal, integer, gib, mib); empty renders the raw value.
+	Format string  `yaml:"format"`
+	Width  float64 `yaml:"width"`
+	// Threshold highlights the XLSX cells above its limits, in the unit of Format (0.9 is 90% in percent).
+	Threshold render_xlsx.Threshold `yaml:"threshold"`
+	// DataBar and Icons add a data bar and a traffic light icon set to the XLSX cells.
+	DataBar bool `yaml:"data_bar"`
+	Icons   bool `yaml:"icons"`
+}
+
+// KPI aggregates one field of a dataset into a single figure.
+type KPI struct {
+	Label string `yaml:"label"`
+	Field string `yaml:"field"`
+	// Aggregate is one of avg, max, min, sum or count.
+	Aggregate string `yaml:"aggregate"`
+	Format    string `yaml:"format"`
+	// Name binds the KPI to the placeholder {{name}} of the template.
+	Name string `yaml:"name"`
+}
+
+// Load reads and validates the definition in the YAML file at path.
+func Load(path string) (*Definition, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report definition: %w", err)
+	}
+	var definition Definition
+	if err := yaml.Unmarshal(content, &definition); err != nil {
+		return nil, fmt.Errorf("failed to parse report definition %s: %w", path, err)
+	}
+	if definition.Template != "" && !filepath.IsAbs(definition.Template) {
+		definition.Template = filepath.Join(filepath.Dir(path), definition.Template)
+	}
+	if err := definition.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid report definition %s: %w", path, err)
+	}
+	if definition.Template != "" {
+		info, err := render_xlsx.InspectTemplate(definition.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report definition %s: template %s: %w", path, definition.Template, err)
+		}
+		if err := definition.validateTemplate(info); err != nil {
+			return nil, fmt.Errorf("invalid report definition %s: template %s: %w", path, definition.Template, err)
+		}
+	}
+	return &definition, nil
+}
+
+// OutputFormats returns the formats the report can be rendered to.
+func (definition *Definition) OutputFormats() []string {
+	if len(definition.Formats) == 0 {
+		return SupportedFormats
+	}
+	return definition.Formats
+}
+
+// HasTableField reports whether a table section of the report has a column of field.
+func (definition *Definition) HasTableField(field string) bool {
+	for _, section := range definition.Sections {
+		if section.Type != SectionTable {
+			continue
+		}
+		for _, column := range section.Columns {
+			if column.Field == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Supports reports whether the report can be rendered to format.
+func (definition *Definition) Supports(format string) bool {
+	return slices.Contains(definition.OutputFormats(), format)
+}
+
+// Validate checks the definition against the known datasets, fields, formats and chart types.
+func (definition *Definition) Validate() error {
+	if !idPattern.MatchString(definition.ID) {
+		return fmt.Errorf("id %q must be lowercase letters, digits, '-' and '_'", definition.ID)
+	}
+	if definition.Title == "" {
+		return errors.New("title is required")
+	}
+	for _, format := range definition.Formats {
+		if !slices.Contains(SupportedFormats, format) {
+			return fmt.Errorf("unsupported format %q", format)
+		}
+	}
+	if definition.Template != "" && !definition.Supports(FormatXlsx) {
+		return errors.New("a template needs the xlsx format")
+	}
+
+	data := make(map[string]*Dataset, len(definition.Data))
+	for _, ref := range definition.Data {
+		dataset, ok := datasets[ref.Dataset]
+		if !ok {
+			return fmt.Errorf("data %q: unknown dataset %q", ref.Name, ref.Dataset)
+		}
+		if ref.Name == "" {
+			return fmt.Errorf("data of dataset %q needs a name", ref.Dataset)
+		}
+		if _, exists := data[ref.Name]; exists {
+			return fmt.Errorf("data %q is declared twice", ref.Name)
+		}
+		data[ref.Name] = dataset
+	}
+
+	if len(definition.Sections) == 0 {
+		return errors.New("at least one section is required")
+	}
+	for i, section := range definition.Sections {
+		if err := section.validate(data); err != nil {
+			return fmt.Errorf("section %d (%q): %w", i+1, section.Title, err)
+		}
+	}
+	return definition.validateNames()
+}
+
+// validateNames checks that the names of the sections and KPIs are valid and unique, ignoring case
+// as Excel does for defined names, and leave the header placeholders alone.
+func (definition *Definition) validateNames() error {
+	seen := make(map[string]bool)
+	for _, name := range headerPlaceholders {
+		seen[name] = true
+	}
+	check := func(name string) error {
+		if name == "" {
+			return nil
+		}
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("name %q must start with a letter or '_' and hold only letters, digits, '_' and '.'", name)
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("name %q is used twice or is a header placeholder", name)
+		}
+		seen[strings.ToLower(name)] = true
+		return nil
+	}
+	for i, section := range definition.Sections {
+		if section.Name != "" && section.Type == SectionKPI {
+			return fmt.Errorf("section %d (%q): a kpi section names its kpis instead", i+1, section.Title)
+		}
+		if err := check(section.Name); err != nil {
+			return fmt.Errorf("section %d (%q): %w", i+1, section.Title, err)
+		}
+		for _, kpi := range section.KPIs {
+			if err := check(kpi.Name); err != nil {
+				return fmt.Errorf("section %d (%q): kpi %q: %w", i+1, section.Title, kpi.Label, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateTemplate checks the template against the names of the definition: every placeholder must
+// be filled, and every named table and chart section must have a defined name to fill.
+func (definition *Definition) validateTemplate(info render_xlsx.TemplateInfo) error {
+	values := make(map[string]bool)
+	for _, name := range headerPlaceholders {
+		values[name] = true
+	}
+	for _, section := range definition.Sections {
+		switch section.Type {
+		case SectionText:
+			values[section.Name] = section.Name != ""
+		case SectionKPI:
+			for _, kpi := range section.KPIs {
+				values[kpi.Name] = kpi.Name != ""
+			}
+		}
+	}
+	for _, placeholder := range info.Placeholders {
+		if !values[placeholder] {
+			return fmt.Errorf("placeholder {{%s}} is not a header placeholder, nor the name of a text section or kpi", placeholder)
+		}
+	}
+	for _, section := range definition.Sections {
+		if section.Name == "" || (section.Type != SectionTable && section.Type != SectionChart) {
+			continue
+		}
+		if !slices.ContainsFunc(info.Tables, func(name string) bool { return strings.EqualFold(name, section.Name) }) {
+			return fmt.Errorf("section %q: no defined name %q refers to a single row", section.Title, section.Name)
+		}
+	}
+	return nil
+}
+
+func (section *Section) validate(data map[string]*Dataset) error {
+	if section.Title == "" {
+		return errors.New("title is required")
+	}
+	if section.Type == SectionText {
+		if section.Text == "" {
+			return errors.New("text is required")
+		}
+		return nil
+	}
+
+	dataset, ok := data[section.Data]
+	if !ok {
+		return fmt.Errorf("unknown data %q", section.Data)
+	}
+	switch section.Type {
+	case SectionTable:
+		if len(section.Columns) == 0 {
+			return errors.New("at least one column is required")
+		}
+		if err := validateColumns(dataset, section.Columns); err != nil {
+			return err
+		}
+		return validateHeaders(section.Columns)
+	case SectionChart:
+		if !slices.Contains([]string{ChartBar, ChartColumn, ChartLine, ChartArea}, section.Chart) {
+			return fmt.Errorf("unknown chart type %q", section.Chart)
+		}
+		if !dataset.HasField(section.Category) {
+			return fmt.Errorf("unknown category field %q", section.Category)
+		}
+		if len(section.Series) == 0 {
+			return errors.New("at least one series is required")
+		}
+		if err := validateColumns(dataset, section.Series); err != nil {
+			return err
+		}
+		return validateHeaders(append([]Column{{Field: section.Category}}, section.Series...))
+	case SectionKPI:
+		if len(section.KPIs) == 0 {
+			return errors.New("at least one kpi is required")
+		}
+		for _, kpi := range section.KPIs {
+			if _, ok := aggregates[kpi.Aggregate]; !ok {
+				return fmt.Errorf("kpi %q: unknown aggregate %q", kpi.Label, kpi.Aggregate)
+			}
+			if kpi.Aggregate != "count" && !dataset.HasField(kpi.Field) {
+				return fmt.Errorf("kpi %q: unknown field %q", kpi.Label, kpi.Field)
+			}
+			if _, ok := formats[kpi.Format]; !ok {
+				return fmt.Errorf("kpi %q: unknown format %q", kpi.Label, kpi.Format)
+			}
+		}
+		labels := make([]Column, len(section.KPIs))
+		for i, kpi := range section.KPIs {
+			if kpi.Label == "" {
+				return fmt.Errorf("kpi %d needs a label", i+1)
+			}
+			labels[i] = Column{Header: kpi.Label}
+		}
+		return validateHeaders(labels)
+	default:
+		return fmt.Errorf("unknown section type %q", section.Type)
+	}
+}
+
+func validateColumns(dataset *Dataset, columns []Column) error {
+	for _, column := range columns {
+		if !dataset.HasField(column.Field) {
+			return fmt.Errorf("unknown field %q", column.Field)
+		}
+		if _, ok := formats[column.Format]; !ok {
+			return fmt.Errorf("field %q: unknown format %q", column.Field, column.Format)
+		}
+		if err := column.Threshold.Validate(); err != nil {
+			return fmt.Errorf("field %q: invalid threshold: %w", column.Field, err)
+		}
+	}
+	return nil
+}
+
+// validateHeaders checks that the headers of the columns are unique, ignoring case, as the header
+// row of an XLSX table must be.
+func validateHeaders(columns []Column) error {
+	seen := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		header := strings.ToLower(column.header())
+		if seen[header] {
+			return fmt.Errorf("header %q is used twice", column.header())
+		}
+		seen[header] = true
+	}
+	return nil
+}
+
+// header returns the column header, defaulting to the field name.
+func (column Column) header() string {
+	if column.Header != "" {
+		return column.Header
+	}
+	return column.Field
+}
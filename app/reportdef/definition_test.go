@@ -0,0 +1,115 @@
+package reportdef
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// hostOverview loads the definition shipped in definitions/.
+func hostOverview(t *testing.T) *Definition {
+	t.Helper()
+	definition, err := Load(filepath.Join("..", "definitions", "host_overview.yaml"))
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	return definition
+}
+
+func TestLoadHostOverview(t *testing.T) {
+	definition := hostOverview(t)
+	if definition.ID != "host-overview" || len(definition.Sections) != 7 {
+		t.Fatalf("Load() = %q with %d sections, want host-overview with 7", definition.ID, len(definition.Sections))
+	}
+	if !definition.Supports(FormatCsv) || !definition.HasTableField("max_usage") || definition.HasTableField("read_iops") {
+		t.Errorf("Load() formats %v, table fields not as declared", definition.OutputFormats())
+	}
+}
+
+func TestLoadRejectsInvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.yaml")
+	if err := os.WriteFile(path, []byte("id: [broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("Load() = %v, want a parse error", err)
+	}
+}
+
+func TestValidateRejectsInvalidDefinitions(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		change func(definition *Definition)
+		want   string
+	}{
+		{"id", func(d *Definition) { d.ID = "Host Overview" }, "id"},
+		{"no title", func(d *Definition) { d.Title = "" }, "title is required"},
+		{"format", func(d *Definition) { d.Formats = []string{"docx"} }, `unsupported format "docx"`},
+		{"template without xlsx", func(d *Definition) { d.Formats, d.Template = []string{FormatPdf}, "template.xlsx" }, "a template needs the xlsx format"},
+		{"dataset", func(d *Definition) { d.Data[0].Dataset = "gpu_usage" }, `unknown dataset "gpu_usage"`},
+		{"unnamed data", func(d *Definition) { d.Data[0].Name = "" }, "needs a name"},
+		{"data twice", func(d *Definition) { d.Data[1].Name = d.Data[0].Name }, "declared twice"},
+		{"no sections", func(d *Definition) { d.Sections = nil }, "at least one section"},
+		{"section type", func(d *Definition) { d.Sections[1].Type = "gauge" }, `unknown section type "gauge"`},
+		{"section title", func(d *Definition) { d.Sections[2].Title = "" }, "title is required"},
+		{"empty text", func(d *Definition) { d.Sections[0].Text = "" }, "text is required"},
+		{"section data", func(d *Definition) { d.Sections[2].Data = "gpu" }, `unknown data "gpu"`},
+		{"no columns", func(d *Definition) { d.Sections[2].Columns = nil }, "at least one column"},
+		{"column field", func(d *Definition) { d.Sections[2].Columns[1].Field = "p99_usage" }, `unknown field "p99_usage"`},
+		{"column format", func(d *Definition) { d.Sections[2].Columns[1].Format = "bytes" }, `unknown format "bytes"`},
+		{"threshold", func(d *Definition) { d.Sections[2].Columns[2].Threshold.Warning = 0.95 }, "invalid threshold"},
+		{"headers", func(d *Definition) { d.Sections[2].Columns[3].Header = "max usage (%)" }, "used twice"},
+		{"chart type", func(d *Definition) { d.Sections[3].Chart = "pie" }, `unknown chart type "pie"`},
+		{"category", func(d *Definition) { d.Sections[3].Category = "core" }, `unknown category field "core"`},
+		{"no series", func(d *Definition) { d.Sections[3].Series = nil }, "at least one series"},
+		{"series header", func(d *Definition) { d.Sections[3].Series[0].Header = "cpu" }, "used twice"},
+		{"no kpis", func(d *Definition) { d.Sections[1].KPIs = nil }, "at least one kpi"},
+		{"aggregate", func(d *Definition) { d.Sections[1].KPIs[1].Aggregate = "p99" }, `unknown aggregate "p99"`},
+		{"kpi field", func(d *Definition) { d.Sections[1].KPIs[1].Field = "p99_usage" }, `unknown field "p99_usage"`},
+		{"kpi format", func(d *Definition) { d.Sections[1].KPIs[1].Format = "bytes" }, `unknown format "bytes"`},
+		{"kpi label", func(d *Definition) { d.Sections[1].KPIs[1].Label = "" }, "needs a label"},
+		{"name", func(d *Definition) { d.Sections[2].Name = "1st_table" }, "must start with a letter"},
+		{"names twice", func(d *Definition) { d.Sections[2].Name, d.Sections[4].Name = "usage", "Usage" }, "used twice"},
+		{"header placeholder", func(d *Definition) { d.Sections[0].Name = "Title" }, "header placeholder"},
+		{"kpi name", func(d *Definition) { d.Sections[1].KPIs[0].Name, d.Sections[1].KPIs[1].Name = "cpus", "CPUs" }, "used twice"},
+		{"named kpi section", func(d *Definition) { d.Sections[1].Name = "cpu" }, "names its kpis instead"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			definition := hostOverview(t)
+			test.change(definition)
+			if err := definition.Validate(); err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	definition := hostOverview(t)
+	definition.Sections[0].Name = "about"
+	definition.Sections[1].KPIs[0].Name = "cpus"
+	definition.Sections[2].Name = "system"
+	for _, test := range []struct {
+		name string
+		info render_xlsx.TemplateInfo
+		want string
+	}{
+		{"bound", render_xlsx.TemplateInfo{Placeholders: []string{"title", "about", "cpus"}, Tables: []string{"System"}}, ""},
+		{"unknown placeholder", render_xlsx.TemplateInfo{Placeholders: []string{"peak"}, Tables: []string{"system"}}, "placeholder {{peak}}"},
+		{"unnamed kpi", render_xlsx.TemplateInfo{Placeholders: []string{"Avg system usage"}, Tables: []string{"system"}}, "placeholder"},
+		{"missing table", render_xlsx.TemplateInfo{Placeholders: []string{"title"}}, `no defined name "system"`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := definition.validateTemplate(test.info)
+			if test.want == "" && err != nil {
+				t.Errorf("validateTemplate() = %v", err)
+			}
+			if test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)) {
+				t.Errorf("validateTemplate() = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
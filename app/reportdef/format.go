@@ -0,0 +1,124 @@
+package reportdef
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// format converts a field value for display. xlsx is the number format id of the XLSX cells.
+type format struct {
+	scale float64
+	xlsx  int
+	text  func(value float64) string
+}
+
+// formats are the number formats of columns, series and KPIs, keyed by name. The empty name keeps
+// the raw value.
+var formats = map[string]format{
+	"":        {},
+	"percent": {scale: 1, xlsx: 10, text: func(v float64) string { return fmt.Sprintf("%.2f%%", v*100) }},
+	"decimal": {scale: 1, xlsx: 2, text: func(v float64) string { return fmt.Sprintf("%.2f", v) }},
+	"integer": {scale: 1, xlsx: 3, text: func(v float64) string { return strconv.FormatFloat(math.Round(v), 'f', 0, 64) }},
+	"gib":     {scale: 1 << 30, xlsx: 2, text: func(v float64) string { return fmt.Sprintf("%.2f GiB", v) }},
+	"mib":     {scale: 1 << 20, xlsx: 2, text: func(v float64) string { return fmt.Sprintf("%.2f MiB", v) }},
+}
+
+// value returns the value to write to a cell: numbers scaled by the format, anything else unchanged.
+func (f format) value(raw any) any {
+	number, ok := toFloat(raw)
+	if !ok || f.scale == 0 {
+		return raw
+	}
+	return number / f.scale
+}
+
+// string renders the value as text, for formats that have no cell styles such as PDF.
+func (f format) string(raw any) string {
+	number, ok := toFloat(raw)
+	if !ok || f.text == nil {
+		return fmt.Sprint(raw)
+	}
+	return f.text(number / f.scale)
+}
+
+// style returns the XLSX style of the cells holding values of the format.
+func (f format) style() *excelize.Style {
+	return &excelize.Style{NumFmt: f.xlsx, Border: []excelize.Border{
+		{Type: "left", Color: "000000", Style: 1},
+		{Type: "top", Color: "000000", Style: 1},
+		{Type: "right", Color: "000000", Style: 1},
+		{Type: "bottom", Color: "000000", Style: 1},
+	}}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// aggregates reduce the values of a field to the figure of a KPI.
+var aggregates = map[string]func(values []float64) float64{
+	"avg": func(values []float64) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	},
+	"max": func(values []float64) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+		return slices.Max(values)
+	},
+	"min": func(values []float64) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+		return slices.Min(values)
+	},
+	"sum": func(values []float64) float64 {
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	},
+	"count": func(values []float64) float64 {
+		return float64(len(values))
+	},
+}
+
+// aggregate computes the KPI over the records.
+func (kpi KPI) aggregate(records []Record) float64 {
+	values := make([]float64, 0, len(records))
+	for _, record := range records {
+		if kpi.Aggregate == "count" {
+			values = append(values, 0)
+			continue
+		}
+		if v, ok := toFloat(record[kpi.Field]); ok {
+			values = append(values, v)
+		}
+	}
+	return aggregates[kpi.Aggregate](values)
+}
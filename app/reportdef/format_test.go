@@ -0,0 +1,59 @@
+package reportdef
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormats(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		raw    any
+		value  any
+		text   string
+	}{
+		{"", "cpu0", "cpu0", "cpu0"},
+		{"", 1.5, 1.5, "1.5"},
+		{"percent", 0.125, 0.125, "12.50%"},
+		{"decimal", float32(2.5), 2.5, "2.50"},
+		{"integer", int64(41), 41.0, "41"},
+		{"integer", 2.6, 2.6, "3"},
+		{"gib", int64(3 << 30), 3.0, "3.00 GiB"},
+		{"mib", 1.5 * (1 << 20), 1.5, "1.50 MiB"},
+		{"gib", "n/a", "n/a", "n/a"},
+	} {
+		f := formats[test.format]
+		if got := f.value(test.raw); got != test.value {
+			t.Errorf("%q value(%v) = %v, want %v", test.format, test.raw, got, test.value)
+		}
+		if got := f.string(test.raw); got != test.text {
+			t.Errorf("%q string(%v) = %q, want %q", test.format, test.raw, got, test.text)
+		}
+	}
+}
+
+func TestKPIAggregates(t *testing.T) {
+	records := []Record{{"usage": 0.5}, {"usage": int64(2)}, {"usage": "n/a"}, {"usage": 0.5}}
+	for _, test := range []struct {
+		aggregate string
+		records   []Record
+		want      float64
+	}{
+		{"avg", records, 1},
+		{"max", records, 2},
+		{"min", records, 0.5},
+		{"sum", records, 3},
+		// count counts records, whether or not the field holds a number.
+		{"count", records, 4},
+		{"avg", nil, 0},
+		{"max", nil, 0},
+		{"min", nil, 0},
+		{"sum", nil, 0},
+		{"count", nil, 0},
+	} {
+		kpi := KPI{Field: "usage", Aggregate: test.aggregate}
+		if got := kpi.aggregate(test.records); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s of %d records = %v, want %v", test.aggregate, len(test.records), got, test.want)
+		}
+	}
+}
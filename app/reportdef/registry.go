@@ -0,0 +1,71 @@
+package reportdef
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Registry holds the report definitions discovered at startup, by id.
+type Registry struct {
+	definitions map[string]*Definition
+	ids         []string
+}
+
+// NewRegistry registers the definitions, rejecting duplicate ids.
+func NewRegistry(definitions ...*Definition) (*Registry, error) {
+	registry := &Registry{definitions: make(map[string]*Definition, len(definitions))}
+	for _, definition := range definitions {
+		if _, exists := registry.definitions[definition.ID]; exists {
+			return nil, fmt.Errorf("report definition %q is declared twice", definition.ID)
+		}
+		registry.definitions[definition.ID] = definition
+		registry.ids = append(registry.ids, definition.ID)
+	}
+	slices.Sort(registry.ids)
+	return registry, nil
+}
+
+// LoadDir loads every *.yaml and *.yml file of dir. A missing directory yields an empty registry,
+// while an invalid definition fails the whole load so that it is noticed at startup.
+func LoadDir(dir string) (*Registry, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewRegistry()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report definitions: %w", err)
+	}
+	var definitions []*Definition
+	for _, entry := range entries {
+		extension := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (extension != ".yaml" && extension != ".yml") {
+			continue
+		}
+		definition, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return NewRegistry(definitions...)
+}
+
+// Get returns the definition with id, if any.
+func (registry *Registry) Get(id string) (*Definition, bool) {
+	definition, ok := registry.definitions[id]
+	return definition, ok
+}
+
+// List returns every definition, ordered by id.
+func (registry *Registry) List() []*Definition {
+	definitions := make([]*Definition, len(registry.ids))
+	for i, id := range registry.ids {
+		definitions[i] = registry.definitions[id]
+	}
+	return definitions
+}
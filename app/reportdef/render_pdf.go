@@ -0,0 +1,86 @@
+package reportdef
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_pdf "github.com/Javier-Godon/reports-rendering-go/render/pdf"
+)
+
+// RenderPdf renders the title page showing h and every section of the definition into a PDF document.
+func RenderPdf(ctx context.Context, definition *Definition, data Data, h header.Header) ([]byte, error) {
+	doc := render_pdf.NewDocument(h)
+	if err := render_pdf.RenderTitlePage(doc, h); err != nil {
+		return nil, err
+	}
+	doc.AddPage()
+	for _, section := range definition.Sections {
+		result := data[section.Data]
+		var err error
+		switch section.Type {
+		case SectionTable:
+			columns := make([]render_pdf.TableColumn, len(section.Columns))
+			for i, column := range section.Columns {
+				columns[i] = render_pdf.TableColumn{Header: column.header(), Width: column.Width, Align: pdfAlign(column)}
+			}
+			rows := make([][]string, len(result.Data))
+			for i, record := range result.Data {
+				rows[i] = make([]string, len(section.Columns))
+				for j, column := range section.Columns {
+					rows[i][j] = formats[column.Format].string(record[column.Field])
+				}
+			}
+			err = (&render_pdf.TableSection{Title: section.Title, Columns: columns, Rows: rows, Unavailable: result.Unavailable()}).Render(ctx, doc)
+		case SectionChart:
+			err = pdfChart(section, result).Render(ctx, doc)
+		case SectionKPI:
+			kpis := make([]render_pdf.KPI, len(section.KPIs))
+			for i, kpi := range section.KPIs {
+				kpis[i] = render_pdf.KPI{Label: kpi.Label, Value: formats[kpi.Format].string(kpi.aggregate(result.Data))}
+			}
+			err = (&render_pdf.KPISection{Title: section.Title, KPIs: kpis, Unavailable: result.Unavailable()}).Render(ctx, doc)
+		case SectionText:
+			err = (&render_pdf.TextSection{Title: section.Title, Text: section.Text}).Render(ctx, doc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to render section %q: %w", section.Title, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfChart plots every series of a chart section, scaled by its format.
+func pdfChart(section Section, result datasource.Result[[]Record]) *render_pdf.ChartSection {
+	chart := &render_pdf.ChartSection{Title: section.Title, Type: section.Chart, CategoryLabel: section.Category, Unavailable: result.Unavailable()}
+	for _, record := range result.Data {
+		chart.Categories = append(chart.Categories, fmt.Sprint(record[section.Category]))
+	}
+	// The value axis is in the format of the first series, like the XLSX chart.
+	chart.ValueFormat = formats[section.Series[0].Format].text
+	for _, column := range section.Series {
+		f := formats[column.Format]
+		series := render_pdf.ChartSeries{Name: column.header()}
+		for _, record := range result.Data {
+			value, _ := toFloat(f.value(record[column.Field]))
+			series.Values = append(series.Values, value)
+		}
+		chart.Series = append(chart.Series, series)
+	}
+	return chart
+}
+
+// pdfAlign right-aligns formatted numbers and left-aligns everything else.
+func pdfAlign(column Column) string {
+	if column.Format != "" {
+		return "R"
+	}
+	return "L"
+}
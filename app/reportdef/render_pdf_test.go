@@ -0,0 +1,57 @@
+package reportdef
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+)
+
+func TestRenderPdf(t *testing.T) {
+	pdf, err := RenderPdf(context.Background(), hostOverview(t), hostData(), header.Header{Title: "Host Overview"})
+	if err != nil {
+		t.Fatalf("RenderPdf() = %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Fatalf("RenderPdf() did not write a PDF")
+	}
+	if got := bytes.Count(pdf, []byte("/Subtype /Image")); got != 2 {
+		t.Errorf("RenderPdf() embedded %d images, want one per chart section", got)
+	}
+}
+
+func TestRenderPdfStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := RenderPdf(ctx, hostOverview(t), hostData(), header.Header{Title: "Host Overview"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("RenderPdf() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestPdfChartPlotsEverySeries(t *testing.T) {
+	definition := hostOverview(t)
+	chart := pdfChart(definition.Sections[5], hostData()["disk"])
+	if !slices.Equal(chart.Categories, []string{"sda", "sdb"}) || chart.CategoryLabel != "device" || chart.Type != ChartBar {
+		t.Errorf("chart = %+v, want a bar chart of sda and sdb by device", chart)
+	}
+	if len(chart.Series) != 2 {
+		t.Fatalf("chart has %d series, want 2", len(chart.Series))
+	}
+	for i, want := range [][]float64{{1, 3}, {2, 4}} {
+		if !slices.Equal(chart.Series[i].Values, want) {
+			t.Errorf("series %q = %v, want %v MiB/s", chart.Series[i].Name, chart.Series[i].Values, want)
+		}
+	}
+	if got := chart.ValueFormat(1.5); got != "1.50 MiB" {
+		t.Errorf("ValueFormat(1.5) = %q, want %q", got, "1.50 MiB")
+	}
+
+	unavailable := pdfChart(definition.Sections[3], datasource.Result[[]Record]{Err: errors.New("provider down")})
+	if unavailable.Unavailable != datasource.UnavailableReason || len(unavailable.Series) != 2 {
+		t.Errorf("chart of unavailable data = %+v, want the placeholder reason", unavailable)
+	}
+}
@@ -0,0 +1,169 @@
+package reportdef
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+const maxSheetNameLength = 31
+
+var chartTypes = map[string]excelize.ChartType{
+	ChartBar:    excelize.Bar,
+	ChartColumn: excelize.Col,
+	ChartLine:   excelize.Line,
+	ChartArea:   excelize.Area,
+}
+
+// XlsxSheets maps every section of the definition to a sheet named after the section title, never
+// render_xlsx.HeaderSheetName, which is left to the header sheet. thresholds, keyed by field, replace
+// the thresholds the definition gives to table columns.
+func XlsxSheets(definition *Definition, data Data, thresholds render_xlsx.Thresholds) []render_xlsx.Report {
+	sheets := make([]render_xlsx.Report, len(definition.Sections))
+	used := map[string]bool{strings.ToLower(render_xlsx.HeaderSheetName): true}
+	for i, section := range definition.Sections {
+		sheet := &render_xlsx.TableSheet{Name: sheetName(section.Title, used)}
+		result := data[section.Data]
+		sheet.Unavailable = result.Unavailable()
+		switch section.Type {
+		case SectionTable:
+			sheet.Fields, sheet.Data = xlsxTable(section.Columns, result.Data)
+			for j, column := range section.Columns {
+				sheet.Fields[j].Highlight = column.highlight(thresholds)
+			}
+		case SectionChart:
+			columns := append([]Column{{Field: section.Category}}, section.Series...)
+			sheet.Fields, sheet.Data = xlsxTable(columns, result.Data)
+			series := make([]int, len(section.Series))
+			for j := range series {
+				series[j] = j + 2
+			}
+			sheet.Chart = &render_xlsx.TableChart{Type: chartTypes[section.Chart], Title: section.Title, YAxis: section.Series[0].header(), Series: series}
+		case SectionKPI:
+			// One column per KPI, so that each figure keeps its own number format.
+			row := make([]any, len(section.KPIs))
+			for j, kpi := range section.KPIs {
+				f := formats[kpi.Format]
+				sheet.Fields = append(sheet.Fields, render_xlsx.StreamColumn{Header: kpi.Label, Width: 20, Style: f.style()})
+				row[j] = f.value(kpi.aggregate(result.Data))
+			}
+			sheet.Data = [][]any{row}
+		case SectionText:
+			sheet.Fields = []render_xlsx.StreamColumn{{Header: section.Title, Width: 100, Style: &excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"}}}}
+			for _, paragraph := range strings.Split(strings.TrimSpace(section.Text), "\n\n") {
+				sheet.Data = append(sheet.Data, []any{strings.Join(strings.Fields(paragraph), " ")})
+			}
+		}
+		sheets[i] = sheet
+	}
+	return sheets
+}
+
+func xlsxTable(columns []Column, records []Record) ([]render_xlsx.StreamColumn, [][]any) {
+	fields := make([]render_xlsx.StreamColumn, len(columns))
+	for i, column := range columns {
+		width := column.Width
+		if width == 0 {
+			width = 18
+		}
+		// Only the formatted columns are known to hold numbers worth summarizing.
+		fields[i] = render_xlsx.StreamColumn{Header: column.header(), Width: width, Style: formats[column.Format].style(), Summarize: column.Format != ""}
+	}
+	rows := make([][]any, len(records))
+	for i, record := range records {
+		row := make([]any, len(columns))
+		for j, column := range columns {
+			row[j] = formats[column.Format].value(record[column.Field])
+		}
+		rows[i] = row
+	}
+	return fields, rows
+}
+
+// highlight returns the conditional formats of the column, if it has any.
+func (column Column) highlight(thresholds render_xlsx.Thresholds) *render_xlsx.ColumnHighlight {
+	threshold, ok := thresholds[column.Field]
+	if !ok {
+		threshold = column.Threshold
+	}
+	if threshold == (render_xlsx.Threshold{}) && !column.DataBar && !column.Icons {
+		return nil
+	}
+	return &render_xlsx.ColumnHighlight{Threshold: threshold, DataBar: column.DataBar, Icons: column.Icons}
+}
+
+// sheetName turns a section title into a valid worksheet name, unique ignoring case like Excel.
+func sheetName(title string, used map[string]bool) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, strings.Trim(title, "'"))
+	name = truncate(name, maxSheetNameLength)
+	unique := name
+	for n := 2; used[strings.ToLower(unique)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncate(name, maxSheetNameLength-len(suffix)) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
+
+// XlsxTemplateData binds the report to its template: the header placeholders, the text sections and
+// KPIs by name, and the rows of the table and chart sections by name, a chart section giving its
+// category and series columns. Unnamed sections are left out.
+func XlsxTemplateData(definition *Definition, data Data, h header.Header) render_xlsx.TemplateData {
+	values := map[string]render_xlsx.TemplateValue{
+		"title":      {Value: h.Title, Text: h.Title},
+		"period":     {Value: h.Period(), Text: h.Period()},
+		"generated":  {Value: h.Generated(), Text: h.Generated()},
+		"provider":   {Value: h.Source(), Text: h.Source()},
+		"request_id": {Value: h.RequestID, Text: h.RequestID},
+	}
+	tables := make(map[string][][]any)
+	for _, section := range definition.Sections {
+		result := data[section.Data]
+		switch section.Type {
+		case SectionTable:
+			if section.Name != "" {
+				_, tables[section.Name] = xlsxTable(section.Columns, result.Data)
+			}
+		case SectionChart:
+			if section.Name != "" {
+				_, tables[section.Name] = xlsxTable(append([]Column{{Field: section.Category}}, section.Series...), result.Data)
+			}
+		case SectionKPI:
+			for _, kpi := range section.KPIs {
+				if kpi.Name == "" {
+					continue
+				}
+				f := formats[kpi.Format]
+				figure := kpi.aggregate(result.Data)
+				values[kpi.Name] = render_xlsx.TemplateValue{Value: f.value(figure), Text: f.string(figure)}
+			}
+		case SectionText:
+			if section.Name != "" {
+				var paragraphs []string
+				for _, paragraph := range strings.Split(strings.TrimSpace(section.Text), "\n\n") {
+					paragraphs = append(paragraphs, strings.Join(strings.Fields(paragraph), " "))
+				}
+				text := strings.Join(paragraphs, "\n")
+				values[section.Name] = render_xlsx.TemplateValue{Value: text, Text: text}
+			}
+		}
+	}
+	return render_xlsx.TemplateData{Values: values, Tables: tables}
+}
@@ -0,0 +1,166 @@
+package reportdef
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// hostData is the data of the host overview, with the network dataset unavailable.
+func hostData() Data {
+	cpus := []Record{
+		{"cpu": "cpu0", "avg_usage": 0.25, "max_usage": 0.75, "min_usage": 0.05},
+		{"cpu": "cpu1", "avg_usage": 0.5, "max_usage": 0.95, "min_usage": 0.1},
+	}
+	return Data{
+		"system": {Data: cpus},
+		"user":   {Data: cpus},
+		"memory": {Data: []Record{{"metric": "used", "avg_bytes": int64(2 << 30), "max_bytes": int64(3 << 30), "min_bytes": int64(1 << 30)}}},
+		"disk": {Data: []Record{
+			{"device": "sda", "read_bytes_per_second": float64(1 << 20), "write_bytes_per_second": float64(2 << 20)},
+			{"device": "sdb", "read_bytes_per_second": float64(3 << 20), "write_bytes_per_second": float64(4 << 20)},
+		}},
+		"network": {Err: errors.New("provider down")},
+	}
+}
+
+func TestXlsxSheets(t *testing.T) {
+	thresholds := render_xlsx.Thresholds{"max_usage": {Warning: 0.5, Critical: 0.8}}
+	sheets := XlsxSheets(hostOverview(t), hostData(), thresholds)
+	for _, test := range []struct {
+		name    string
+		headers []string
+		rows    [][]any
+		series  []int
+	}{
+		{name: "About this report", headers: []string{"About this report"}, rows: [][]any{
+			{"Average, maximum and minimum usage of the host resources over the requested period."},
+			{"CPU usage is a ratio of the time spent in system and user mode; memory is in GiB, disk throughput in MiB per second and network traffic in GiB."},
+		}},
+		{name: "CPU", headers: []string{"CPUs", "Avg system usage", "Peak system usage"}, rows: [][]any{{2.0, 0.375, 0.95}}},
+		{name: "CPU System Usage", headers: []string{"CPU", "Average Usage (%)", "Max Usage (%)", "Min Usage (%)"}, rows: [][]any{
+			{"cpu0", 0.25, 0.75, 0.05}, {"cpu1", 0.5, 0.95, 0.1},
+		}},
+		{name: "CPU User Usage", headers: []string{"cpu", "Average Usage (%)", "Max Usage (%)"}, rows: [][]any{
+			{"cpu0", 0.25, 0.75}, {"cpu1", 0.5, 0.95},
+		}, series: []int{2, 3}},
+		{name: "Memory Usage", headers: []string{"Metric", "Average (GiB)", "Max (GiB)", "Min (GiB)"}, rows: [][]any{{"used", 2.0, 3.0, 1.0}}},
+		{name: "Disk Throughput", headers: []string{"device", "Read (MiB/s)", "Write (MiB/s)"}, rows: [][]any{
+			{"sda", 1.0, 2.0}, {"sdb", 3.0, 4.0},
+		}, series: []int{2, 3}},
+		{name: "Network Usage", headers: []string{"Interface", "Received (GiB)", "Sent (GiB)", "Receive Errors", "Send Errors"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			i := slices.IndexFunc(sheets, func(sheet render_xlsx.Report) bool { return sheet.SheetName() == test.name })
+			if i < 0 {
+				t.Fatalf("no sheet %q", test.name)
+			}
+			sheet := sheets[i].(*render_xlsx.TableSheet)
+			var headers []string
+			for _, field := range sheet.Fields {
+				headers = append(headers, field.Header)
+			}
+			if !slices.Equal(headers, test.headers) {
+				t.Errorf("headers = %q, want %q", headers, test.headers)
+			}
+			if !slices.EqualFunc(sheet.Data, test.rows, slices.Equal) {
+				t.Errorf("rows = %v, want %v", sheet.Data, test.rows)
+			}
+			if sheet.Chart == nil && test.series != nil || sheet.Chart != nil && !slices.Equal(sheet.Chart.Series, test.series) {
+				t.Errorf("chart = %+v, want series %v", sheet.Chart, test.series)
+			}
+		})
+	}
+
+	system := sheets[2].(*render_xlsx.TableSheet)
+	if got := system.Fields[2].Highlight; got == nil || got.Threshold != thresholds["max_usage"] || !got.Icons {
+		t.Errorf("max usage highlight = %+v, want the request thresholds with icons", got)
+	}
+	if got := system.Fields[1].Style.NumFmt; got != 10 {
+		t.Errorf("average usage number format = %d, want 10", got)
+	}
+	if chart := sheets[5].(*render_xlsx.TableSheet).Chart; chart.Type != excelize.Bar || chart.YAxis != "Read (MiB/s)" {
+		t.Errorf("disk chart = %+v, want a bar chart of Read (MiB/s)", chart)
+	}
+	if got := sheets[6].(*render_xlsx.TableSheet).Unavailable; got != datasource.UnavailableReason {
+		t.Errorf("network sheet unavailable = %q, want %q", got, datasource.UnavailableReason)
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	used := map[string]bool{"report": true}
+	for _, test := range []struct {
+		title, want string
+	}{
+		{"CPU: system/user [avg]", "CPU- system-user -avg-"},
+		{"'Quoted'", "Quoted"},
+		{"A title well over thirty-one characters", "A title well over thirty-one ch"},
+		{"A title well over thirty-one characters too", "A title well over thirty-on (2)"},
+		{"Report", "Report (2)"},
+		{"REPORT", "REPORT (3)"},
+	} {
+		if got := sheetName(test.title, used); got != test.want {
+			t.Errorf("sheetName(%q) = %q, want %q", test.title, got, test.want)
+		}
+	}
+}
+
+func TestXlsxSheetsNamesSheetsUniquelyIgnoringCase(t *testing.T) {
+	definition := &Definition{Sections: []Section{
+		{Type: SectionText, Title: "Notes", Text: "first"},
+		{Type: SectionText, Title: "notes", Text: "second"},
+		{Type: SectionText, Title: "report", Text: "third"},
+	}}
+	var names []string
+	for _, sheet := range XlsxSheets(definition, Data{}, nil) {
+		names = append(names, sheet.SheetName())
+	}
+	if want := []string{"Notes", "notes (2)", "report (2)"}; !slices.Equal(names, want) {
+		t.Errorf("XlsxSheets() sheets = %q, want %q", names, want)
+	}
+}
+
+func TestXlsxTemplateData(t *testing.T) {
+	definition := hostOverview(t)
+	definition.Sections[0].Name = "about"
+	definition.Sections[1].KPIs[1].Name = "avg_system"
+	definition.Sections[4].Name = "memory"
+	definition.Sections[5].Name = "disk"
+	h := header.Header{Title: "Host Overview", DateFrom: 0, DateTo: 3600, GeneratedAt: time.Unix(7200, 0), RequestID: "r-1"}
+	data := XlsxTemplateData(definition, hostData(), h)
+
+	for name, want := range map[string]render_xlsx.TemplateValue{
+		"title":      {Value: "Host Overview", Text: "Host Overview"},
+		"request_id": {Value: "r-1", Text: "r-1"},
+		"avg_system": {Value: 0.375, Text: "37.50%"},
+		"about": {
+			Value: "Average, maximum and minimum usage of the host resources over the requested period.\nCPU usage is a ratio of the time spent in system and user mode; memory is in GiB, disk throughput in MiB per second and network traffic in GiB.",
+			Text:  "Average, maximum and minimum usage of the host resources over the requested period.\nCPU usage is a ratio of the time spent in system and user mode; memory is in GiB, disk throughput in MiB per second and network traffic in GiB.",
+		},
+	} {
+		if got := data.Values[name]; got != want {
+			t.Errorf("value %q = %+v, want %+v", name, got, want)
+		}
+	}
+	if _, ok := data.Values["CPUs"]; ok || len(data.Values) != 7 {
+		t.Errorf("values = %v, want the 5 header placeholders, about and avg_system", data.Values)
+	}
+	for name, want := range map[string][][]any{
+		"memory": {{"used", 2.0, 3.0, 1.0}},
+		"disk":   {{"sda", 1.0, 2.0}, {"sdb", 3.0, 4.0}},
+	} {
+		if got := data.Tables[name]; !slices.EqualFunc(got, want, slices.Equal) {
+			t.Errorf("table %q = %v, want %v", name, got, want)
+		}
+	}
+	if len(data.Tables) != 2 {
+		t.Errorf("tables = %v, want memory and disk only", data.Tables)
+	}
+}
@@ -0,0 +1,12 @@
+package rest
+
+type HealthResponse struct {
+	Status       string                  `json:"status"`
+	DataProvider *DataProviderHealthInfo `json:"data_provider,omitempty"`
+}
+
+type DataProviderHealthInfo struct {
+	Address     string   `json:"address"`
+	Ready       bool     `json:"ready"`
+	Connections []string `json:"connections"`
+}
@@ -0,0 +1,30 @@
+package rest
+
+import (
+	"net/http"
+
+	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteHealth exposes the connectivity of the data provider pool. pool is nil when reports do not read
+// from the gRPC data provider.
+func RouteHealth(route *gin.Engine, pool *proto.GRPCClientPool) (routes gin.IRoutes) {
+	HealthRoute := route.GET("/health", func(ctx *gin.Context) {
+		response := HealthResponse{Status: "ok"}
+		if pool != nil {
+			info := &DataProviderHealthInfo{Address: pool.Address(), Ready: pool.Ready()}
+			for _, state := range pool.States() {
+				info.Connections = append(info.Connections, state.String())
+			}
+			response.DataProvider = info
+			if !info.Ready {
+				response.Status = "degraded"
+				ctx.JSON(http.StatusServiceUnavailable, response)
+				return
+			}
+		}
+		ctx.JSON(http.StatusOK, response)
+	})
+	return HealthRoute
+}
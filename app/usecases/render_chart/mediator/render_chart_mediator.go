@@ -0,0 +1,21 @@
+package mediator
+
+import (
+	"context"
+	"log"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_chart"
+)
+
+func Register() error {
+	return framework.RegisterWithContext[render_chart.RenderChartQuery, render_chart.RenderChartResult](render_chart.NewRenderChartHandler())
+}
+
+func Send(ctx context.Context, query render_chart.RenderChartQuery) (render_chart.RenderChartResult, error) {
+	RenderChartResult, err := framework.SendWithContext[render_chart.RenderChartQuery, render_chart.RenderChartResult](ctx, query)
+	if err != nil {
+		log.Printf("Could not execute %+v: %v", query, err)
+	}
+	return RenderChartResult, err
+}
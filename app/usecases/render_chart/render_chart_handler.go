@@ -0,0 +1,30 @@
+package render_chart
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+)
+
+// RenderChartHandler draws charts from the data of the request, without reading any data source.
+type RenderChartHandler struct{}
+
+func NewRenderChartHandler() *RenderChartHandler {
+	return &RenderChartHandler{}
+}
+
+func (handler RenderChartHandler) Handle(ctx context.Context, query RenderChartQuery) (RenderChartResult, error) {
+	var buf bytes.Buffer
+	if query.Format == FormatSvg {
+		if err := query.Chart.SVG(&buf); err != nil {
+			return RenderChartResult{}, framework.NewRenderError("error rendering chart", err)
+		}
+		return RenderChartResult{ContentType: chart.ContentTypeSVG, Payload: buf.Bytes()}, nil
+	}
+	if err := query.Chart.PNG(&buf); err != nil {
+		return RenderChartResult{}, framework.NewRenderError("error rendering chart", err)
+	}
+	return RenderChartResult{ContentType: chart.ContentTypePNG, Payload: buf.Bytes()}, nil
+}
@@ -0,0 +1,27 @@
+package render_chart
+
+import (
+	"fmt"
+
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+)
+
+// Image formats a chart can be rendered to.
+const (
+	FormatPng = "png"
+	FormatSvg = "svg"
+)
+
+// RenderChartQuery renders the data of the request as a chart image.
+type RenderChartQuery struct {
+	Chart  chart.Chart `json:"chart"`
+	Format string      `json:"format" binding:"required"`
+}
+
+// Validate checks the image format and that the chart can be drawn.
+func (query RenderChartQuery) Validate() error {
+	if query.Format != FormatPng && query.Format != FormatSvg {
+		return fmt.Errorf("unknown image format %q", query.Format)
+	}
+	return query.Chart.Validate()
+}
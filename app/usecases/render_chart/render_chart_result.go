@@ -0,0 +1,6 @@
+package render_chart
+
+type RenderChartResult struct {
+	ContentType string `json:"content_type"`
+	Payload     []byte `json:"payload" binding:"required"`
+}
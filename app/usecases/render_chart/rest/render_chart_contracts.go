@@ -0,0 +1,23 @@
+package rest
+
+import "github.com/Javier-Godon/reports-rendering-go/render/chart"
+
+type RenderChartRequest struct {
+	Title      string         `json:"title"`
+	XAxis      string         `json:"x_axis"`
+	YAxis      string         `json:"y_axis"`
+	Categories []string       `json:"categories" binding:"required"`
+	Series     []chart.Series `json:"series" binding:"required"`
+	// Legend is top, bottom (default), left, right or none.
+	Legend string `json:"legend"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// ValueFormat is percent for ratios shown as percentages, or empty for plain numbers.
+	ValueFormat string `json:"value_format"`
+	// Format is png (default) or svg.
+	Format string `json:"format"`
+}
+
+type RenderChartResponse struct {
+	Payload []byte `json:"payload" binding:"required"`
+}
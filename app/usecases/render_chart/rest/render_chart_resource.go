@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/render/chart"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_chart"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_chart/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteRenderChart draws the chart of the request body: POST /render/chart/{type}, where type is bar,
+// stacked_bar, line, area or heatmap. The image is returned inline.
+func RouteRenderChart(route *gin.Engine) (routes gin.IRoutes) {
+	RenderChartRoute := route.POST("/render/chart/:type", func(ctx *gin.Context) {
+		var request RenderChartRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderChartQuery(chart.Type(ctx.Param("type")), request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		RenderChartResult, err := mediator.Send(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if framework.WantsJSON(ctx, RenderChartResult.ContentType) {
+			ctx.JSON(http.StatusOK, RenderChartResponse{Payload: RenderChartResult.Payload})
+			return
+		}
+		ctx.Data(http.StatusOK, RenderChartResult.ContentType, RenderChartResult.Payload)
+	})
+	return RenderChartRoute
+}
+
+func buildRenderChartQuery(chartType chart.Type, request RenderChartRequest) (render_chart.RenderChartQuery, error) {
+	query := render_chart.RenderChartQuery{
+		Chart: chart.Chart{
+			Type:       chartType,
+			Title:      request.Title,
+			XAxis:      request.XAxis,
+			YAxis:      request.YAxis,
+			Categories: request.Categories,
+			Series:     request.Series,
+			Legend:     chart.LegendPosition(request.Legend),
+			Width:      request.Width,
+			Height:     request.Height,
+		},
+		Format: request.Format,
+	}
+	if query.Format == "" {
+		query.Format = render_chart.FormatPng
+	}
+	switch request.ValueFormat {
+	case "":
+	case "percent":
+		query.Chart.ValueFormat = chart.FormatPercent
+	default:
+		return query, framework.NewValidationError(fmt.Sprintf("unknown value format %q", request.ValueFormat), nil)
+	}
+	return query, nil
+}
@@ -0,0 +1,8 @@
+package render_full_csv
+
+type RenderFullCsvResult struct {
+	// ContentType and Extension depend on the layout and the delimiter: a zip archive, CSV or TSV.
+	ContentType string `json:"content_type"`
+	Extension   string `json:"extension"`
+	Payload     []byte `json:"payload" binding:"required"`
+}
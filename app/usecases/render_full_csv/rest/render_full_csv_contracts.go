@@ -0,0 +1,26 @@
+package rest
+
+import (
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_csv "github.com/Javier-Godon/reports-rendering-go/render/csv"
+)
+
+type RenderFullCsvRequest struct {
+	DateFrom int32 `json:"date_from" binding:"required" description:"Start of the period, in unix seconds"`
+	DateTo   int32 `json:"date_to" binding:"required" description:"End of the period, in unix seconds"`
+	// Step of the optional timeline sections, as a duration such as "1m", "5m" or "1h".
+	Step string `json:"step" description:"Adds CPU timeline sections bucketed by this duration, e.g. 5m or 1h (at least 1m)"`
+	render_csv.RequestOptions
+}
+
+// renderFullCsvReport describes the report in the catalog.
+var renderFullCsvReport = framework.ReportType{
+	ID:          "full-csv",
+	Title:       "Host usage tables",
+	Description: "The sheets of the host usage workbook as CSV or TSV, zipped per section or in a single file.",
+	Formats:     []string{"csv"},
+}
+
+type RenderFullCsvResponse struct {
+	Payload []byte `json:"payload" binding:"required"`
+}
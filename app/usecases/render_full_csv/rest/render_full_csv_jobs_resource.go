@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_csv/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRenderFullCsvJobs(route *gin.Engine, jobs *framework.JobManager, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/csv/jobs"
+	report := renderFullCsvReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "csv", Method: http.MethodPost, Path: path, Async: true}}
+	catalog.Register(report, RenderFullCsvRequest{})
+
+	RenderFullCsvJobsRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullCsvRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullCsvQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		// The job outlives the request, but its report still names the request ID.
+		requestID := framework.RequestID(ctx.Request.Context())
+		job, err := jobs.Submit("csv", func(jobCtx context.Context) (framework.JobArtifact, error) {
+			result, err := mediator.Send(framework.WithRequestID(jobCtx, requestID), query)
+			if err != nil {
+				return framework.JobArtifact{}, err
+			}
+			fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), result.Extension)
+			return framework.JobArtifact{ContentType: result.ContentType, FileName: fileName, Payload: result.Payload}, nil
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Location", "/jobs/"+job.ID)
+		ctx.JSON(http.StatusAccepted, job)
+	})
+	return RenderFullCsvJobsRoute
+}
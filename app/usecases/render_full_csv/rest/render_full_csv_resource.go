@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_csv"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_csv/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRenderFullCsv(route *gin.Engine, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/csv/"
+	report := renderFullCsvReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "csv", Method: http.MethodPost, Path: path}}
+	catalog.Register(report, RenderFullCsvRequest{})
+
+	RenderFullCsvRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullCsvRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullCsvQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		RenderFullCsvResult, err := mediator.Send(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if framework.WantsJSON(ctx, RenderFullCsvResult.ContentType) {
+			ctx.JSON(http.StatusOK, RenderFullCsvResponse{Payload: RenderFullCsvResult.Payload})
+			return
+		}
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), RenderFullCsvResult.Extension)
+		framework.RespondWithAttachment(ctx, RenderFullCsvResult.ContentType, fileName, RenderFullCsvResult.Payload)
+	})
+	return RenderFullCsvRoute
+}
+
+func buildRenderFullCsvQuery(request RenderFullCsvRequest) (render_full_csv.RenderFullCsvQuery, error) {
+	query := render_full_csv.RenderFullCsvQuery{
+		DateFrom: request.DateFrom,
+		DateTo:   request.DateTo,
+	}
+	if request.Step != "" {
+		step, err := time.ParseDuration(request.Step)
+		if err != nil {
+			return query, framework.NewValidationError("invalid step", err)
+		}
+		query.Step = step
+	}
+	options, err := request.RequestOptions.Parse()
+	if err != nil {
+		return query, framework.NewValidationError("invalid csv options", err)
+	}
+	query.Options = options
+	return query, nil
+}
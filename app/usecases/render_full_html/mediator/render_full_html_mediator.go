@@ -0,0 +1,25 @@
+package mediator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html"
+)
+
+// Register registers the render full html handler, reading its data from source, applying policy
+// when some of it cannot be fetched and showing the times of the report header in location.
+func Register(source datasource.DataSource, policy datasource.FailurePolicy, location *time.Location) error {
+	return framework.RegisterWithContext[render_full_html.RenderFullHtmlQuery, render_full_html.RenderFullHtmlResult](render_full_html.NewRenderFullHtmlHandler(source, policy, location))
+}
+
+func Send(ctx context.Context, query render_full_html.RenderFullHtmlQuery) (render_full_html.RenderFullHtmlResult, error) {
+	RenderFullHtmlResult, err := framework.SendWithContext[render_full_html.RenderFullHtmlQuery, render_full_html.RenderFullHtmlResult](ctx, query)
+	if err != nil {
+		log.Printf("Could not execute %+v: %v", query, err)
+	}
+	return RenderFullHtmlResult, err
+}
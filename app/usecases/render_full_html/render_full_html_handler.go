@@ -0,0 +1,207 @@
+package render_full_html
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_html "github.com/Javier-Godon/reports-rendering-go/render/html"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+type RenderFullHtmlHandler struct {
+	source   datasource.DataSource
+	policy   datasource.FailurePolicy
+	location *time.Location
+}
+
+// NewRenderFullHtmlHandler creates the handler. The report header shows times in location.
+func NewRenderFullHtmlHandler(source datasource.DataSource, policy datasource.FailurePolicy, location *time.Location) *RenderFullHtmlHandler {
+	return &RenderFullHtmlHandler{source: source, policy: policy, location: location}
+}
+
+// Handle renders the sections of the full XLSX report as a self-contained HTML document. The sections
+// are the XLSX reports themselves, so that both formats have the same tables and charts.
+func (handler RenderFullHtmlHandler) Handle(ctx context.Context, query RenderFullHtmlQuery) (RenderFullHtmlResult, error) {
+	usages, err := datasource.FetchCpuUsages(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy, datasource.CpuSystem, datasource.CpuUser)
+	if err != nil {
+		return RenderFullHtmlResult{}, err
+	}
+	systemUsage, userUsage := usages[datasource.CpuSystem], usages[datasource.CpuUser]
+
+	host, err := datasource.FetchHostUsage(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy)
+	if err != nil {
+		return RenderFullHtmlResult{}, err
+	}
+
+	sections := []render_xlsx.Report{
+		&render_xlsx.CpuOverviewReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapCpuOverview(systemUsage.Data, userUsage.Data),
+			Unavailable: cmp.Or(systemUsage.Unavailable(), userUsage.Unavailable()),
+		},
+		&render_xlsx.CpuSystemUsageReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapCpuSystemUsage(systemUsage.Data),
+			Unavailable: systemUsage.Unavailable(),
+		},
+		&render_xlsx.CpuUserUsageReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapCpuUserUsage(userUsage.Data),
+			Unavailable: userUsage.Unavailable(),
+		},
+		&render_xlsx.MemoryUsageReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapMemoryUsage(host.Memory.Data),
+			Unavailable: host.Memory.Unavailable(),
+		},
+		&render_xlsx.DiskUsageReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapDiskUsage(host.Disk.Data),
+			Unavailable: host.Disk.Unavailable(),
+		},
+		&render_xlsx.NetworkUsageReport{
+			DateFrom:    int64(query.DateFrom),
+			DateTo:      int64(query.DateTo),
+			Data:        mapNetworkUsage(host.Network.Data),
+			Unavailable: host.Network.Unavailable(),
+		},
+	}
+
+	if query.Step > 0 {
+		timelines, err := datasource.FetchCpuUsageTimelines(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), query.Step, handler.policy, datasource.CpuSystem, datasource.CpuUser)
+		if err != nil {
+			return RenderFullHtmlResult{}, err
+		}
+		for _, timeline := range []struct {
+			name   string
+			result datasource.CpuUsageTimelineResult
+		}{
+			{"CPU System Timeline", timelines[datasource.CpuSystem]},
+			{"CPU User Timeline", timelines[datasource.CpuUser]},
+		} {
+			sections = append(sections, &render_xlsx.CpuUsageTimelineReport{
+				Name:        timeline.name,
+				DateFrom:    int64(query.DateFrom),
+				DateTo:      int64(query.DateTo),
+				Step:        query.Step,
+				Data:        mapCpuUsageTimelines(timeline.result.Data),
+				Unavailable: timeline.result.Unavailable(),
+			})
+		}
+	}
+
+	provenance := handler.source.Provenance()
+	options := query.Options
+	options.Header = header.Header{
+		Title:           "Host Usage Report",
+		DateFrom:        int64(query.DateFrom),
+		DateTo:          int64(query.DateTo),
+		Location:        handler.location,
+		GeneratedAt:     time.Now(),
+		Provider:        provenance.Address,
+		ProviderVersion: provenance.Version,
+		RequestID:       framework.RequestID(ctx),
+	}
+	var buf bytes.Buffer
+	if err := render_html.Write(ctx, &buf, options, sections...); err != nil {
+		return RenderFullHtmlResult{}, framework.NewRenderError("error rendering html", err)
+	}
+	return RenderFullHtmlResult{Payload: buf.Bytes()}, nil
+}
+
+// mapCpuSystemUsage maps the data source usages to a slice of CpuSystemUsageData.
+func mapCpuSystemUsage(usages []datasource.CpuUsage) []render_xlsx.CpuSystemUsageData {
+	data := make([]render_xlsx.CpuSystemUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.CpuSystemUsageData{
+			CPU:      u.CPU,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
+
+// mapCpuUserUsage maps the data source usages to a slice of CpuUserUsageData.
+func mapCpuUserUsage(usages []datasource.CpuUsage) []render_xlsx.CpuUserUsageData {
+	data := make([]render_xlsx.CpuUserUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.CpuUserUsageData{
+			CPU:      u.CPU,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
+
+// mapCpuOverview joins the system and user usages of every CPU.
+func mapCpuOverview(system []datasource.CpuUsage, user []datasource.CpuUsage) []render_xlsx.CpuOverviewData {
+	joined := datasource.JoinCpuUsage(system, user)
+	data := make([]render_xlsx.CpuOverviewData, len(joined))
+	for i, usage := range joined {
+		data[i] = render_xlsx.CpuOverviewData{CPU: usage.CPU}
+		if usage.System != nil {
+			data[i].System = &render_xlsx.CpuUsageStats{AvgUsage: usage.System.AvgUsage, MaxUsage: usage.System.MaxUsage, MinUsage: usage.System.MinUsage}
+		}
+		if usage.User != nil {
+			data[i].User = &render_xlsx.CpuUsageStats{AvgUsage: usage.User.AvgUsage, MaxUsage: usage.User.MaxUsage, MinUsage: usage.User.MinUsage}
+		}
+		if total, ok := usage.TotalAvgUsage(); ok {
+			data[i].TotalAvgUsage = &total
+		}
+	}
+	return data
+}
+
+// mapCpuUsageTimelines maps the data source timelines to a slice of CpuUsageTimelineData.
+func mapCpuUsageTimelines(timelines []datasource.CpuUsageTimeline) []render_xlsx.CpuUsageTimelineData {
+	data := make([]render_xlsx.CpuUsageTimelineData, len(timelines))
+	for i, t := range timelines {
+		samples := make([]render_xlsx.CpuUsageTimelineSample, len(t.Samples))
+		for j, sample := range t.Samples {
+			samples[j] = render_xlsx.CpuUsageTimelineSample(sample)
+		}
+		data[i] = render_xlsx.CpuUsageTimelineData{CPU: t.CPU, Samples: samples}
+	}
+	return data
+}
+
+// mapMemoryUsage maps the data source usages to a slice of MemoryUsageData.
+func mapMemoryUsage(usages []datasource.MemoryUsage) []render_xlsx.MemoryUsageData {
+	data := make([]render_xlsx.MemoryUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.MemoryUsageData(u)
+	}
+	return data
+}
+
+// mapDiskUsage maps the data source usages to a slice of DiskUsageData.
+func mapDiskUsage(usages []datasource.DiskUsage) []render_xlsx.DiskUsageData {
+	data := make([]render_xlsx.DiskUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.DiskUsageData(u)
+	}
+	return data
+}
+
+// mapNetworkUsage maps the data source usages to a slice of NetworkUsageData.
+func mapNetworkUsage(usages []datasource.NetworkUsage) []render_xlsx.NetworkUsageData {
+	data := make([]render_xlsx.NetworkUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.NetworkUsageData(u)
+	}
+	return data
+}
@@ -0,0 +1,31 @@
+package render_full_html
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	render_html "github.com/Javier-Godon/reports-rendering-go/render/html"
+)
+
+type RenderFullHtmlQuery struct {
+	DateFrom int32 `json:"date_from" binding:"required"`
+	DateTo   int32 `json:"date_to" binding:"required"`
+	// Step, when set, adds a timeline section per usage kind with one row per bucket of Step.
+	Step    time.Duration       `json:"step"`
+	Options render_html.Options `json:"options"`
+}
+
+// Validate checks that the query covers a non-empty period, that a requested timeline step is usable
+// and that the HTML options are valid.
+func (query RenderFullHtmlQuery) Validate() error {
+	if query.DateTo <= query.DateFrom {
+		return fmt.Errorf("date_to (%d) must be after date_from (%d)", query.DateTo, query.DateFrom)
+	}
+	if query.Step != 0 {
+		if err := datasource.ValidateTimelineStep(int64(query.DateFrom), int64(query.DateTo), query.Step); err != nil {
+			return err
+		}
+	}
+	return query.Options.Validate()
+}
@@ -0,0 +1,5 @@
+package render_full_html
+
+type RenderFullHtmlResult struct {
+	Payload []byte `json:"payload" binding:"required"`
+}
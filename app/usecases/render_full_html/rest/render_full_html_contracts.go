@@ -0,0 +1,24 @@
+package rest
+
+import "github.com/Javier-Godon/reports-rendering-go/framework"
+
+type RenderFullHtmlRequest struct {
+	DateFrom int32 `json:"date_from" binding:"required" description:"Start of the period, in unix seconds"`
+	DateTo   int32 `json:"date_to" binding:"required" description:"End of the period, in unix seconds"`
+	// Step of the optional timeline sections, as a duration such as "1m", "5m" or "1h".
+	Step     string `json:"step" description:"Adds CPU timeline sections bucketed by this duration, e.g. 5m or 1h (at least 1m)"`
+	Fragment bool   `json:"fragment" description:"Returns the report without the html, head and body elements, for embedding in emails and wiki pages"`
+	MaxRows  int    `json:"max_rows" description:"Rows shown per table (default 500); charts plot every row"`
+}
+
+// renderFullHtmlReport describes the report in the catalog.
+var renderFullHtmlReport = framework.ReportType{
+	ID:          "full-html",
+	Title:       "Host usage page",
+	Description: "The host usage report as a self-contained HTML page with SVG charts, viewable without JavaScript.",
+	Formats:     []string{"html"},
+}
+
+type RenderFullHtmlResponse struct {
+	Payload []byte `json:"payload" binding:"required"`
+}
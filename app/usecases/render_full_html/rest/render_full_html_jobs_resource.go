@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_html "github.com/Javier-Godon/reports-rendering-go/render/html"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRenderFullHtmlJobs(route *gin.Engine, jobs *framework.JobManager, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/html/jobs"
+	report := renderFullHtmlReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "html", Method: http.MethodPost, Path: path, Async: true}}
+	catalog.Register(report, RenderFullHtmlRequest{})
+
+	RenderFullHtmlJobsRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullHtmlRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullHtmlQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), "html")
+		// The job outlives the request, but its report still names the request ID.
+		requestID := framework.RequestID(ctx.Request.Context())
+		job, err := jobs.Submit("html", func(jobCtx context.Context) (framework.JobArtifact, error) {
+			result, err := mediator.Send(framework.WithRequestID(jobCtx, requestID), query)
+			if err != nil {
+				return framework.JobArtifact{}, err
+			}
+			return framework.JobArtifact{ContentType: render_html.ContentType, FileName: fileName, Payload: result.Payload}, nil
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Location", "/jobs/"+job.ID)
+		ctx.JSON(http.StatusAccepted, job)
+	})
+	return RenderFullHtmlJobsRoute
+}
@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_html "github.com/Javier-Godon/reports-rendering-go/render/html"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_html/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteRenderFullHtml serves the report inline, so that a browser shows it instead of downloading it.
+func RouteRenderFullHtml(route *gin.Engine, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/html/"
+	report := renderFullHtmlReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "html", Method: http.MethodPost, Path: path}}
+	catalog.Register(report, RenderFullHtmlRequest{})
+
+	RenderFullHtmlRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullHtmlRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullHtmlQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		RenderFullHtmlResult, err := mediator.Send(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if framework.WantsJSON(ctx, render_html.ContentType) {
+			ctx.JSON(http.StatusOK, RenderFullHtmlResponse{Payload: RenderFullHtmlResult.Payload})
+			return
+		}
+		ctx.Data(http.StatusOK, render_html.ContentType, RenderFullHtmlResult.Payload)
+	})
+	return RenderFullHtmlRoute
+}
+
+func buildRenderFullHtmlQuery(request RenderFullHtmlRequest) (render_full_html.RenderFullHtmlQuery, error) {
+	query := render_full_html.RenderFullHtmlQuery{
+		DateFrom: request.DateFrom,
+		DateTo:   request.DateTo,
+		Options:  render_html.Options{Fragment: request.Fragment, MaxRows: request.MaxRows},
+	}
+	if request.Step != "" {
+		step, err := time.ParseDuration(request.Step)
+		if err != nil {
+			return query, framework.NewValidationError("invalid step", err)
+		}
+		query.Step = step
+	}
+	return query, nil
+}
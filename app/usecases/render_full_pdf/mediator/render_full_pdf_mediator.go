@@ -1,22 +1,26 @@
 package mediator
 
 import (
+	"context"
 	"log"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
 	"github.com/Javier-Godon/reports-rendering-go/framework"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf"
 )
 
-func init() {
-	err := framework.Register[render_full_pdf.RenderFullPdfQuery, render_full_pdf.RenderFullPdfResult](render_full_pdf.NewRenderFullPdfHandler())
-	if err != nil {
-		return
-	}
+// Register registers the render full pdf handler, reading its data from source, applying policy
+// when some of it cannot be fetched, highlighting the overview CPUs busier than threshold and showing
+// the times of the report header in location.
+func Register(source datasource.DataSource, policy datasource.FailurePolicy, threshold float64, location *time.Location) error {
+	return framework.RegisterWithContext[render_full_pdf.RenderFullPdfQuery, render_full_pdf.RenderFullPdfResult](render_full_pdf.NewRenderFullPdfHandler(source, policy, threshold, location))
 }
 
-func Send(command render_full_pdf.RenderFullPdfQuery) render_full_pdf.RenderFullPdfResult {
-	RenderFullPdfResult, err := framework.Send[render_full_pdf.RenderFullPdfQuery, render_full_pdf.RenderFullPdfResult](command)
+func Send(ctx context.Context, command render_full_pdf.RenderFullPdfQuery) (render_full_pdf.RenderFullPdfResult, error) {
+	RenderFullPdfResult, err := framework.SendWithContext[render_full_pdf.RenderFullPdfQuery, render_full_pdf.RenderFullPdfResult](ctx, command)
 	if err != nil {
-		log.Fatalf("Could not execute: %v", command)
+		log.Printf("Could not execute %+v: %v", command, err)
 	}
-	return RenderFullPdfResult
-}
\ No newline at end of file
+	return RenderFullPdfResult, err
+}
@@ -1,93 +1,198 @@
 package render_full_pdf
 
 import (
-	"fmt"
-	"log"
-	"strings"
-	"encoding/json"
+	"bytes"
+	"cmp"
 	"context"
+	"time"
 
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
 	config "github.com/Javier-Godon/reports-rendering-go/framework"
-	pb_system "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_system_usage"
-	pb_user "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_user_usage"
-	// render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
-	
-	proto "github.com/Javier-Godon/reports-rendering-go/proto"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_pdf "github.com/Javier-Godon/reports-rendering-go/render/pdf"
 )
 
 type RenderFullPdfHandler struct {
+	source    datasource.DataSource
+	policy    datasource.FailurePolicy
+	threshold float64
+	location  *time.Location
 }
 
-func NewRenderFullPdfHandler() *RenderFullPdfHandler {
-	return &RenderFullPdfHandler{
-	}
+// NewRenderFullPdfHandler creates the handler. The overview highlights the CPUs busier than threshold, when positive,
+// and the report header shows times in location.
+func NewRenderFullPdfHandler(source datasource.DataSource, policy datasource.FailurePolicy, threshold float64, location *time.Location) *RenderFullPdfHandler {
+	return &RenderFullPdfHandler{source: source, policy: policy, threshold: threshold, location: location}
 }
 
-func (handler RenderFullPdfHandler) Handle(query RenderFullPdfQuery) (RenderFullPdfResult, error) {	
-	address := config.AppConfig.DataProvider.ADDRESS 
-	clientSingleton := proto.GRPCClientSingleton{}
-	client, err := clientSingleton.Instance(address)
+func (handler RenderFullPdfHandler) Handle(ctx context.Context, query RenderFullPdfQuery) (RenderFullPdfResult, error) {
+	usages, err := datasource.FetchCpuUsages(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy, datasource.CpuSystem, datasource.CpuUser)
 	if err != nil {
-		log.Fatalf("failed to get gRPC client instance: %v", err) 
+		return RenderFullPdfResult{}, err
 	}
-	defer client.Close() 
+	systemUsage, userUsage := usages[datasource.CpuSystem], usages[datasource.CpuUser]
 
-	ctx := context.Background() // Define a context
-	systemUsage, err := client.GetCpuSystemUsage(ctx, int64(query.DateFrom), int64(query.DateTo))
+	host, err := datasource.FetchHostUsage(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy)
 	if err != nil {
-		log.Printf("Error getting system usage: %v", err)
+		return RenderFullPdfResult{}, err
 	}
 
-	userUsage, err := client.GetCpuUserUsage(ctx, int64(query.DateFrom), int64(query.DateTo))
-	if err != nil {
-		log.Printf("Error getting user usage: %v", err)
+	reportOverviewData := render_pdf.CpuOverviewReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuOverview(systemUsage.Data, userUsage.Data),
+		Threshold:   handler.threshold,
+		Unavailable: cmp.Or(systemUsage.Unavailable(), userUsage.Unavailable()),
 	}
 
-	//  Map the gRPC responses to JSON strings.
-	systemUsageJSON, err := mapGetCpuSystemUsageResultToJSON(systemUsage)
-	if err != nil {
-		log.Printf("Error converting system usage to JSON: %v", err)
+	reportSystemData := render_pdf.CpuSystemUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuSystemUsage(systemUsage.Data),
+		Unavailable: systemUsage.Unavailable(),
 	}
 
-	userUsageJSON, err := mapGetCpuUserUsageResultToJSON(userUsage)
-	if err != nil {
-		log.Printf("Error converting user usage to JSON: %v", err)		
+	reportUserData := render_pdf.CpuUserUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuUserUsage(userUsage.Data),
+		Unavailable: userUsage.Unavailable(),
 	}
 
-	//  Create a list of JSON strings.
-	dataListJSON := []string{systemUsageJSON, userUsageJSON}
+	reportMemoryData := render_pdf.MemoryUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapMemoryUsage(host.Memory.Data),
+		Unavailable: host.Memory.Unavailable(),
+	}
 
+	reportDiskData := render_pdf.DiskUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapDiskUsage(host.Disk.Data),
+		Unavailable: host.Disk.Unavailable(),
+	}
 
-	// render_xlsx.CpuSystemUsageReport()
+	reportNetworkData := render_pdf.NetworkUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapNetworkUsage(host.Network.Data),
+		Unavailable: host.Network.Unavailable(),
+	}
 
-	//  Do something with dataListJSON (e.g., return it, log it, etc.).
-	fmt.Printf("Data: %v\n", dataListJSON)
+	provenance := handler.source.Provenance()
+	reportHeader := header.Header{
+		Title:           "Host Usage Report",
+		DateFrom:        int64(query.DateFrom),
+		DateTo:          int64(query.DateTo),
+		Location:        handler.location,
+		GeneratedAt:     time.Now(),
+		Provider:        provenance.Address,
+		ProviderVersion: provenance.Version,
+		RequestID:       config.RequestID(ctx),
+	}
 
-	result := RenderFullPdfResult{
-		Payload: strings.Join(dataListJSON, ","), // Join the JSON strings into a single string
+	doc := render_pdf.NewDocument(reportHeader)
+	if err := render_pdf.RenderTitlePage(doc, reportHeader); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering title page", err)
+	}
+	if err := reportOverviewData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering overview", err)
+	}
+	if err := reportSystemData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering system data", err)
+	}
+	if err := reportUserData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering user data", err)
+	}
+	if err := reportMemoryData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering memory data", err)
+	}
+	if err := reportDiskData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering disk data", err)
+	}
+	if err := reportNetworkData.Render(ctx, doc); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("error rendering network data", err)
 	}
 
-	return result, err
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		return RenderFullPdfResult{}, config.NewRenderError("failed to write file", err)
+	}
+
+	return RenderFullPdfResult{Payload: buf.Bytes()}, nil
 }
 
+// mapCpuSystemUsage maps the data source usages to a slice of CpuSystemUsageData.
+func mapCpuSystemUsage(usages []datasource.CpuUsage) []render_pdf.CpuSystemUsageData {
+	data := make([]render_pdf.CpuSystemUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_pdf.CpuSystemUsageData{
+			CPU:      u.CPU,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
 
+// mapCpuOverview joins the system and user usages of every CPU.
+func mapCpuOverview(system []datasource.CpuUsage, user []datasource.CpuUsage) []render_pdf.CpuOverviewData {
+	joined := datasource.JoinCpuUsage(system, user)
+	data := make([]render_pdf.CpuOverviewData, len(joined))
+	for i, usage := range joined {
+		data[i] = render_pdf.CpuOverviewData{CPU: usage.CPU}
+		if usage.System != nil {
+			data[i].System = &render_pdf.CpuUsageStats{AvgUsage: usage.System.AvgUsage, MaxUsage: usage.System.MaxUsage, MinUsage: usage.System.MinUsage}
+		}
+		if usage.User != nil {
+			data[i].User = &render_pdf.CpuUsageStats{AvgUsage: usage.User.AvgUsage, MaxUsage: usage.User.MaxUsage, MinUsage: usage.User.MinUsage}
+		}
+		if total, ok := usage.TotalAvgUsage(); ok {
+			data[i].TotalAvgUsage = &total
+		}
+	}
+	return data
+}
 
+// mapCpuUserUsage maps the data source usages to a slice of CpuUserUsageData.
+func mapCpuUserUsage(usages []datasource.CpuUsage) []render_pdf.CpuUserUsageData {
+	data := make([]render_pdf.CpuUserUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_pdf.CpuUserUsageData{
+			CPU:      u.CPU,
+			AvgUsage: u.AvgUsage,
+			MaxUsage: u.MaxUsage,
+			MinUsage: u.MinUsage,
+		}
+	}
+	return data
+}
 
+// mapMemoryUsage maps the data source usages to a slice of MemoryUsageData.
+func mapMemoryUsage(usages []datasource.MemoryUsage) []render_pdf.MemoryUsageData {
+	data := make([]render_pdf.MemoryUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_pdf.MemoryUsageData(u)
+	}
+	return data
+}
 
-/// mapGetCpuSystemUsageResultToJSON converts the GetCpuSystemUsageResponse to a JSON string.
-func mapGetCpuSystemUsageResultToJSON(resp *pb_system.GetCpuSystemUsageResponse) (string, error) {	
-	jsonBytes, err := json.Marshal(resp)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal GetCpuSystemUsageResponse to JSON: %w", err)
+// mapDiskUsage maps the data source usages to a slice of DiskUsageData.
+func mapDiskUsage(usages []datasource.DiskUsage) []render_pdf.DiskUsageData {
+	data := make([]render_pdf.DiskUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_pdf.DiskUsageData(u)
 	}
-	return string(jsonBytes), nil
+	return data
 }
 
-// mapGetCpuUserUsageResultToJSON converts the GetCpuUserUsageResponse to a JSON string.
-func mapGetCpuUserUsageResultToJSON(resp *pb_user.GetCpuUserUsageResponse) (string, error) {	
-	jsonBytes, err := json.Marshal(resp)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal GetCpuUserUsageResponse to JSON: %w", err)
+// mapNetworkUsage maps the data source usages to a slice of NetworkUsageData.
+func mapNetworkUsage(usages []datasource.NetworkUsage) []render_pdf.NetworkUsageData {
+	data := make([]render_pdf.NetworkUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_pdf.NetworkUsageData(u)
 	}
-	return string(jsonBytes), nil
-}
\ No newline at end of file
+	return data
+}
@@ -1,6 +1,16 @@
 package render_full_pdf
 
+import "fmt"
+
 type RenderFullPdfQuery struct {
 	DateFrom int32 `json:"date_from" binding:"required"`
 	DateTo   int32 `json:"date_to" binding:"required"`
-}
\ No newline at end of file
+}
+
+// Validate checks that the query covers a non-empty period.
+func (query RenderFullPdfQuery) Validate() error {
+	if query.DateTo <= query.DateFrom {
+		return fmt.Errorf("date_to (%d) must be after date_from (%d)", query.DateTo, query.DateFrom)
+	}
+	return nil
+}
@@ -1,5 +1,5 @@
 package render_full_pdf
 
 type RenderFullPdfResult struct {
-	Payload string `json:"payload" binding:"required"`
-}
\ No newline at end of file
+	Payload []byte `json:"payload" binding:"required"`
+}
@@ -1,10 +1,20 @@
 package rest
 
+import "github.com/Javier-Godon/reports-rendering-go/framework"
+
 type RenderFullPdfRequest struct {
-	DateFrom int32 `json:"date_from" binding:"required"`
-	DateTo   int32 `json:"date_to" binding:"required"`
+	DateFrom int32 `json:"date_from" binding:"required" description:"Start of the period, in unix seconds"`
+	DateTo   int32 `json:"date_to" binding:"required" description:"End of the period, in unix seconds"`
 }
 
 type RenderFullPdfResponse struct {
-	Payload string `json:"payload" binding:"required"`
+	Payload []byte `json:"payload" binding:"required"`
+}
+
+// renderFullPdfReport describes the report in the catalog.
+var renderFullPdfReport = framework.ReportType{
+	ID:          "full-pdf",
+	Title:       "Host usage document",
+	Description: "CPU system/user, memory, disk and network usage of the host, one section per dataset.",
+	Formats:     []string{"pdf"},
 }
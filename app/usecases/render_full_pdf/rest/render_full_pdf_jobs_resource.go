@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRenderFullPdfJobs(route *gin.Engine, jobs *framework.JobManager, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/pdf/jobs"
+	report := renderFullPdfReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "pdf", Method: http.MethodPost, Path: path, Async: true}}
+	catalog.Register(report, RenderFullPdfRequest{})
+
+	RenderFullPdfJobsRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullPdfRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query := buildRenderFullPdfQuery(request)
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), "pdf")
+		// The job outlives the request, but its report still names the request ID.
+		requestID := framework.RequestID(ctx.Request.Context())
+		job, err := jobs.Submit("pdf", func(jobCtx context.Context) (framework.JobArtifact, error) {
+			result, err := mediator.Send(framework.WithRequestID(jobCtx, requestID), query)
+			if err != nil {
+				return framework.JobArtifact{}, err
+			}
+			return framework.JobArtifact{ContentType: framework.MIMEPdf, FileName: fileName, Payload: result.Payload}, nil
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Location", "/jobs/"+job.ID)
+		ctx.JSON(http.StatusAccepted, job)
+	})
+	return RenderFullPdfJobsRoute
+}
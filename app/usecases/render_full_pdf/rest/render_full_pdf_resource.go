@@ -2,22 +2,38 @@ package rest
 
 import (
 	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_pdf/mediator"
 	"github.com/gin-gonic/gin"
-) 
+)
+
+func RouteRenderFullPdf(route *gin.Engine, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/pdf/"
+	report := renderFullPdfReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "pdf", Method: http.MethodPost, Path: path}}
+	catalog.Register(report, RenderFullPdfRequest{})
 
-func RouteRenderFullPdf(route *gin.Engine)(routes gin.IRoutes){
-	RenderFullPdfRoute := route.POST("/render/pdf/", func(ctx *gin.Context) {
+	RenderFullPdfRoute := route.POST(path, func(ctx *gin.Context) {
 		var request RenderFullPdfRequest
 		err := ctx.ShouldBindJSON(&request)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		RenderFullPdfResult, err := mediator.Send(ctx.Request.Context(), buildRenderFullPdfQuery(request))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if framework.WantsJSON(ctx, framework.MIMEPdf) {
+			ctx.JSON(http.StatusOK, fromRenderFullPdfResultToResponse(RenderFullPdfResult))
 			return
 		}
-		RenderFullPdfResult := mediator.Send(buildRenderFullPdfQuery(request))
-		ctx.JSON(http.StatusOK, fromRenderFullPdfResultToResponse(RenderFullPdfResult))
-	} )
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), "pdf")
+		framework.RespondWithAttachment(ctx, framework.MIMEPdf, fileName, RenderFullPdfResult.Payload)
+	})
 	return RenderFullPdfRoute
 
 }
@@ -30,10 +46,10 @@ func fromRenderFullPdfResultToResponse(result render_full_pdf.RenderFullPdfResul
 
 func buildRenderFullPdfQuery(request RenderFullPdfRequest) render_full_pdf.RenderFullPdfQuery {
 	return render_full_pdf.RenderFullPdfQuery{
-		DateFrom:        request.DateFrom,
-		DateTo: request.DateTo,
+		DateFrom: request.DateFrom,
+		DateTo:   request.DateTo,
 	}
 }
 
 //https://stackoverflow.com/questions/42967235/golang-gin-gonic-split-routes-into-multiple-files
-//https://www.youtube.com/watch?v=BkAoT2XZM24
\ No newline at end of file
+//https://www.youtube.com/watch?v=BkAoT2XZM24
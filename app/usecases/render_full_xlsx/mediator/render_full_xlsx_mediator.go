@@ -1,22 +1,27 @@
 package mediator
 
 import (
+	"context"
 	"log"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
 	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx"
 )
 
-func init() {
-	err := framework.Register[render_full_xlsx.RenderFullXlsxQuery, render_full_xlsx.RenderFullXlsxResult](render_full_xlsx.NewRenderFullXlsxHandler())
-	if err != nil {
-		return
-	}
+// Register registers the render full xlsx handler, reading its data from source, applying policy
+// when some of it cannot be fetched, streaming the reports that streaming selects, highlighting
+// the overview CPUs busier than threshold and showing the times of the report header in location.
+func Register(source datasource.DataSource, policy datasource.FailurePolicy, streaming render_xlsx.StreamOptions, threshold float64, location *time.Location) error {
+	return framework.RegisterWithContext[render_full_xlsx.RenderFullXlsxQuery, render_full_xlsx.RenderFullXlsxResult](render_full_xlsx.NewRenderFullXlsxHandler(source, policy, streaming, threshold, location))
 }
 
-func Send(query render_full_xlsx.RenderFullXlsxQuery) render_full_xlsx.RenderFullXlsxResult {
-	RenderFullXlsxResult, err := framework.Send[render_full_xlsx.RenderFullXlsxQuery, render_full_xlsx.RenderFullXlsxResult](query)
+func Send(ctx context.Context, query render_full_xlsx.RenderFullXlsxQuery) (render_full_xlsx.RenderFullXlsxResult, error) {
+	RenderFullXlsxResult, err := framework.SendWithContext[render_full_xlsx.RenderFullXlsxQuery, render_full_xlsx.RenderFullXlsxResult](ctx, query)
 	if err != nil {
-		log.Fatalf("Could not execute: %v", query)
+		log.Printf("Could not execute %+v: %v", query, err)
 	}
-	return RenderFullXlsxResult
-}
\ No newline at end of file
+	return RenderFullXlsxResult, err
+}
@@ -2,170 +2,253 @@ package render_full_xlsx
 
 import (
 	"bytes"
+	"cmp"
 	"context"
-	"fmt"
-	"log"
-	"os"
-	"sync"
+	"time"
 
-	"github.com/xuri/excelize/v2"
-
-	pb_system "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_system_usage"
-	pb_user "github.com/Javier-Godon/reports-rendering-go/proto/get_cpu_user_usage"
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
 	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
-
-	proto "github.com/Javier-Godon/reports-rendering-go/proto"
 )
 
-type RenderFullXlsxHandler struct{}
-
-func NewRenderFullXlsxHandler() *RenderFullXlsxHandler {
-	return &RenderFullXlsxHandler{}
+type RenderFullXlsxHandler struct {
+	source    datasource.DataSource
+	policy    datasource.FailurePolicy
+	streaming render_xlsx.StreamOptions
+	threshold float64
+	location  *time.Location
 }
 
-type RenderResult struct {
-	File      *excelize.File
-	SheetName string
-	Err       error
+// NewRenderFullXlsxHandler creates the handler. The overview highlights the CPUs busier than threshold, when positive,
+// and the report header shows times in location.
+func NewRenderFullXlsxHandler(source datasource.DataSource, policy datasource.FailurePolicy, streaming render_xlsx.StreamOptions, threshold float64, location *time.Location) *RenderFullXlsxHandler {
+	return &RenderFullXlsxHandler{source: source, policy: policy, streaming: streaming, threshold: threshold, location: location}
 }
 
-func copySheet(src, dst *excelize.File, sheetName string) {
-	rows, err := src.GetRows(sheetName)
+func (handler RenderFullXlsxHandler) Handle(ctx context.Context, query RenderFullXlsxQuery) (RenderFullXlsxResult, error) {
+	usages, err := datasource.FetchCpuUsages(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy, datasource.CpuSystem, datasource.CpuUser)
 	if err != nil {
-		log.Printf("Error reading rows from %s: %v", sheetName, err)
-		return
-	}
-	dst.NewSheet(sheetName)
-	for rowIdx, row := range rows {
-		for colIdx, cell := range row {
-			cellRef, _ := excelize.CoordinatesToCellName(colIdx+1, rowIdx+1)
-			dst.SetCellValue(sheetName, cellRef, cell)
-		}
+		return RenderFullXlsxResult{}, err
 	}
-}
+	systemUsage, userUsage := usages[datasource.CpuSystem], usages[datasource.CpuUser]
 
-func (handler RenderFullXlsxHandler) Handle(query RenderFullXlsxQuery) (RenderFullXlsxResult, error) {
-	address := "localhost:50051"
-	clientSingleton := proto.GRPCClientSingleton{}
-	client, err := clientSingleton.Instance(address)
+	host, err := datasource.FetchHostUsage(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy)
 	if err != nil {
-		log.Fatalf("failed to get gRPC client instance: %v", err)
+		return RenderFullXlsxResult{}, err
 	}
-	defer client.Close()
 
-	ctx := context.Background() // Define a context
-	systemUsage, err := client.GetCpuSystemUsage(ctx, int64(query.DateFrom), int64(query.DateTo))
-	if err != nil {
-		log.Printf("Error getting system usage: %v", err)
+	reportOverviewData := render_xlsx.CpuOverviewReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuOverview(systemUsage.Data, userUsage.Data),
+		Threshold:   handler.threshold,
+		Unavailable: cmp.Or(systemUsage.Unavailable(), userUsage.Unavailable()),
 	}
 
-	userUsage, err := client.GetCpuUserUsage(ctx, int64(query.DateFrom), int64(query.DateTo))
-	if err != nil {
-		log.Printf("Error getting user usage: %v", err)
+	reportSystemData := render_xlsx.CpuSystemUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuSystemUsage(systemUsage.Data),
+		Thresholds:  query.Thresholds,
+		Unavailable: systemUsage.Unavailable(),
 	}
 
-	// Map the gRPC responses to CpuUsageData structs.
-	systemUsageData, err := mapGetCpuSystemUsageResponse(systemUsage)
-	if err != nil {
-		log.Printf("Error converting system usage: %v", err)
+	reportUserData := render_xlsx.CpuUserUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapCpuUserUsage(userUsage.Data),
+		Thresholds:  query.Thresholds,
+		Unavailable: userUsage.Unavailable(),
 	}
 
-	userUsageData, err := mapGetCpuUserUsageResponse(userUsage)
-	if err != nil {
-		log.Printf("Error converting user usage: %v", err)
+	reportMemoryData := render_xlsx.MemoryUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapMemoryUsage(host.Memory.Data),
+		Unavailable: host.Memory.Unavailable(),
 	}
 
-	reportSystemData := render_xlsx.CpuSystemUsageReport{
-		DateFrom: int64(query.DateFrom),
-		DateTo:   int64(query.DateTo),
-		Data:     systemUsageData,
+	reportDiskData := render_xlsx.DiskUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapDiskUsage(host.Disk.Data),
+		Unavailable: host.Disk.Unavailable(),
 	}
 
-	reportUserData := render_xlsx.CpuUserUsageReport{
-		DateFrom: int64(query.DateFrom),
-		DateTo:   int64(query.DateTo),
-		Data:     userUsageData,
+	reportNetworkData := render_xlsx.NetworkUsageReport{
+		DateFrom:    int64(query.DateFrom),
+		DateTo:      int64(query.DateTo),
+		Data:        mapNetworkUsage(host.Network.Data),
+		Unavailable: host.Network.Unavailable(),
 	}
 
-	f := excelize.NewFile()
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	var systemErr, userErr error
-
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		mu.Lock()
-		defer mu.Unlock()
-		systemErr = reportSystemData.Render(f)
-	}()
+	reports := []render_xlsx.Report{&reportOverviewData, &reportSystemData, &reportUserData, &reportMemoryData, &reportDiskData, &reportNetworkData}
+	rowCount := len(reportOverviewData.Data) + len(systemUsage.Data) + len(userUsage.Data) + len(host.Memory.Data) + len(host.Disk.Data) + len(host.Network.Data)
 
-	go func() {
-		defer wg.Done()
-		mu.Lock()
-		defer mu.Unlock()
-		userErr = reportUserData.Render(f)
-	}()
-
-	wg.Wait()
-
-	if systemErr != nil {
-		return RenderFullXlsxResult{}, fmt.Errorf("error rendering system data: %w", systemErr)
-	}
-	if userErr != nil {
-		return RenderFullXlsxResult{}, fmt.Errorf("error rendering user data: %w", userErr)
+	if query.Step > 0 {
+		timelines, err := datasource.FetchCpuUsageTimelines(ctx, handler.source, int64(query.DateFrom), int64(query.DateTo), query.Step, handler.policy, datasource.CpuSystem, datasource.CpuUser)
+		if err != nil {
+			return RenderFullXlsxResult{}, err
+		}
+		for _, timeline := range []struct {
+			name   string
+			result datasource.CpuUsageTimelineResult
+		}{
+			{"CPU System Timeline", timelines[datasource.CpuSystem]},
+			{"CPU User Timeline", timelines[datasource.CpuUser]},
+		} {
+			report := &render_xlsx.CpuUsageTimelineReport{
+				Name:        timeline.name,
+				DateFrom:    int64(query.DateFrom),
+				DateTo:      int64(query.DateTo),
+				Step:        query.Step,
+				Data:        mapCpuUsageTimelines(timeline.result.Data),
+				Unavailable: timeline.result.Unavailable(),
+			}
+			reports = append(reports, report)
+			rowCount += report.RowCount()
+		}
 	}
 
-	// Remove default sheet (if it's still there)
-	if defaultSheet := f.GetSheetName(0); defaultSheet != "" {
-		_ = f.DeleteSheet(defaultSheet)
+	provenance := handler.source.Provenance()
+	reportHeader := header.Header{
+		Title:           "Host Usage Report",
+		DateFrom:        int64(query.DateFrom),
+		DateTo:          int64(query.DateTo),
+		Location:        handler.location,
+		GeneratedAt:     time.Now(),
+		Provider:        provenance.Address,
+		ProviderVersion: provenance.Version,
+		RequestID:       framework.RequestID(ctx),
+	}
+
+	// The dashboard refers to the other sheets and is added once they are rendered.
+	dashboard := render_xlsx.DashboardReport{Header: reportHeader, Overview: &reportOverviewData, System: &reportSystemData, User: &reportUserData}
+
+	// Large datasets are streamed sheet by sheet and written straight to the response by the caller.
+	if handler.streaming.Enabled(rowCount) {
+		streaming := handler.streaming
+		streaming.HeaderFooter = render_xlsx.PageHeaderFooter(reportHeader)
+		workbook, err := render_xlsx.StreamWorkbook(ctx, streaming, reports...)
+		if err != nil {
+			return RenderFullXlsxResult{}, framework.NewRenderError("error rendering workbook", err)
+		}
+		if err := render_xlsx.AddDashboard(workbook, &dashboard); err != nil {
+			_ = workbook.Close()
+			return RenderFullXlsxResult{}, framework.NewRenderError("error rendering dashboard", err)
+		}
+		if err := render_xlsx.SetDocumentProperties(workbook, reportHeader); err != nil {
+			_ = workbook.Close()
+			return RenderFullXlsxResult{}, framework.NewRenderError("error rendering header", err)
+		}
+		return RenderFullXlsxResult{Workbook: workbook}, nil
 	}
 
-	if idx, err := f.GetSheetIndex("CPU System Usage"); err == nil {
-		f.SetActiveSheet(idx)
+	// Each sheet is rendered into its own workbook in parallel, then merged into the final file.
+	f, err := render_xlsx.RenderWorkbook(ctx, reports...)
+	if err != nil {
+		return RenderFullXlsxResult{}, framework.NewRenderError("error rendering workbook", err)
+	}
+	defer f.Close()
+	if err := render_xlsx.AddDashboard(f, &dashboard); err != nil {
+		return RenderFullXlsxResult{}, framework.NewRenderError("error rendering dashboard", err)
+	}
+	if err := render_xlsx.ApplyHeader(f, reportHeader); err != nil {
+		return RenderFullXlsxResult{}, framework.NewRenderError("error rendering header", err)
 	}
 
 	var buf bytes.Buffer
 	if err := f.Write(&buf); err != nil {
-		return RenderFullXlsxResult{}, fmt.Errorf("failed to write file: %w", err)
-	}
-
-	xlsxBytes := buf.Bytes()
-
-	if err := os.WriteFile("final_report.xlsx", xlsxBytes, 0o644); err != nil {
-		log.Printf("Error writing XLSX file: %v", err)
+		return RenderFullXlsxResult{}, framework.NewRenderError("failed to write file", err)
 	}
 
-	fmt.Println("Excel report generated successfully")
-	return RenderFullXlsxResult{Payload: xlsxBytes}, nil
-	
+	return RenderFullXlsxResult{Payload: buf.Bytes()}, nil
 }
 
-// / mapGetCpuSystemUsageResponse maps the gRPC response to a slice of CpuUsageData.
-func mapGetCpuSystemUsageResponse(usage *pb_system.GetCpuSystemUsageResponse) ([]render_xlsx.CpuSystemUsageData, error) {
-	data := make([]render_xlsx.CpuSystemUsageData, len(usage.Usages))
-	for i, u := range usage.Usages {
+// mapCpuSystemUsage maps the data source usages to a slice of CpuSystemUsageData.
+func mapCpuSystemUsage(usages []datasource.CpuUsage) []render_xlsx.CpuSystemUsageData {
+	data := make([]render_xlsx.CpuSystemUsageData, len(usages))
+	for i, u := range usages {
 		data[i] = render_xlsx.CpuSystemUsageData{
-			CPU:      u.Cpu,
+			CPU:      u.CPU,
 			AvgUsage: u.AvgUsage,
 			MaxUsage: u.MaxUsage,
 			MinUsage: u.MinUsage,
 		}
 	}
-	return data, nil
+	return data
 }
 
-// mapGetCpuUserUsageResponse maps the gRPC response to a slice of CpuUsageData.
-func mapGetCpuUserUsageResponse(usage *pb_user.GetCpuUserUsageResponse) ([]render_xlsx.CpuUserUsageData, error) {
-	data := make([]render_xlsx.CpuUserUsageData, len(usage.Usages))
-	for i, u := range usage.Usages {
+// mapCpuUserUsage maps the data source usages to a slice of CpuUserUsageData.
+func mapCpuUserUsage(usages []datasource.CpuUsage) []render_xlsx.CpuUserUsageData {
+	data := make([]render_xlsx.CpuUserUsageData, len(usages))
+	for i, u := range usages {
 		data[i] = render_xlsx.CpuUserUsageData{
-			CPU:      u.Cpu,
+			CPU:      u.CPU,
 			AvgUsage: u.AvgUsage,
 			MaxUsage: u.MaxUsage,
 			MinUsage: u.MinUsage,
 		}
 	}
-	return data, nil
+	return data
+}
+
+// mapCpuOverview joins the system and user usages of every CPU.
+func mapCpuOverview(system []datasource.CpuUsage, user []datasource.CpuUsage) []render_xlsx.CpuOverviewData {
+	joined := datasource.JoinCpuUsage(system, user)
+	data := make([]render_xlsx.CpuOverviewData, len(joined))
+	for i, usage := range joined {
+		data[i] = render_xlsx.CpuOverviewData{CPU: usage.CPU}
+		if usage.System != nil {
+			data[i].System = &render_xlsx.CpuUsageStats{AvgUsage: usage.System.AvgUsage, MaxUsage: usage.System.MaxUsage, MinUsage: usage.System.MinUsage}
+		}
+		if usage.User != nil {
+			data[i].User = &render_xlsx.CpuUsageStats{AvgUsage: usage.User.AvgUsage, MaxUsage: usage.User.MaxUsage, MinUsage: usage.User.MinUsage}
+		}
+		if total, ok := usage.TotalAvgUsage(); ok {
+			data[i].TotalAvgUsage = &total
+		}
+	}
+	return data
+}
+
+// mapCpuUsageTimelines maps the data source timelines to a slice of CpuUsageTimelineData.
+func mapCpuUsageTimelines(timelines []datasource.CpuUsageTimeline) []render_xlsx.CpuUsageTimelineData {
+	data := make([]render_xlsx.CpuUsageTimelineData, len(timelines))
+	for i, t := range timelines {
+		samples := make([]render_xlsx.CpuUsageTimelineSample, len(t.Samples))
+		for j, sample := range t.Samples {
+			samples[j] = render_xlsx.CpuUsageTimelineSample(sample)
+		}
+		data[i] = render_xlsx.CpuUsageTimelineData{CPU: t.CPU, Samples: samples}
+	}
+	return data
+}
+
+// mapMemoryUsage maps the data source usages to a slice of MemoryUsageData.
+func mapMemoryUsage(usages []datasource.MemoryUsage) []render_xlsx.MemoryUsageData {
+	data := make([]render_xlsx.MemoryUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.MemoryUsageData(u)
+	}
+	return data
+}
+
+// mapDiskUsage maps the data source usages to a slice of DiskUsageData.
+func mapDiskUsage(usages []datasource.DiskUsage) []render_xlsx.DiskUsageData {
+	data := make([]render_xlsx.DiskUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.DiskUsageData(u)
+	}
+	return data
+}
+
+// mapNetworkUsage maps the data source usages to a slice of NetworkUsageData.
+func mapNetworkUsage(usages []datasource.NetworkUsage) []render_xlsx.NetworkUsageData {
+	data := make([]render_xlsx.NetworkUsageData, len(usages))
+	for i, u := range usages {
+		data[i] = render_xlsx.NetworkUsageData(u)
+	}
+	return data
 }
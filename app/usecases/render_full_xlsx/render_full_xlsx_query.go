@@ -1,6 +1,39 @@
 package render_full_xlsx
 
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
 type RenderFullXlsxQuery struct {
 	DateFrom int32 `json:"date_from" binding:"required"`
 	DateTo   int32 `json:"date_to" binding:"required"`
-}
\ No newline at end of file
+	// Step, when set, adds a timeline sheet per usage kind with one row per bucket of Step.
+	Step time.Duration `json:"step"`
+	// Thresholds highlight the usage columns of the CPU system and user sheets.
+	Thresholds render_xlsx.Thresholds `json:"thresholds"`
+}
+
+// Validate checks that the query covers a non-empty period, that the thresholds are usable and, if a
+// timeline is requested, that its step is usable for that period.
+func (query RenderFullXlsxQuery) Validate() error {
+	if query.DateTo <= query.DateFrom {
+		return fmt.Errorf("date_to (%d) must be after date_from (%d)", query.DateTo, query.DateFrom)
+	}
+	for metric := range query.Thresholds {
+		if !slices.Contains(render_xlsx.CpuUsageMetrics, metric) {
+			return fmt.Errorf("unknown threshold metric %q, expected one of %v", metric, render_xlsx.CpuUsageMetrics)
+		}
+	}
+	if err := query.Thresholds.Validate(); err != nil {
+		return err
+	}
+	if query.Step != 0 {
+		return datasource.ValidateTimelineStep(int64(query.DateFrom), int64(query.DateTo), query.Step)
+	}
+	return nil
+}
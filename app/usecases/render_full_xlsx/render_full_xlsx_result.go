@@ -1,5 +1,42 @@
 package render_full_xlsx
 
+import (
+	"bytes"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+)
+
 type RenderFullXlsxResult struct {
 	Payload []byte `json:"payload" binding:"required"`
-}
\ No newline at end of file
+	// Workbook is set instead of Payload when the report was rendered with the streaming writer,
+	// so that it can be written straight to the response. Write or Bytes close it.
+	Workbook *excelize.File `json:"-"`
+}
+
+// Write writes the rendered file to w.
+func (result RenderFullXlsxResult) Write(w io.Writer) error {
+	if result.Workbook == nil {
+		_, err := w.Write(result.Payload)
+		return err
+	}
+	defer result.Workbook.Close()
+	if err := result.Workbook.Write(w); err != nil {
+		return framework.NewRenderError("failed to write file", err)
+	}
+	return nil
+}
+
+// Bytes returns the rendered file, buffering the streamed workbook if there is one.
+func (result RenderFullXlsxResult) Bytes() ([]byte, error) {
+	if result.Workbook == nil {
+		return result.Payload, nil
+	}
+	var buf bytes.Buffer
+	if err := result.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,8 +1,25 @@
 package rest
 
+import (
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
 type RenderFullXlsxRequest struct {
-	DateFrom int32 `json:"date_from" binding:"required"`
-	DateTo   int32 `json:"date_to" binding:"required"`
+	DateFrom int32 `json:"date_from" binding:"required" description:"Start of the period, in unix seconds"`
+	DateTo   int32 `json:"date_to" binding:"required" description:"End of the period, in unix seconds"`
+	// Step of the optional timeline sheets, as a duration such as "1m", "5m" or "1h".
+	Step string `json:"step" description:"Adds CPU timeline sheets bucketed by this duration, e.g. 5m or 1h (at least 1m)"`
+	// Thresholds of the CPU usage sheets, keyed by avg_usage, max_usage or min_usage.
+	Thresholds render_xlsx.Thresholds `json:"thresholds" description:"Highlight limits of the CPU usage columns (avg_usage, max_usage, min_usage) as ratios, e.g. 0.9 for 90%"`
+}
+
+// renderFullXlsxReport describes the report in the catalog.
+var renderFullXlsxReport = framework.ReportType{
+	ID:          "full-xlsx",
+	Title:       "Host usage workbook",
+	Description: "CPU system/user, memory, disk and network usage of the host, one sheet per dataset, with optional CPU timelines.",
+	Formats:     []string{"xlsx"},
 }
 
 type RenderFullXlsxResponse struct {
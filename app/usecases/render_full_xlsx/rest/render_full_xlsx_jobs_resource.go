@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRenderFullXlsxJobs(route *gin.Engine, jobs *framework.JobManager, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/xlsx/jobs"
+	report := renderFullXlsxReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "xlsx", Method: http.MethodPost, Path: path, Async: true}}
+	catalog.Register(report, RenderFullXlsxRequest{})
+
+	RenderFullXlsxJobsRoute := route.POST(path, func(ctx *gin.Context) {
+		var request RenderFullXlsxRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullXlsxQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), "xlsx")
+		// The job outlives the request, but its report still names the request ID.
+		requestID := framework.RequestID(ctx.Request.Context())
+		job, err := jobs.Submit("xlsx", func(jobCtx context.Context) (framework.JobArtifact, error) {
+			result, err := mediator.Send(framework.WithRequestID(jobCtx, requestID), query)
+			if err != nil {
+				return framework.JobArtifact{}, err
+			}
+			payload, err := result.Bytes()
+			if err != nil {
+				return framework.JobArtifact{}, err
+			}
+			return framework.JobArtifact{ContentType: framework.MIMEXlsx, FileName: fileName, Payload: payload}, nil
+		})
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Location", "/jobs/"+job.ID)
+		ctx.JSON(http.StatusAccepted, job)
+	})
+	return RenderFullXlsxJobsRoute
+}
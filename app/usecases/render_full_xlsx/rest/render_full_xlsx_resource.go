@@ -2,22 +2,52 @@ package rest
 
 import (
 	"net/http"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx"
 	"github.com/Javier-Godon/reports-rendering-go/usecases/render_full_xlsx/mediator"
 	"github.com/gin-gonic/gin"
-) 
+)
+
+func RouteRenderFullXlsx(route *gin.Engine, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	const path = "/render/xlsx/"
+	report := renderFullXlsxReport
+	report.Endpoints = []framework.ReportEndpoint{{Format: "xlsx", Method: http.MethodPost, Path: path}}
+	catalog.Register(report, RenderFullXlsxRequest{})
 
-func RouteRenderFullXlsx(route *gin.Engine)(routes gin.IRoutes){
-	RenderFullXlsxRoute := route.POST("/render/xlsx/", func(ctx *gin.Context) {
+	RenderFullXlsxRoute := route.POST(path, func(ctx *gin.Context) {
 		var request RenderFullXlsxRequest
 		err := ctx.ShouldBindJSON(&request)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderFullXlsxQuery(request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		RenderFullXlsxResult, err := mediator.Send(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.Error(err)
 			return
 		}
-		RenderFullXlsxResult := mediator.Send(buildRenderFullXlsxQuery(request))
-		ctx.JSON(http.StatusOK, fromRenderFullXlsxResultToResponse(RenderFullXlsxResult))
-	} )
+		if framework.WantsJSON(ctx, framework.MIMEXlsx) {
+			if RenderFullXlsxResult.Payload, err = RenderFullXlsxResult.Bytes(); err != nil {
+				ctx.Error(err)
+				return
+			}
+			ctx.JSON(http.StatusOK, fromRenderFullXlsxResultToResponse(RenderFullXlsxResult))
+			return
+		}
+		fileName := framework.AttachmentFileName("cpu_usage_report", int64(request.DateFrom), int64(request.DateTo), "xlsx")
+		if RenderFullXlsxResult.Workbook != nil {
+			framework.StreamAttachment(ctx, framework.MIMEXlsx, fileName, RenderFullXlsxResult.Write)
+			return
+		}
+		framework.RespondWithAttachment(ctx, framework.MIMEXlsx, fileName, RenderFullXlsxResult.Payload)
+	})
 	return RenderFullXlsxRoute
 
 }
@@ -28,12 +58,21 @@ func fromRenderFullXlsxResultToResponse(result render_full_xlsx.RenderFullXlsxRe
 	}
 }
 
-func buildRenderFullXlsxQuery(request RenderFullXlsxRequest) render_full_xlsx.RenderFullXlsxQuery {
-	return render_full_xlsx.RenderFullXlsxQuery{
-		DateFrom:        request.DateFrom,
-		DateTo: request.DateTo,
+func buildRenderFullXlsxQuery(request RenderFullXlsxRequest) (render_full_xlsx.RenderFullXlsxQuery, error) {
+	query := render_full_xlsx.RenderFullXlsxQuery{
+		DateFrom:   request.DateFrom,
+		DateTo:     request.DateTo,
+		Thresholds: request.Thresholds,
+	}
+	if request.Step != "" {
+		step, err := time.ParseDuration(request.Step)
+		if err != nil {
+			return query, framework.NewValidationError("invalid step", err)
+		}
+		query.Step = step
 	}
+	return query, nil
 }
 
 //https://stackoverflow.com/questions/42967235/golang-gin-gonic-split-routes-into-multiple-files
-//https://www.youtube.com/watch?v=BkAoT2XZM24
\ No newline at end of file
+//https://www.youtube.com/watch?v=BkAoT2XZM24
@@ -0,0 +1,28 @@
+package mediator
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+	"github.com/Javier-Godon/reports-rendering-go/reportdef"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_report"
+)
+
+// Register registers the handler rendering the report definitions of registry, reading their data
+// from source, applying policy when some of it cannot be fetched, streaming the large workbooks and
+// showing the times of the report headers in location.
+func Register(registry *reportdef.Registry, source datasource.DataSource, policy datasource.FailurePolicy, streaming render_xlsx.StreamOptions, location *time.Location) error {
+	return framework.RegisterWithContext[render_report.RenderReportQuery, render_report.RenderReportResult](render_report.NewRenderReportHandler(registry, source, policy, streaming, location))
+}
+
+func Send(ctx context.Context, query render_report.RenderReportQuery) (render_report.RenderReportResult, error) {
+	RenderReportResult, err := framework.SendWithContext[render_report.RenderReportQuery, render_report.RenderReportResult](ctx, query)
+	if err != nil {
+		log.Printf("Could not execute %+v: %v", query, err)
+	}
+	return RenderReportResult, err
+}
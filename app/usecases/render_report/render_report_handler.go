@@ -0,0 +1,138 @@
+package render_report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Javier-Godon/reports-rendering-go/datasource"
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	render_csv "github.com/Javier-Godon/reports-rendering-go/render/csv"
+	"github.com/Javier-Godon/reports-rendering-go/render/header"
+	render_html "github.com/Javier-Godon/reports-rendering-go/render/html"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+	"github.com/Javier-Godon/reports-rendering-go/reportdef"
+)
+
+// RenderReportHandler renders any report definition of the registry, to any format it supports.
+type RenderReportHandler struct {
+	registry  *reportdef.Registry
+	source    datasource.DataSource
+	policy    datasource.FailurePolicy
+	streaming render_xlsx.StreamOptions
+	location  *time.Location
+}
+
+// NewRenderReportHandler creates the handler. The report headers show times in location.
+func NewRenderReportHandler(registry *reportdef.Registry, source datasource.DataSource, policy datasource.FailurePolicy, streaming render_xlsx.StreamOptions, location *time.Location) *RenderReportHandler {
+	return &RenderReportHandler{registry: registry, source: source, policy: policy, streaming: streaming, location: location}
+}
+
+func (handler RenderReportHandler) Handle(ctx context.Context, query RenderReportQuery) (RenderReportResult, error) {
+	definition, ok := handler.registry.Get(query.ReportID)
+	if !ok {
+		return RenderReportResult{}, framework.NewNotFoundError(fmt.Sprintf("unknown report %q", query.ReportID), nil)
+	}
+	if !definition.Supports(query.Format) {
+		return RenderReportResult{}, framework.NewValidationError(fmt.Sprintf("report %q cannot be rendered to %q", query.ReportID, query.Format), nil)
+	}
+	for field := range query.Thresholds {
+		if !definition.HasTableField(field) {
+			return RenderReportResult{}, framework.NewValidationError(fmt.Sprintf("report %q has no table column of field %q", query.ReportID, field), nil)
+		}
+	}
+
+	data, err := reportdef.Fetch(ctx, definition, handler.source, int64(query.DateFrom), int64(query.DateTo), handler.policy)
+	if err != nil {
+		return RenderReportResult{}, err
+	}
+
+	provenance := handler.source.Provenance()
+	reportHeader := header.Header{
+		Title:           definition.Title,
+		DateFrom:        int64(query.DateFrom),
+		DateTo:          int64(query.DateTo),
+		Location:        handler.location,
+		GeneratedAt:     time.Now(),
+		Provider:        provenance.Address,
+		ProviderVersion: provenance.Version,
+		RequestID:       framework.RequestID(ctx),
+	}
+
+	switch query.Format {
+	case reportdef.FormatPdf:
+		payload, err := reportdef.RenderPdf(ctx, definition, data, reportHeader)
+		if err != nil {
+			return RenderReportResult{}, framework.NewRenderError("error rendering document", err)
+		}
+		return RenderReportResult{ContentType: framework.MIMEPdf, Extension: "pdf", Payload: payload}, nil
+	case reportdef.FormatCsv:
+		var buf bytes.Buffer
+		sections := append([]render_xlsx.Report{render_xlsx.HeaderSheet(reportHeader)}, reportdef.XlsxSheets(definition, data, nil)...)
+		if err := render_csv.Write(ctx, &buf, query.Csv, sections...); err != nil {
+			return RenderReportResult{}, framework.NewRenderError("error rendering csv", err)
+		}
+		return RenderReportResult{ContentType: query.Csv.ContentType(), Extension: query.Csv.Extension(), Payload: buf.Bytes()}, nil
+	case reportdef.FormatHtml:
+		options := render_html.Options{Header: reportHeader}
+		var buf bytes.Buffer
+		if err := render_html.Write(ctx, &buf, options, reportdef.XlsxSheets(definition, data, nil)...); err != nil {
+			return RenderReportResult{}, framework.NewRenderError("error rendering html", err)
+		}
+		return RenderReportResult{ContentType: render_html.ContentType, Extension: "html", Payload: buf.Bytes()}, nil
+	default:
+		if definition.Template != "" {
+			return renderTemplate(ctx, definition, data, reportHeader)
+		}
+		sheets := append([]render_xlsx.Report{render_xlsx.HeaderSheet(reportHeader)}, reportdef.XlsxSheets(definition, data, query.Thresholds)...)
+		rowCount := 0
+		for _, result := range data {
+			rowCount += len(result.Data)
+		}
+		if handler.streaming.Enabled(rowCount) {
+			streaming := handler.streaming
+			streaming.HeaderFooter = render_xlsx.PageHeaderFooter(reportHeader)
+			workbook, err := render_xlsx.StreamWorkbook(ctx, streaming, sheets...)
+			if err != nil {
+				return RenderReportResult{}, framework.NewRenderError("error rendering workbook", err)
+			}
+			if err := render_xlsx.SetDocumentProperties(workbook, reportHeader); err != nil {
+				_ = workbook.Close()
+				return RenderReportResult{}, framework.NewRenderError("error rendering header", err)
+			}
+			return RenderReportResult{ContentType: framework.MIMEXlsx, Extension: "xlsx", Workbook: workbook}, nil
+		}
+		f, err := render_xlsx.RenderWorkbook(ctx, sheets...)
+		if err != nil {
+			return RenderReportResult{}, framework.NewRenderError("error rendering workbook", err)
+		}
+		defer f.Close()
+		if err := render_xlsx.ApplyHeader(f, reportHeader); err != nil {
+			return RenderReportResult{}, framework.NewRenderError("error rendering header", err)
+		}
+		var buf bytes.Buffer
+		if err := f.Write(&buf); err != nil {
+			return RenderReportResult{}, framework.NewRenderError("failed to write file", err)
+		}
+		return RenderReportResult{ContentType: framework.MIMEXlsx, Extension: "xlsx", Payload: buf.Bytes()}, nil
+	}
+}
+
+// renderTemplate renders the report into its template workbook. The template keeps its own page
+// header and footer; only the document properties come from the report header.
+func renderTemplate(ctx context.Context, definition *reportdef.Definition, data reportdef.Data, reportHeader header.Header) (RenderReportResult, error) {
+	f, err := render_xlsx.RenderTemplate(ctx, definition.Template, reportdef.XlsxTemplateData(definition, data, reportHeader))
+	if err != nil {
+		return RenderReportResult{}, framework.NewRenderError("error rendering template", err)
+	}
+	defer f.Close()
+	if err := render_xlsx.SetDocumentProperties(f, reportHeader); err != nil {
+		return RenderReportResult{}, framework.NewRenderError("error rendering header", err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return RenderReportResult{}, framework.NewRenderError("failed to write file", err)
+	}
+	return RenderReportResult{ContentType: framework.MIMEXlsx, Extension: "xlsx", Payload: buf.Bytes()}, nil
+}
@@ -0,0 +1,28 @@
+package render_report
+
+import (
+	"fmt"
+
+	render_csv "github.com/Javier-Godon/reports-rendering-go/render/csv"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+// RenderReportQuery renders the report definition ReportID to Format over a period.
+type RenderReportQuery struct {
+	ReportID string `json:"report_id" binding:"required"`
+	Format   string `json:"format" binding:"required"`
+	DateFrom int32  `json:"date_from" binding:"required"`
+	DateTo   int32  `json:"date_to" binding:"required"`
+	// Csv shapes the output of the csv format.
+	Csv render_csv.Options `json:"csv"`
+	// Thresholds, keyed by field, replace the thresholds of the table columns in XLSX.
+	Thresholds render_xlsx.Thresholds `json:"thresholds"`
+}
+
+// Validate checks that the query covers a non-empty period and that the thresholds are usable.
+func (query RenderReportQuery) Validate() error {
+	if query.DateTo <= query.DateFrom {
+		return fmt.Errorf("date_to (%d) must be after date_from (%d)", query.DateTo, query.DateFrom)
+	}
+	return query.Thresholds.Validate()
+}
@@ -0,0 +1,44 @@
+package render_report
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+)
+
+type RenderReportResult struct {
+	ContentType string `json:"content_type"`
+	Extension   string `json:"extension"`
+	Payload     []byte `json:"payload" binding:"required"`
+	// Workbook is set instead of Payload when an XLSX report was rendered with the streaming writer,
+	// so that it can be written straight to the response. Write or Bytes close it.
+	Workbook *excelize.File `json:"-"`
+}
+
+// Write writes the rendered file to w.
+func (result RenderReportResult) Write(w io.Writer) error {
+	if result.Workbook == nil {
+		_, err := w.Write(result.Payload)
+		return err
+	}
+	defer result.Workbook.Close()
+	if err := result.Workbook.Write(w); err != nil {
+		return framework.NewRenderError("failed to write file", err)
+	}
+	return nil
+}
+
+// Bytes returns the rendered file, buffering the streamed workbook if there is one.
+func (result RenderReportResult) Bytes() ([]byte, error) {
+	if result.Workbook == nil {
+		return result.Payload, nil
+	}
+	var buf bytes.Buffer
+	if err := result.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,18 @@
+package rest
+
+import (
+	render_csv "github.com/Javier-Godon/reports-rendering-go/render/csv"
+	render_xlsx "github.com/Javier-Godon/reports-rendering-go/render/xlsx"
+)
+
+type RenderReportRequest struct {
+	render_csv.RequestOptions
+	DateFrom int32 `json:"date_from" binding:"required" description:"Start of the period, in unix seconds"`
+	DateTo   int32 `json:"date_to" binding:"required" description:"End of the period, in unix seconds"`
+	// Thresholds replace the thresholds the definition gives to its table columns.
+	Thresholds render_xlsx.Thresholds `json:"thresholds" description:"XLSX only: highlight limits of table columns, keyed by field, replacing those of the definition"`
+}
+
+type RenderReportResponse struct {
+	Payload []byte `json:"payload" binding:"required"`
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/Javier-Godon/reports-rendering-go/reportdef"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_report"
+	"github.com/Javier-Godon/reports-rendering-go/usecases/render_report/mediator"
+	"github.com/gin-gonic/gin"
+)
+
+// RouteRenderReport renders the report definitions of registry: POST /reports/{id}/render/{format}.
+func RouteRenderReport(route *gin.Engine, registry *reportdef.Registry, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	for _, definition := range registry.List() {
+		report := framework.ReportType{
+			ID:          definition.ID,
+			Title:       definition.Title,
+			Description: definition.Description,
+			Formats:     definition.OutputFormats(),
+		}
+		for _, format := range report.Formats {
+			report.Endpoints = append(report.Endpoints, framework.ReportEndpoint{
+				Format: format,
+				Method: http.MethodPost,
+				Path:   fmt.Sprintf("/reports/%s/render/%s", definition.ID, format),
+			})
+		}
+		catalog.Register(report, RenderReportRequest{})
+	}
+
+	RenderReportRoute := route.POST("/reports/:id/render/:format", func(ctx *gin.Context) {
+		var request RenderReportRequest
+		err := ctx.ShouldBindJSON(&request)
+		if err != nil {
+			ctx.Error(framework.NewValidationError("invalid request body", err))
+			return
+		}
+		query, err := buildRenderReportQuery(ctx.Param("id"), ctx.Param("format"), request)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		RenderReportResult, err := mediator.Send(ctx.Request.Context(), query)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		if framework.WantsJSON(ctx, RenderReportResult.ContentType) {
+			if RenderReportResult.Payload, err = RenderReportResult.Bytes(); err != nil {
+				ctx.Error(err)
+				return
+			}
+			ctx.JSON(http.StatusOK, RenderReportResponse{Payload: RenderReportResult.Payload})
+			return
+		}
+		fileName := framework.AttachmentFileName(query.ReportID, int64(request.DateFrom), int64(request.DateTo), RenderReportResult.Extension)
+		if RenderReportResult.Workbook != nil {
+			framework.StreamAttachment(ctx, RenderReportResult.ContentType, fileName, RenderReportResult.Write)
+			return
+		}
+		framework.RespondWithAttachment(ctx, RenderReportResult.ContentType, fileName, RenderReportResult.Payload)
+	})
+	return RenderReportRoute
+}
+
+func buildRenderReportQuery(id string, format string, request RenderReportRequest) (render_report.RenderReportQuery, error) {
+	query := render_report.RenderReportQuery{
+		ReportID:   id,
+		Format:     format,
+		DateFrom:   request.DateFrom,
+		DateTo:     request.DateTo,
+		Thresholds: request.Thresholds,
+	}
+	options, err := request.RequestOptions.Parse()
+	if err != nil {
+		return query, framework.NewValidationError("invalid csv options", err)
+	}
+	query.Csv = options
+	return query, nil
+}
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/gin-gonic/gin"
+)
+
+const mimeSchemaJSON = "application/schema+json"
+
+type ReportCatalogResponse struct {
+	Reports []framework.ReportType `json:"reports"`
+}
+
+// RouteReportCatalog lists the registered reports and serves the JSON Schema of their request body.
+func RouteReportCatalog(route *gin.Engine, catalog *framework.ReportCatalog) (routes gin.IRoutes) {
+	route.GET("/reports", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, ReportCatalogResponse{Reports: catalog.List()})
+	})
+
+	ReportCatalogRoute := route.GET("/reports/:id/schema", func(ctx *gin.Context) {
+		schema, ok := catalog.Schema(ctx.Param("id"))
+		if !ok {
+			ctx.Error(framework.NewNotFoundError(fmt.Sprintf("unknown report %q", ctx.Param("id")), nil))
+			return
+		}
+		body, err := json.Marshal(schema)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Data(http.StatusOK, mimeSchemaJSON, body)
+	})
+	return ReportCatalogRoute
+}
@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteReportJobs(route *gin.Engine, jobs *framework.JobManager) (routes gin.IRoutes) {
+	route.GET("/jobs/:id", func(ctx *gin.Context) {
+		job, ok := jobs.Get(ctx.Param("id"))
+		if !ok {
+			ctx.Error(framework.NewNotFoundError("job not found or expired", nil))
+			return
+		}
+		ctx.JSON(http.StatusOK, job)
+	})
+
+	ReportJobsRoute := route.GET("/jobs/:id/result", func(ctx *gin.Context) {
+		job, artifact, ok := jobs.Result(ctx.Param("id"))
+		if !ok {
+			ctx.Error(framework.NewNotFoundError("job not found or expired", nil))
+			return
+		}
+		if artifact == nil {
+			// Queued, running or failed jobs have nothing to download yet; report the status instead.
+			ctx.JSON(http.StatusConflict, job)
+			return
+		}
+		framework.RespondWithAttachment(ctx, artifact.ContentType, artifact.FileName, artifact.Payload)
+	})
+	return ReportJobsRoute
+}
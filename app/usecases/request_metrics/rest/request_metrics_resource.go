@@ -0,0 +1,15 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Javier-Godon/reports-rendering-go/framework"
+	"github.com/gin-gonic/gin"
+)
+
+func RouteRequestMetrics(route *gin.Engine, metrics *framework.RequestMetrics) (routes gin.IRoutes) {
+	RequestMetricsRoute := route.GET("/metrics/requests", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, metrics.Snapshot())
+	})
+	return RequestMetricsRoute
+}